@@ -0,0 +1,505 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package btree provides a persistent Map datastructure which keeps its keys
+// in sorted order, with the same API as the map in the sortedmap package. The
+// implementation is a B-tree with wide nodes, each holding many entries in a
+// single slice, so scanning a range of keys touches far fewer nodes than
+// walking a binary tree and the entries visited are laid out next to each
+// other in memory. The tradeoff is that each update copies a whole node at
+// each level of the tree rather than a single entry.
+package btree
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// These constants determine the width of the nodes of the tree. Every node
+// other than the root holds between minEntries and maxEntries entries, and
+// every node other than a leaf has one more child than it has entries.
+const (
+	maxEntries = 31
+	minEntries = maxEntries / 2
+)
+
+type entry[K any, V any] struct {
+	key   K
+	value V
+}
+
+// node is a node within the tree. The keys of the entries of a node are in
+// ascending order, and the keys within children[i] are between those of
+// entries[i-1] and entries[i]. Leaves have no children.
+type node[K any, V any] struct {
+	entries  []entry[K, V]
+	children []*node[K, V]
+}
+
+func (n *node[K, V]) isLeaf() bool {
+	return n.children == nil
+}
+
+// search returns the index of the entry of n with key, and true if there is
+// one. If there isn't, the index of the child which would contain key is
+// returned.
+func (n *node[K, V]) search(compare func(a, b K) int, key K) (int, bool) {
+	return slices.BinarySearchFunc(n.entries, key, func(e entry[K, V], key K) int {
+		return compare(e.key, key)
+	})
+}
+
+// clone returns a shallow copy of n, whose entries and children may be
+// modified without affecting n.
+func (n *node[K, V]) clone() *node[K, V] {
+	return &node[K, V]{
+		entries:  slices.Clone(n.entries),
+		children: slices.Clone(n.children),
+	}
+}
+
+// split splits n, which has one more entry than it may hold, at its median
+// entry, returning a new node for the entries on either side of it.
+func (n *node[K, V]) split() (*node[K, V], entry[K, V], *node[K, V]) {
+	var mid = len(n.entries) / 2
+	var left = &node[K, V]{entries: n.entries[:mid:mid]}
+	var right = &node[K, V]{entries: slices.Clone(n.entries[mid+1:])}
+	if !n.isLeaf() {
+		left.children = n.children[: mid+1 : mid+1]
+		right.children = slices.Clone(n.children[mid+1:])
+	}
+	return left, n.entries[mid], right
+}
+
+// assoc returns a new node with key associated to value within the subtree
+// rooted at n, and true if key wasn't already present. If the new node would
+// have too many entries it is split in two, in which case the node for the
+// entries before the median entry, the median entry and the node for the
+// entries after it are returned instead, with the last node being non-nil.
+func (n *node[K, V]) assoc(compare func(a, b K) int, key K, value V) (*node[K, V], entry[K, V], *node[K, V], bool) {
+	var none entry[K, V]
+
+	var i, found = n.search(compare, key)
+	if found {
+		var updated = n.clone()
+		updated.entries[i] = entry[K, V]{key, value}
+		return updated, none, nil, false
+	}
+
+	var updated = n.clone()
+	if n.isLeaf() {
+		updated.entries = slices.Insert(updated.entries, i, entry[K, V]{key, value})
+	} else {
+		var child, median, right, added = n.children[i].assoc(compare, key, value)
+		updated.children[i] = child
+		if right != nil {
+			updated.entries = slices.Insert(updated.entries, i, median)
+			updated.children = slices.Insert(updated.children, i+1, right)
+		}
+		if !added {
+			return updated, none, nil, false
+		}
+	}
+
+	if len(updated.entries) > maxEntries {
+		var left, median, right = updated.split()
+		return left, median, right, true
+	}
+	return updated, none, nil, true
+}
+
+// dissoc returns a new node without key within the subtree rooted at n, and
+// true if there was an entry for key to remove. If there wasn't, n itself is
+// returned. The returned node may have too few entries, which the caller must
+// fix.
+func (n *node[K, V]) dissoc(compare func(a, b K) int, key K) (*node[K, V], bool) {
+	var i, found = n.search(compare, key)
+	if n.isLeaf() {
+		if !found {
+			return n, false
+		}
+		var updated = n.clone()
+		updated.entries = slices.Delete(updated.entries, i, i+1)
+		return updated, true
+	}
+
+	var updated *node[K, V]
+	if found {
+		// The entry is replaced by the greatest entry before it, which is
+		// always within a leaf.
+		var max, child = n.children[i].popMax()
+		updated = n.clone()
+		updated.entries[i] = max
+		updated.children[i] = child
+	} else {
+		var child, removed = n.children[i].dissoc(compare, key)
+		if !removed {
+			return n, false
+		}
+		updated = n.clone()
+		updated.children[i] = child
+	}
+
+	updated.rebalance(i)
+	return updated, true
+}
+
+// popMax returns the entry with the greatest key within the subtree rooted at
+// n, and a new node for the subtree without it. The returned node may have too
+// few entries, which the caller must fix.
+func (n *node[K, V]) popMax() (entry[K, V], *node[K, V]) {
+	var updated = n.clone()
+	if n.isLeaf() {
+		var last = len(updated.entries) - 1
+		var max = updated.entries[last]
+		updated.entries = updated.entries[:last]
+		return max, updated
+	}
+
+	var last = len(updated.children) - 1
+	var max, child = n.children[last].popMax()
+	updated.children[last] = child
+	updated.rebalance(last)
+	return max, updated
+}
+
+// rebalance ensures that children[i] of n, which must already be a copy of
+// the original node, has enough entries after one has been removed from it.
+// Entries are moved into it from a sibling if the sibling has any to spare,
+// otherwise it is merged with the sibling and the entry separating them.
+func (n *node[K, V]) rebalance(i int) {
+	var child = n.children[i]
+	if len(child.entries) >= minEntries {
+		return
+	}
+
+	if i > 0 && len(n.children[i-1].entries) > minEntries {
+		// Rotate the last entry of the left sibling through the parent.
+		var left = n.children[i-1].clone()
+		var last = len(left.entries) - 1
+		child = child.clone()
+		child.entries = slices.Insert(child.entries, 0, n.entries[i-1])
+		n.entries[i-1] = left.entries[last]
+		left.entries = left.entries[:last]
+		if !left.isLeaf() {
+			child.children = slices.Insert(child.children, 0, left.children[last+1])
+			left.children = left.children[:last+1]
+		}
+		n.children[i-1], n.children[i] = left, child
+		return
+	}
+
+	if i+1 < len(n.children) && len(n.children[i+1].entries) > minEntries {
+		// Rotate the first entry of the right sibling through the parent.
+		var right = n.children[i+1].clone()
+		child = child.clone()
+		child.entries = append(child.entries, n.entries[i])
+		n.entries[i] = right.entries[0]
+		right.entries = slices.Delete(right.entries, 0, 1)
+		if !right.isLeaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+		n.children[i], n.children[i+1] = child, right
+		return
+	}
+
+	// Merge with a sibling, using the left one if there is one.
+	if i > 0 {
+		i -= 1
+	}
+	var left, right = n.children[i], n.children[i+1]
+	var merged = &node[K, V]{
+		entries: slices.Concat(left.entries, []entry[K, V]{n.entries[i]}, right.entries),
+	}
+	if !left.isLeaf() {
+		merged.children = slices.Concat(left.children, right.children)
+	}
+	n.entries = slices.Delete(n.entries, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+	n.children[i] = merged
+}
+
+// find returns the entry for key within the subtree rooted at n, or nil if
+// there isn't one.
+func (n *node[K, V]) find(compare func(a, b K) int, key K) *entry[K, V] {
+	for n != nil {
+		var i, found = n.search(compare, key)
+		if found {
+			return &n.entries[i]
+		}
+		if n.isLeaf() {
+			return nil
+		}
+		n = n.children[i]
+	}
+	return nil
+}
+
+// forEach calls yield with each entry within the subtree rooted at n in
+// order, starting from the first entry with a key not less than lo, if there
+// is one, and stopping before the first entry with a key not less than hi, if
+// there is one. Iteration stops early if yield returns false, in which case
+// forEach also returns false.
+func (n *node[K, V]) forEach(compare func(a, b K) int, lo, hi *K, yield func(*entry[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	var i = 0
+	if lo != nil {
+		i, _ = n.search(compare, *lo)
+	}
+	for ; i <= len(n.entries); i += 1 {
+		if !n.isLeaf() && !n.children[i].forEach(compare, lo, hi, yield) {
+			return false
+		}
+		if i == len(n.entries) {
+			break
+		}
+		if hi != nil && compare(n.entries[i].key, *hi) >= 0 {
+			return true
+		}
+		if !yield(&n.entries[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// pack returns the number of entries each of the nodes at one level of the
+// tree should hold when count entries are split across as few nodes as
+// possible, with one entry between each pair of nodes being moved up to the
+// level above. Since n nodes hold at most n*maxEntries entries with n-1
+// between them, count entries need (count+1)/(maxEntries+1) nodes rounded up.
+func pack(count int) []int {
+	var nodes = (count + maxEntries + 1) / (maxEntries + 1)
+	if nodes == 0 {
+		nodes = 1
+	}
+	var sizes = make([]int, nodes)
+	var base, extra = (count - nodes + 1) / nodes, (count - nodes + 1) % nodes
+	for i := range sizes {
+		sizes[i] = base
+		if i < extra {
+			sizes[i] += 1
+		}
+	}
+	return sizes
+}
+
+// build returns the root of a tree holding entries, which must be in
+// ascending order of key. The tree is built a level at a time from the leaves
+// up, with every node other than the root at least half full.
+func build[K any, V any](entries []entry[K, V]) *node[K, V] {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var nodes []*node[K, V]
+	var separators []entry[K, V]
+	for _, size := range pack(len(entries)) {
+		if len(nodes) > 0 {
+			separators = append(separators, entries[0])
+			entries = entries[1:]
+		}
+		nodes = append(nodes, &node[K, V]{entries: entries[:size:size]})
+		entries = entries[size:]
+	}
+
+	for len(nodes) > 1 {
+		var children, above = nodes, separators
+		nodes, separators = nil, nil
+		for _, size := range pack(len(above)) {
+			if len(nodes) > 0 {
+				separators = append(separators, above[0])
+				above = above[1:]
+			}
+			nodes = append(nodes, &node[K, V]{
+				entries:  above[:size:size],
+				children: children[: size+1 : size+1],
+			})
+			above, children = above[size:], children[size+1:]
+		}
+	}
+	return nodes[0]
+}
+
+// Map is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Assoc'ing a key to a Map, the previous version of that
+// Map can be used in more operations and referenced without having been
+// mutated from any operations it was used as input for. Only the nodes along
+// the path to the key are copied by each operation, while the rest are shared
+// between both versions.
+//
+// Like the map in the sortedmap package, a Map must be created with New,
+// NewFunc or one of the FromSorted functions before it is used, as its zero
+// value has no way to compare keys.
+type Map[K any, V any] struct {
+	count   int
+	root    *node[K, V]
+	compare func(a, b K) int
+}
+
+// New creates a new empty persistent map with keys sorted in ascending order.
+func New[K cmp.Ordered, V any]() Map[K, V] {
+	return NewFunc[K, V](cmp.Compare[K])
+}
+
+// NewFunc creates a new empty persistent map with keys sorted in ascending
+// order according to compare, which returns a negative number when a < b, a
+// positive number when a > b and zero when a == b.
+func NewFunc[K any, V any](compare func(a, b K) int) Map[K, V] {
+	return Map[K, V]{compare: compare}
+}
+
+// FromSorted creates a new persistent map with keys sorted in ascending order
+// containing each key and value of seq, which must yield keys in strictly
+// ascending order. Rather than inserting one entry at a time, the tree is
+// built directly from the entries with each node filled as far as possible,
+// which takes O(n) time.
+func FromSorted[K cmp.Ordered, V any](seq iter.Seq2[K, V]) Map[K, V] {
+	return FromSortedFunc(cmp.Compare[K], seq)
+}
+
+// FromSortedFunc is like FromSorted, but with keys sorted according to
+// compare as for NewFunc. It panics if seq doesn't yield keys in strictly
+// ascending order according to compare.
+func FromSortedFunc[K any, V any](compare func(a, b K) int, seq iter.Seq2[K, V]) Map[K, V] {
+	var entries []entry[K, V]
+	for key, value := range seq {
+		if len(entries) > 0 && compare(entries[len(entries)-1].key, key) >= 0 {
+			panic(fmt.Sprintf("btree: key %v out of order after %v", key, entries[len(entries)-1].key))
+		}
+		entries = append(entries, entry[K, V]{key, value})
+	}
+
+	return Map[K, V]{count: len(entries), root: build(entries), compare: compare}
+}
+
+// Len returns the number of entries in m.
+func (m Map[K, V]) Len() int {
+	return m.count
+}
+
+// Get returns the value associated with key, or the zero value if key isn't
+// present in m.
+func (m Map[K, V]) Get(key K) V {
+	var value, _ = m.GetOK(key)
+	return value
+}
+
+// GetOK returns the value associated with key and true, or the zero value and
+// false if key isn't present in m.
+func (m Map[K, V]) GetOK(key K) (V, bool) {
+	if e := m.root.find(m.compare, key); e != nil {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Contains returns true if key is present in m.
+func (m Map[K, V]) Contains(key K) bool {
+	return m.root.find(m.compare, key) != nil
+}
+
+// Assoc returns a new map with key associated to value, replacing any value
+// key was already associated to.
+func (m Map[K, V]) Assoc(key K, value V) Map[K, V] {
+	if m.root == nil {
+		return Map[K, V]{
+			count:   1,
+			root:    &node[K, V]{entries: []entry[K, V]{{key, value}}},
+			compare: m.compare,
+		}
+	}
+
+	var root, median, right, added = m.root.assoc(m.compare, key, value)
+	if right != nil {
+		// The root was split, so the tree grows by a level.
+		root = &node[K, V]{
+			entries:  []entry[K, V]{median},
+			children: []*node[K, V]{root, right},
+		}
+	}
+
+	var count = m.count
+	if added {
+		count += 1
+	}
+	return Map[K, V]{count: count, root: root, compare: m.compare}
+}
+
+// Dissoc returns a new map without key. If key isn't present in m, m itself is
+// returned.
+func (m Map[K, V]) Dissoc(key K) Map[K, V] {
+	if m.root == nil {
+		return m
+	}
+
+	var root, removed = m.root.dissoc(m.compare, key)
+	if !removed {
+		return m
+	}
+	if len(root.entries) == 0 {
+		// The root was emptied by a merge of its children, so the tree
+		// shrinks by a level.
+		if root.isLeaf() {
+			root = nil
+		} else {
+			root = root.children[0]
+		}
+	}
+
+	return Map[K, V]{count: m.count - 1, root: root, compare: m.compare}
+}
+
+// All returns an iterator over each key and value of m in ascending order of
+// key.
+func (m Map[K, V]) All() iter.Seq2[K, V] {
+	return m.iterate(nil, nil)
+}
+
+// Range returns an iterator over each key and value of m with a key from lo
+// (inclusive) to hi (exclusive), in ascending order of key.
+func (m Map[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return m.iterate(&lo, &hi)
+}
+
+func (m Map[K, V]) iterate(lo, hi *K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.forEach(m.compare, lo, hi, func(e *entry[K, V]) bool {
+			return yield(e.key, e.value)
+		})
+	}
+}
+
+// String returns a representation of a map in the same form as a Go map when
+// using the "%v" formatting verb as in the standard fmt package, with the
+// entries in ascending order of key:
+//
+//	With no entries: map[]
+//	With one entry: map[a:1]
+//	With more than one entry: map[a:1 b:2]
+func (m Map[K, V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("map[")
+	var first = true
+	for key, value := range m.All() {
+		if !first {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%v:%v", key, value)
+		first = false
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,289 @@
+package btree_test
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/btree"
+)
+
+func keysOf[K any, V any](m btree.Map[K, V]) []K {
+	var keys []K
+	for key := range m.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestMapEmpty(t *testing.T) {
+	var m = btree.New[string, int]()
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, ok := m.GetOK("a"); got != 0 || ok {
+		t.Fatalf("got GetOK(a)=%d, %t, want GetOK(a)=0, false", got, ok)
+	}
+	if got, want := m.Dissoc("a").Len(), 0; got != want {
+		t.Fatalf("got Len()=%d after Dissoc, want Len()=%d", got, want)
+	}
+	if got, want := m.String(), "map[]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMapAssoc(t *testing.T) {
+	var m1 = btree.New[string, int]().Assoc("b", 2).Assoc("a", 1).Assoc("c", 3)
+	var m2 = m1.Assoc("a", 4)
+
+	if got, want := m2.String(), "map[a:4 b:2 c:3]"; got != want {
+		t.Fatalf("got m2 %s, want %s", got, want)
+	}
+	if got, want := m1.String(), "map[a:1 b:2 c:3]"; got != want {
+		t.Fatalf("got m1 %s, want %s", got, want)
+	}
+	if got, want := m2.Len(), 3; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+}
+
+func TestMapModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(11, 12))
+	var model = map[int]int{}
+	var m = btree.New[int, int]()
+	var versions []btree.Map[int, int]
+	var models []map[int]int
+
+	for i := 0; i < 50000; i++ {
+		var key = r.IntN(5000)
+		if r.IntN(3) == 0 {
+			delete(model, key)
+			m = m.Dissoc(key)
+		} else {
+			model[key] = i
+			m = m.Assoc(key, i)
+		}
+
+		if i%5000 == 0 {
+			versions = append(versions, m)
+			models = append(models, maps.Clone(model))
+		}
+	}
+
+	// Empty the map again to exercise the tree shrinking.
+	for key := range model {
+		m = m.Dissoc(key)
+	}
+	versions = append(versions, m)
+	models = append(models, map[int]int{})
+
+	// Every version must still match the model at the time it was made, with
+	// its keys in order.
+	for i, version := range versions {
+		if got, want := version.Len(), len(models[i]); got != want {
+			t.Fatalf("got version %d Len()=%d, want Len()=%d", i, got, want)
+		}
+		for key := 0; key < 5000; key++ {
+			var got, ok = version.GetOK(key)
+			if want, present := models[i][key]; got != want || ok != present {
+				t.Fatalf("got version %d GetOK(%d)=%d, %t, want GetOK(%d)=%d, %t", i, key, got, ok, key, want, present)
+			}
+		}
+		var want = slices.Sorted(maps.Keys(models[i]))
+		if got := keysOf(version); !slices.Equal(got, want) {
+			t.Fatalf("got version %d keys %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestMapRange(t *testing.T) {
+	var m = btree.New[int, string]()
+	for i := 0; i < 1000; i += 2 {
+		m = m.Assoc(i, fmt.Sprint(i))
+	}
+
+	var testCases = []struct {
+		name   string
+		lo, hi int
+		want   []int
+	}{
+		{"Empty", 10, 10, nil},
+		{"Reversed", 20, 10, nil},
+		{"Inclusive", 10, 15, []int{10, 12, 14}},
+		{"Exclusive", 11, 16, []int{12, 14}},
+		{"Before", -10, 3, []int{0, 2}},
+		{"After", 995, 2000, []int{996, 998}},
+		{"Outside", 2000, 3000, nil},
+		{"Across nodes", 55, 75, []int{56, 58, 60, 62, 64, 66, 68, 70, 72, 74}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []int
+			for key, value := range m.Range(tc.lo, tc.hi) {
+				if value != fmt.Sprint(key) {
+					t.Fatalf("got value %q for key %d, want %q", value, key, fmt.Sprint(key))
+				}
+				got = append(got, key)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapRangeBreak(t *testing.T) {
+	var m = btree.New[int, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Assoc(i, i)
+	}
+
+	var got []int
+	for key := range m.Range(10, 90) {
+		if key == 13 {
+			break
+		}
+		got = append(got, key)
+	}
+	if want := []int{10, 11, 12}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFromSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 31, 32, 33, 100, 1000, 33000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			var seq = func(yield func(int, int) bool) {
+				for i := 0; i < n; i++ {
+					if !yield(i*2, i) {
+						return
+					}
+				}
+			}
+			var m = btree.FromSorted(seq)
+			if got, want := m.Len(), n; got != want {
+				t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+			}
+			for i := 0; i < n; i++ {
+				if got, want := m.Get(i*2), i; got != want {
+					t.Fatalf("got Get(%d)=%d, want Get(%d)=%d", i*2, got, i*2, want)
+				}
+			}
+
+			// The bulk loaded tree must remain valid as it is updated.
+			for i := 0; i < n; i += 3 {
+				m = m.Dissoc(i*2).Assoc(i*2+1, i)
+			}
+			var want []int
+			for i := 0; i < n; i++ {
+				if i%3 == 0 {
+					want = append(want, i*2+1)
+				} else {
+					want = append(want, i*2)
+				}
+			}
+			if got := keysOf(m); !slices.Equal(got, want) {
+				t.Fatalf("got keys %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFromSortedBalanced(t *testing.T) {
+	// Counts just around multiples of 32 are where each level is split into
+	// one more node, at the leaves and, for the larger counts, above them.
+	var counts []int
+	for _, k := range []int{1, 2, 3, 32, 33, 64, 1024, 1025} {
+		counts = append(counts, k*32-1, k*32, k*32+1)
+	}
+
+	for _, n := range counts {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			var seq = func(yield func(int, int) bool) {
+				for i := 0; i < n; i++ {
+					if !yield(i, i) {
+						return
+					}
+				}
+			}
+			for _, size := range btree.NodeSizes(btree.FromSorted(seq)) {
+				if size < btree.MinEntries || size > btree.MaxEntries {
+					t.Fatalf("got node with %d entries, want between %d and %d", size, btree.MinEntries, btree.MaxEntries)
+				}
+			}
+		})
+	}
+}
+
+func TestFromSortedPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	btree.FromSorted(func(yield func(int, int) bool) {
+		_ = yield(2, 2) && yield(1, 1)
+	})
+}
+
+func TestNewFunc(t *testing.T) {
+	var m = btree.NewFunc[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(b), strings.ToLower(a))
+	})
+	m = m.Assoc("a", 1).Assoc("C", 3).Assoc("b", 2).Assoc("A", 4)
+
+	if got, want := m.String(), "map[C:3 b:2 A:4]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := m.Get("c"), 3; got != want {
+		t.Fatalf("got Get(c)=%d, want Get(c)=%d", got, want)
+	}
+}
+
+func BenchmarkMapAssoc(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			var m = btree.New[int, int]()
+			for i := 0; i < n; i++ {
+				m = m.Assoc(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m = m.Assoc(i%n, i)
+			}
+		})
+	}
+}
+
+func BenchmarkMapGet(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			var m = btree.New[int, int]()
+			for i := 0; i < n; i++ {
+				m = m.Assoc(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = m.Get(i % n)
+			}
+		})
+	}
+}
+
+func BenchmarkMapRange(b *testing.B) {
+	var m = btree.New[int, int]()
+	for i := 0; i < 1000000; i++ {
+		m = m.Assoc(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var lo = i % 990000
+		for range m.Range(lo, lo+10000) {
+		}
+	}
+}
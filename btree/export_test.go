@@ -0,0 +1,29 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package btree
+
+// NodeSizes returns the number of entries in every node of m other than the
+// root, so tests can check the tree stays balanced.
+func NodeSizes[K any, V any](m Map[K, V]) []int {
+	var sizes []int
+	var walk func(n *node[K, V])
+	walk = func(n *node[K, V]) {
+		if n != m.root {
+			sizes = append(sizes, len(n.entries))
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	if m.root != nil {
+		walk(m.root)
+	}
+	return sizes
+}
+
+const (
+	MinEntries = minEntries
+	MaxEntries = maxEntries
+)
@@ -0,0 +1,61 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package cbor encodes persistent data structures in the Concise Binary Object
+// Representation (CBOR) of RFC 8949, and decodes CBOR back into persistent
+// data structures. Encoding may optionally be deterministic, following the
+// core deterministic encoding requirements of the RFC, so that equal values
+// always encode to the same bytes no matter the order their entries were
+// added in.
+//
+// Values are encoded according to their type:
+//
+//   - nil, booleans, integers, floating point numbers and strings as their
+//     CBOR counterparts, with integers and lengths in their shortest form.
+//   - Byte slices as byte strings, time.Time values as standard date/time
+//     strings (tag 0) and Tag values as tagged data items.
+//   - Maps with struct{} values as sets of their keys (tag 258), other maps
+//     as maps, and vectors and lists as arrays. As with the paths package,
+//     maps are any values with Len(), GetOK(key), Assoc(key, value) and All()
+//     methods, such as those of the maps, sortedmap and btree packages.
+//     Vectors and lists are any values with Len() and All() methods, such as
+//     those of the vectors and lists packages.
+//   - Go slices, arrays and maps as arrays and maps, and pointers as the value
+//     they point to.
+//   - Values of a type with a codec registered in the codec package as their
+//     representation.
+//
+// Decoding produces values of dynamic types: nil, bool, int64, uint64 for
+// integers too large for an int64, float64, string, []byte, time.Time, Tag,
+// vectors.Vector[any] for arrays, maps.Map[any, any] for maps and
+// maps.Map[any, struct{}] for sets. Since CBOR has no lists, those are decoded
+// as vectors.
+package cbor
+
+// Tag is a CBOR tagged data item other than those decoded as time.Time values
+// or sets, holding the number of the tag and the content it applies to.
+type Tag struct {
+	Number  uint64
+	Content any
+}
+
+// The major types of CBOR data items, in the top three bits of their first
+// byte.
+const (
+	majorUint   = 0 << 5
+	majorNegInt = 1 << 5
+	majorBytes  = 2 << 5
+	majorText   = 3 << 5
+	majorArray  = 4 << 5
+	majorMap    = 5 << 5
+	majorTag    = 6 << 5
+	majorSimple = 7 << 5
+)
+
+// The numbers of the tags with a meaning understood by this package.
+const (
+	tagDateTime = 0
+	tagEpoch    = 1
+	tagSet      = 258
+)
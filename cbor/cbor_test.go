@@ -0,0 +1,297 @@
+package cbor_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/toddgaunt/persistent/cbor"
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/sortedmap"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestMarshal(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		value any
+		want  []byte
+	}{
+		{"Nil", nil, []byte{0xf6}},
+		{"Bool", false, []byte{0xf4}},
+		{"SmallInt", 23, []byte{0x17}},
+		{"Uint8", 24, []byte{0x18, 0x18}},
+		{"Uint16", 1000, []byte{0x19, 0x03, 0xe8}},
+		{"Uint32", 1000000, []byte{0x1a, 0x00, 0x0f, 0x42, 0x40}},
+		{"Uint64", uint64(math.MaxUint64), []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"NegativeInt", -1000, []byte{0x39, 0x03, 0xe7}},
+		{"Float32", float32(1.5), []byte{0xfa, 0x3f, 0xc0, 0x00, 0x00}},
+		{"Float64", 1.5, []byte{0xfb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"String", "IETF", []byte{0x64, 'I', 'E', 'T', 'F'}},
+		{"Bytes", []byte{1, 2, 3, 4}, []byte{0x44, 1, 2, 3, 4}},
+		{"Vector", vectors.New[any](1, vectors.New(2, 3)), []byte{0x82, 0x01, 0x82, 0x02, 0x03}},
+		{"List", lists.New("a"), []byte{0x81, 0x61, 'a'}},
+		{"Map", sortedmap.New[string, int]().Assoc("a", 1).Assoc("b", 2), []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'b', 0x02}},
+		{"Set", maps.New[int, struct{}]().Assoc(5, struct{}{}), []byte{0xd9, 0x01, 0x02, 0x81, 0x05}},
+		{"Time", time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC), append([]byte{0xc0, 0x74}, "2013-03-21T20:04:00Z"...)},
+		{"Tag", cbor.Tag{Number: 32, Content: "a"}, []byte{0xd8, 0x20, 0x61, 'a'}},
+		{"GoSlice", []string{"a"}, []byte{0x81, 0x61, 'a'}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = cbor.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("got % x, want % x", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		value any
+		want  []byte
+	}{
+		{"Half", 1.5, []byte{0xf9, 0x3e, 0x00}},
+		{"HalfSubnormal", 5.960464477539063e-8, []byte{0xf9, 0x00, 0x01}},
+		{"NegativeZero", math.Copysign(0, -1), []byte{0xf9, 0x80, 0x00}},
+		{"Infinity", math.Inf(1), []byte{0xf9, 0x7c, 0x00}},
+		{"NaN", math.NaN(), []byte{0xf9, 0x7e, 0x00}},
+		{"Single", 100000.0, []byte{0xfa, 0x47, 0xc3, 0x50, 0x00}},
+		{"Double", 1.1, []byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}},
+		{"Float32", float32(1.5), []byte{0xf9, 0x3e, 0x00}},
+		{"Set", maps.New[int, struct{}]().Assoc(300, struct{}{}).Assoc(2, struct{}{}), []byte{0xd9, 0x01, 0x02, 0x82, 0x02, 0x19, 0x01, 0x2c}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = cbor.MarshalCanonical(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("got % x, want % x", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarshalCanonicalOrder(t *testing.T) {
+	// The keys of the example in section 4.2.1 of RFC 8949, in the order they
+	// must be encoded in.
+	var keys = []any{10, 100, -1, "z", "aa", false}
+	var want = []byte{0xa6,
+		0x0a, 0x00,
+		0x18, 0x64, 0x01,
+		0x20, 0x02,
+		0x61, 'z', 0x03,
+		0x62, 'a', 'a', 0x04,
+		0xf4, 0x05,
+	}
+
+	var forward, backward = maps.New[any, int](), maps.New[any, int]()
+	for i := range keys {
+		forward = forward.Assoc(keys[i], i)
+		backward = backward.Assoc(keys[len(keys)-1-i], len(keys)-1-i)
+	}
+
+	for _, m := range []maps.Map[any, int]{forward, backward} {
+		var got, err = cbor.MarshalCanonical(m)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("got % x, want % x", got, want)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var testCases = []struct {
+		name string
+		data []byte
+		want any
+	}{
+		{"Uint", []byte{0x19, 0x03, 0xe8}, int64(1000)},
+		{"LargeUint", []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint64(math.MaxUint64)},
+		{"NegativeInt", []byte{0x3b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, int64(math.MinInt64)},
+		{"Half", []byte{0xf9, 0x3c, 0x00}, 1.0},
+		{"Single", []byte{0xfa, 0x47, 0xc3, 0x50, 0x00}, 100000.0},
+		{"Undefined", []byte{0xf7}, nil},
+		{"Text", []byte{0x62, 0xc3, 0xbc}, "ü"},
+		{"IndefiniteText", []byte{0x7f, 0x65, 's', 't', 'r', 'e', 'a', 0x64, 'm', 'i', 'n', 'g', 0xff}, "streaming"},
+		{"Epoch", []byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0}, time.Unix(1363896240, 0).UTC()},
+		{"UnknownTag", []byte{0xd8, 0x20, 0x61, 'a'}, cbor.Tag{Number: 32, Content: "a"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = cbor.Unmarshal(tc.data)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalIndefinite(t *testing.T) {
+	// [_ 1, [2, 3], [_ 4, 5]]
+	var got, err = cbor.Unmarshal([]byte{0x9f, 0x01, 0x82, 0x02, 0x03, 0x9f, 0x04, 0x05, 0xff, 0xff})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := got.(vectors.Vector[any]).String(), "[1 [2 3] [4 5]]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	// {_ "a": 1, "b": h'0102' h'03'}
+	got, err = cbor.Unmarshal([]byte{0xbf, 0x61, 'a', 0x01, 0x61, 'b', 0x5f, 0x42, 0x01, 0x02, 0x41, 0x03, 0xff, 0xff})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	var m = got.(maps.Map[any, any])
+	if got, want := m.Get("a"), int64(1); got != want {
+		t.Fatalf("got a=%v, want a=%v", got, want)
+	}
+	if got, want := m.Get("b").([]byte), []byte{1, 2, 3}; !bytes.Equal(got, want) {
+		t.Fatalf("got b=% x, want b=% x", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var big = vectors.New[int]()
+	for i := 0; i < 70000; i++ {
+		big = big.Conj(i - 35000)
+	}
+	var m = maps.New[any, any]()
+	for i := 0; i < 100; i++ {
+		m = m.Assoc(int64(i), float64(i)/4)
+	}
+	var when = time.Date(2024, 2, 29, 12, 30, 15, 123456789, time.UTC)
+
+	var testCases = []struct {
+		name  string
+		value any
+		check func(t *testing.T, got any)
+	}{
+		{"BigVector", big, func(t *testing.T, got any) {
+			var v = got.(vectors.Vector[any])
+			if v.Len() != big.Len() || v.Nth(0) != int64(-35000) || v.Nth(69999) != int64(34999) {
+				t.Fatalf("got vector of length %d, want %d", v.Len(), big.Len())
+			}
+		}},
+		{"Map", m, func(t *testing.T, got any) {
+			if !maps.Equal(got.(maps.Map[any, any]), m) {
+				t.Fatalf("got %v, want %v", got, m)
+			}
+		}},
+		{"Set", maps.New[string, struct{}]().Assoc("a", struct{}{}), func(t *testing.T, got any) {
+			var s = got.(maps.Map[any, struct{}])
+			if s.Len() != 1 || !s.Contains("a") {
+				t.Fatalf("got %v, want map[a:{}]", s)
+			}
+		}},
+		{"Time", when, func(t *testing.T, got any) {
+			if !got.(time.Time).Equal(when) {
+				t.Fatalf("got %v, want %v", got, when)
+			}
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		for _, marshal := range []func(any) ([]byte, error){cbor.Marshal, cbor.MarshalCanonical} {
+			t.Run(tc.name, func(t *testing.T) {
+				var data, err = marshal(tc.value)
+				if err != nil {
+					t.Fatalf("got error %v, want nil", err)
+				}
+				var got any
+				if got, err = cbor.Unmarshal(data); err != nil {
+					t.Fatalf("got error %v, want nil", err)
+				}
+				tc.check(t, got)
+			})
+		}
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		value any
+	}{
+		{"Func", func() {}},
+		{"Channel", vectors.New(make(chan int))},
+		{"Complex", complex(1, 2)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if data, err := cbor.Marshal(tc.value); err == nil {
+				t.Fatalf("got % x and nil error, want an error", data)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var testCases = []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", nil},
+		{"Trailing", []byte{0x01, 0x02}},
+		{"ShortString", []byte{0x63, 'a'}},
+		{"ShortArray", []byte{0x82, 0x01}},
+		{"HugeLength", []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"ReservedInfo", []byte{0x1c}},
+		{"IndefiniteInt", []byte{0x1f}},
+		{"Break", []byte{0xff}},
+		{"BreakInDefinite", []byte{0x9f, 0x82, 0x01, 0xff, 0xff}},
+		{"NegativeIntRange", []byte{0x3b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"InvalidUTF8", []byte{0x61, 0xff}},
+		{"MixedChunks", []byte{0x5f, 0x61, 'a', 0xff}},
+		{"BytesKey", []byte{0xa1, 0x40, 0x01}},
+		{"DuplicateKey", []byte{0xa2, 0x01, 0x01, 0x01, 0x02}},
+		{"MissingValue", []byte{0xbf, 0x01, 0xff}},
+		{"DateTime", []byte{0xc0, 0x61, 'a'}},
+		{"Epoch", []byte{0xc1, 0x61, 'a'}},
+		{"Set", []byte{0xd9, 0x01, 0x02, 0x01}},
+		{"SimpleValue", []byte{0xf0}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if v, err := cbor.Unmarshal(tc.data); err == nil {
+				t.Fatalf("got %v and nil error, want an error", v)
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalCanonical(b *testing.B) {
+	var m = maps.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		m = m.Assoc(i, "value")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = cbor.MarshalCanonical(m)
+	}
+}
@@ -0,0 +1,37 @@
+package cbor_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/toddgaunt/persistent/cbor"
+	"github.com/toddgaunt/persistent/codec"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+// date is encoded by its codec as a string with only the day, rather than as
+// a date/time as time.Time is encoded.
+type date struct{ time.Time }
+
+func init() {
+	codec.Register(
+		func(d date) (string, error) { return d.Format(time.DateOnly), nil },
+		func(s string) (date, error) {
+			var t, err = time.Parse(time.DateOnly, s)
+			return date{t}, err
+		},
+	)
+}
+
+func TestMarshalCodec(t *testing.T) {
+	var day = date{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}
+	var got, err = cbor.Marshal(vectors.New[any](day, &day))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	var want = append(append([]byte{0x82, 0x6a}, "2024-01-02"...), append([]byte{0x6a}, "2024-01-02"...)...)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got % x, want % x", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+	"unicode/utf8"
+
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+var errShort = errors.New("cbor: unexpected end of data")
+
+// Unmarshal parses the single CBOR data item in data, returning it as a value
+// of one of the types described in the package documentation. Both definite
+// and indefinite length items are accepted.
+func Unmarshal(data []byte) (any, error) {
+	var d = decoder{data: data}
+	var v, err = d.item()
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) > 0 {
+		return nil, fmt.Errorf("cbor: %d bytes of data after item", len(d.data))
+	}
+	return v, nil
+}
+
+type decoder struct {
+	data []byte
+}
+
+// next returns the next n bytes of data.
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)) {
+		return nil, errShort
+	}
+	var b = d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+// head reads the head of a data item, returning its major type, additional
+// information and argument. The argument is zero for indefinite lengths.
+func (d *decoder) head() (byte, byte, uint64, error) {
+	var b, err = d.next(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	var major, info = b[0] & 0xe0, b[0] & 0x1f
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		if b, err = d.next(1 << (info - 24)); err != nil {
+			return 0, 0, 0, err
+		}
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return major, info, n, nil
+	case info == 31 && major != majorUint && major != majorNegInt && major != majorTag:
+		return major, info, 0, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: invalid additional information %d for major type %d", info, major>>5)
+	}
+}
+
+// length checks that a definite length of n items of at least one byte each
+// doesn't exceed the data left, so it can't be used to allocate too much.
+func (d *decoder) length(n uint64) error {
+	if n > uint64(len(d.data)) {
+		return errShort
+	}
+	return nil
+}
+
+func (d *decoder) item() (any, error) {
+	var major, info, n, err = d.head()
+	if err != nil {
+		return nil, err
+	}
+	var indefinite = info == 31
+
+	switch major {
+	case majorUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	case majorNegInt:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("cbor: negative integer -1-%d out of range", n)
+		}
+		return -1 - int64(n), nil
+	case majorBytes, majorText:
+		var b []byte
+		if b, err = d.str(major, n, indefinite); err != nil {
+			return nil, err
+		}
+		if major == majorBytes {
+			return b, nil
+		}
+		if !utf8.Valid(b) {
+			return nil, fmt.Errorf("cbor: text string is not valid UTF-8")
+		}
+		return string(b), nil
+	case majorArray:
+		var b vectors.Builder[any]
+		if err = d.items(n, indefinite, func(v any) error { b.Append(v); return nil }); err != nil {
+			return nil, err
+		}
+		return b.Vector(), nil
+	case majorMap:
+		return d.mapOf(n, indefinite)
+	case majorTag:
+		return d.tag(n)
+	default:
+		return d.simple(info, n)
+	}
+}
+
+// str reads the contents of a byte or text string, joining the chunks of an
+// indefinite length string.
+func (d *decoder) str(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		var b, err = d.next(n)
+		return append([]byte{}, b...), err
+	}
+
+	var joined = []byte{}
+	for {
+		var chunkMajor, info, n, err = d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor == majorSimple && info == 31 {
+			return joined, nil
+		}
+		if chunkMajor != major || info == 31 {
+			return nil, fmt.Errorf("cbor: invalid chunk of an indefinite length string")
+		}
+		var b []byte
+		if b, err = d.next(n); err != nil {
+			return nil, err
+		}
+		joined = append(joined, b...)
+	}
+}
+
+// items reads n items, or items up to a break if indefinite is true, calling
+// add with each.
+func (d *decoder) items(n uint64, indefinite bool, add func(any) error) error {
+	if !indefinite {
+		if err := d.length(n); err != nil {
+			return err
+		}
+	}
+	for i := uint64(0); indefinite || i < n; i += 1 {
+		if indefinite && len(d.data) > 0 && d.data[0] == majorSimple|31 {
+			d.data = d.data[1:]
+			return nil
+		}
+		var v, err = d.item()
+		if err != nil {
+			return err
+		}
+		if err = add(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) mapOf(n uint64, indefinite bool) (any, error) {
+	var m = maps.New[any, any]()
+	var key any
+	var hasKey = false
+	var err = d.items(2*n, indefinite, func(v any) error {
+		if !hasKey {
+			if err := checkKey(v); err != nil {
+				return err
+			}
+			if m.Contains(v) {
+				return fmt.Errorf("cbor: duplicate map key %v", v)
+			}
+			key, hasKey = v, true
+			return nil
+		}
+		m = m.Assoc(key, v)
+		hasKey = false
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if hasKey {
+		return nil, fmt.Errorf("cbor: map with a key %v but no value", key)
+	}
+	return m, nil
+}
+
+// checkKey returns an error if key can't be used as the key of a map.
+func checkKey(key any) error {
+	if key != nil && !reflect.TypeOf(key).Comparable() {
+		return fmt.Errorf("cbor: map key of type %T is not comparable", key)
+	}
+	if f, ok := key.(float64); ok && math.IsNaN(f) {
+		return fmt.Errorf("cbor: map key NaN is not equal to itself")
+	}
+	return nil
+}
+
+func (d *decoder) tag(number uint64) (any, error) {
+	var content, err = d.item()
+	if err != nil {
+		return nil, err
+	}
+
+	switch number {
+	case tagDateTime:
+		var s, ok = content.(string)
+		if !ok {
+			return nil, fmt.Errorf("cbor: date/time of %T rather than a string", content)
+		}
+		var t time.Time
+		if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return nil, fmt.Errorf("cbor: invalid date/time %q", s)
+		}
+		return t, nil
+	case tagEpoch:
+		switch x := content.(type) {
+		case int64:
+			return time.Unix(x, 0).UTC(), nil
+		case float64:
+			var sec, frac = math.Modf(x)
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+		}
+		return nil, fmt.Errorf("cbor: epoch date/time of %T rather than a number", content)
+	case tagSet:
+		var items, ok = content.(vectors.Vector[any])
+		if !ok {
+			return nil, fmt.Errorf("cbor: set of %T rather than an array", content)
+		}
+		var s = maps.New[any, struct{}]()
+		for _, item := range items.All() {
+			if err = checkKey(item); err != nil {
+				return nil, err
+			}
+			s = s.Assoc(item, struct{}{})
+		}
+		return s, nil
+	default:
+		return Tag{Number: number, Content: content}, nil
+	}
+}
+
+// simple returns the value of a simple value or float with additional
+// information info and argument n.
+func (d *decoder) simple(info byte, n uint64) (any, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return fromHalf(uint16(n)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	case 31:
+		return nil, fmt.Errorf("cbor: unexpected break")
+	default:
+		return nil, fmt.Errorf("cbor: unsupported simple value %d", n)
+	}
+}
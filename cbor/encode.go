@@ -0,0 +1,224 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/toddgaunt/persistent/codec"
+)
+
+// Marshal returns the CBOR encoding of v. Map entries and set elements are
+// written in the order they are iterated over, and floating point numbers are
+// written at the precision of their type. An error is returned if v or any
+// value within it is of a type which can't be encoded.
+func Marshal(v any) ([]byte, error) {
+	var e = encoder{}
+	return e.value(nil, reflect.ValueOf(v))
+}
+
+// MarshalCanonical is like Marshal, except the encoding is deterministic as
+// defined by section 4.2.1 of RFC 8949. Map entries and set elements are
+// sorted by the bytewise lexicographic order of the encodings of their keys,
+// floating point numbers are written in the shortest form which represents
+// them exactly, and every NaN is written as the same quiet NaN.
+func MarshalCanonical(v any) ([]byte, error) {
+	var e = encoder{canonical: true}
+	return e.value(nil, reflect.ValueOf(v))
+}
+
+type encoder struct {
+	canonical bool
+}
+
+// appendHead appends the head of a data item of the major type with the
+// argument n, in its shortest form.
+func appendHead(data []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(data, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(data, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(data, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(data, major|27), n)
+	}
+}
+
+func (e *encoder) value(data []byte, v reflect.Value) ([]byte, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return append(data, majorSimple|22), nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return append(data, majorSimple|22), nil
+	}
+	if c := codec.Lookup(v.Type()); c != nil {
+		var repr, err = c.Encode(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return e.value(data, reflect.ValueOf(repr))
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		data = appendHead(data, majorTag, tagDateTime)
+		var s = x.Format(time.RFC3339Nano)
+		return append(appendHead(data, majorText, uint64(len(s))), s...), nil
+	case Tag:
+		return e.value(appendHead(data, majorTag, x.Number), reflect.ValueOf(x.Content))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(data, majorSimple|21), nil
+		}
+		return append(data, majorSimple|20), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := v.Int(); i < 0 {
+			return appendHead(data, majorNegInt, uint64(-1-i)), nil
+		}
+		return appendHead(data, majorUint, uint64(v.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendHead(data, majorUint, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return e.float(data, v.Float(), v.Kind() == reflect.Float32), nil
+	case reflect.String:
+		return append(appendHead(data, majorText, uint64(v.Len())), v.String()...), nil
+	}
+
+	if has(v, "Len", "GetOK", "Assoc", "All") {
+		var n = uint64(v.MethodByName("Len").Call(nil)[0].Int())
+		if v.MethodByName("GetOK").Type().Out(0) == reflect.TypeFor[struct{}]() {
+			data = appendHead(data, majorTag, tagSet)
+			return e.each(appendHead(data, majorArray, n), v, 1, e.canonical)
+		}
+		return e.each(appendHead(data, majorMap, n), v, 2, e.canonical)
+	}
+	if has(v, "Len", "All") {
+		var n = uint64(v.MethodByName("Len").Call(nil)[0].Int())
+		// Vectors yield their indices before each item, which are skipped.
+		var skip = v.MethodByName("All").Type().Out(0).In(0).NumIn() - 1
+		return e.each(appendHead(data, majorArray, n), v, -skip, false)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return append(appendHead(data, majorBytes, uint64(v.Len())), v.Bytes()...), nil
+		}
+		data = appendHead(data, majorArray, uint64(v.Len()))
+		for i := 0; i < v.Len(); i += 1 {
+			var err error
+			if data, err = e.value(data, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	case reflect.Map:
+		data = appendHead(data, majorMap, uint64(v.Len()))
+		var items []reflect.Value
+		for it := v.MapRange(); it.Next(); {
+			items = append(items, it.Key(), it.Value())
+		}
+		return e.items(data, items, 2, e.canonical)
+	}
+
+	return nil, fmt.Errorf("cbor: cannot encode a value of type %s", v.Type())
+}
+
+// float appends f, which is a float32 if single is true, as a half, single or
+// double precision float.
+func (e *encoder) float(data []byte, f float64, single bool) []byte {
+	if e.canonical {
+		if h, ok := toHalf(float32(f)); ok && (math.IsNaN(f) || fromHalf(h) == f) {
+			return binary.BigEndian.AppendUint16(append(data, majorSimple|25), h)
+		}
+		single = float64(float32(f)) == f
+	}
+	if single {
+		return binary.BigEndian.AppendUint32(append(data, majorSimple|26), math.Float32bits(float32(f)))
+	}
+	return binary.BigEndian.AppendUint64(append(data, majorSimple|27), math.Float64bits(f))
+}
+
+// each appends the values yielded by the iterator returned by calling the All
+// method of v, as items does. If n is positive, the first n values of each
+// step are appended, otherwise the first -n values are skipped and the rest
+// appended.
+func (e *encoder) each(data []byte, v reflect.Value, n int, sorted bool) ([]byte, error) {
+	var items []reflect.Value
+	var all = v.MethodByName("All").Call(nil)[0]
+	var yield = reflect.MakeFunc(all.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		if n > 0 {
+			items = append(items, args[:n]...)
+		} else {
+			items = append(items, args[-n:]...)
+		}
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+	all.Call([]reflect.Value{yield})
+	if n <= 0 {
+		n = 1
+	}
+	return e.items(data, items, n, sorted)
+}
+
+// items appends items, which are the keys and values of a map if n is 2 or
+// the elements of a set or array if n is 1. If sorted is true, the entries
+// are sorted by the encoding of their keys or elements.
+func (e *encoder) items(data []byte, items []reflect.Value, n int, sorted bool) ([]byte, error) {
+	if !sorted {
+		for _, item := range items {
+			var err error
+			if data, err = e.value(data, item); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	}
+
+	var entries = make([][2][]byte, 0, len(items)/n)
+	for i := 0; i < len(items); i += n {
+		var entry [2][]byte
+		for j := 0; j < n; j += 1 {
+			var err error
+			if entry[j], err = e.value(nil, items[i+j]); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b [2][]byte) int {
+		return bytes.Compare(a[0], b[0])
+	})
+	for _, entry := range entries {
+		data = append(append(data, entry[0]...), entry[1]...)
+	}
+	return data, nil
+}
+
+// has returns true if v has every method in names.
+func has(v reflect.Value, names ...string) bool {
+	for _, name := range names {
+		if !v.MethodByName(name).IsValid() {
+			return false
+		}
+	}
+	return true
+}
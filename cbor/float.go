@@ -0,0 +1,69 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cbor
+
+import "math"
+
+// toHalf returns f as the bits of a half precision float, and true if it is
+// represented exactly. NaNs are all converted to the same quiet NaN.
+func toHalf(f float32) (uint16, bool) {
+	var bits = math.Float32bits(f)
+	var sign = uint16(bits>>16) & 0x8000
+	var exp = int(bits>>23) & 0xff
+	var mant = bits & 0x7fffff
+
+	switch {
+	case exp == 0xff && mant != 0:
+		return 0x7e00, true
+	case exp == 0xff:
+		return sign | 0x7c00, true
+	case exp == 0 && mant == 0:
+		return sign, true
+	}
+
+	var e = exp - 127 + 15
+	switch {
+	case e >= 0x1f:
+		return 0, false
+	case e <= 0:
+		// The value is subnormal as a half, losing the implicit leading bit
+		// of the mantissa.
+		var full = mant | 0x800000
+		var shift = 126 - exp
+		if shift > 24 || full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	default:
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(e)<<10 | uint16(mant>>13), true
+	}
+}
+
+// fromHalf returns the value of the half precision float with bits h.
+func fromHalf(h uint16) float64 {
+	var exp = int(h>>10) & 0x1f
+	var mant = float64(h & 0x3ff)
+
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
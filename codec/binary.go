@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package codec
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// MarshalBinary returns the binary encoding of a single value, as used for the
+// values held by collections encoded with their MarshalBinary methods. Values
+// of a type with a registered codec are first converted to their
+// representation. Values are encoded using their own MarshalBinary method if
+// they implement encoding.BinaryMarshaler. Otherwise strings and byte slices
+// are written as-is, int and uint are written as 8 bytes, and fixed-size
+// values such as numbers, or arrays and structs of them, are written in
+// little-endian byte order as with encoding/binary. Any other type of value
+// results in an error.
+func MarshalBinary(value any) ([]byte, error) {
+	if c := Lookup(reflect.TypeOf(value)); c != nil {
+		var repr, err = c.encode(value)
+		if err != nil {
+			return nil, err
+		}
+		return MarshalBinary(repr)
+	}
+
+	switch x := value.(type) {
+	case encoding.BinaryMarshaler:
+		return x.MarshalBinary()
+	case string:
+		return []byte(x), nil
+	case []byte:
+		return x, nil
+	case int:
+		return binary.LittleEndian.AppendUint64(nil, uint64(x)), nil
+	case uint:
+		return binary.LittleEndian.AppendUint64(nil, uint64(x)), nil
+	}
+
+	if binary.Size(value) < 0 {
+		return nil, fmt.Errorf("codec: cannot marshal value of type %T", value)
+	}
+	return binary.Append(nil, binary.LittleEndian, value)
+}
+
+// UnmarshalBinary sets value to the value encoded in data by MarshalBinary,
+// decoding the representation of T and converting it if a codec is registered
+// for T.
+func UnmarshalBinary[T any](data []byte, value *T) error {
+	return DecodeWith(value, func(ptr any) error {
+		return unmarshalInto(data, ptr)
+	})
+}
+
+// unmarshalInto decodes data into the value ptr points to.
+func unmarshalInto(data []byte, ptr any) error {
+	switch x := ptr.(type) {
+	case encoding.BinaryUnmarshaler:
+		return x.UnmarshalBinary(data)
+	case *string:
+		*x = string(data)
+		return nil
+	case *[]byte:
+		*x = append([]byte(nil), data...)
+		return nil
+	case *int:
+		if len(data) != 8 {
+			return fmt.Errorf("got %d bytes for an int, want 8", len(data))
+		}
+		*x = int(binary.LittleEndian.Uint64(data))
+		return nil
+	case *uint:
+		if len(data) != 8 {
+			return fmt.Errorf("got %d bytes for a uint, want 8", len(data))
+		}
+		*x = uint(binary.LittleEndian.Uint64(data))
+		return nil
+	}
+
+	if binary.Size(ptr) < 0 {
+		return fmt.Errorf("cannot unmarshal value of type %s", reflect.TypeOf(ptr).Elem())
+	}
+	n, err := binary.Decode(data, binary.LittleEndian, ptr)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("%d trailing bytes", len(data)-n)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package codec_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/codec"
+)
+
+type point struct {
+	X, Y int32
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		value     any
+		unmarshal func(data []byte) (any, error)
+	}{
+		{"String", "abc", unmarshal[string]},
+		{"Int", -7, unmarshal[int]},
+		{"Uint", uint(7), unmarshal[uint]},
+		{"Float", 1.5, unmarshal[float64]},
+		{"Struct", point{1, -2}, unmarshal[point]},
+		{"Registered", cents(1205), unmarshal[cents]},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var data, err = codec.MarshalBinary(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			var got any
+			if got, err = tc.unmarshal(data); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got != tc.value {
+				t.Fatalf("got %#v, want %#v", got, tc.value)
+			}
+		})
+	}
+}
+
+func unmarshal[T any](data []byte) (any, error) {
+	var value T
+	var err = codec.UnmarshalBinary(data, &value)
+	return value, err
+}
+
+func TestBinaryRegisteredRepr(t *testing.T) {
+	var data, err = codec.MarshalBinary(cents(1205))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := string(data), "12.05"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	if data, err := codec.MarshalBinary(map[int]int{}); err == nil {
+		t.Fatalf("got %v and nil error, want an error", data)
+	}
+	if data, err := codec.MarshalBinary(cents(-1)); err == nil {
+		t.Fatalf("got %v and nil error, want an error", data)
+	}
+
+	var i int
+	if err := codec.UnmarshalBinary([]byte{1, 2, 3}, &i); err == nil {
+		t.Fatalf("got %d and nil error for 3 bytes, want an error", i)
+	}
+	var p point
+	if err := codec.UnmarshalBinary(make([]byte, 9), &p); err == nil {
+		t.Fatalf("got %v and nil error for trailing bytes, want an error", p)
+	}
+	var m map[int]int
+	if err := codec.UnmarshalBinary(nil, &m); err == nil {
+		t.Fatalf("got %v and nil error, want an error", m)
+	}
+}
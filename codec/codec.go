@@ -0,0 +1,166 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package codec provides a registry of conversions for types of values held
+// in persistent data structures, so that values of a type are serialized the
+// same way no matter which collection or format they are written with. A
+// codec converts values of its type to and from a representation, which is
+// another type the formats already know how to encode, such as a string:
+//
+//	codec.Register(
+//		func(t time.Time) (string, error) {
+//			return t.Format(time.DateOnly), nil
+//		},
+//		func(s string) (time.Time, error) {
+//			return time.Parse(time.DateOnly, s)
+//		},
+//	)
+//
+// Registered codecs are used when encoding the values, and the keys of maps,
+// held by vectors and maps as JSON or with MarshalBinary, and for any value
+// encoded by the cbor package. The binary encoding of a single value shared by
+// the collections is provided by MarshalBinary and UnmarshalBinary. When
+// decoding, the codec registered for the element, key or value type of the
+// collection being decoded is used, so values held in interface types can't
+// be decoded with a codec. Since the cbor package decodes to values of
+// dynamic types, codecs are not used by cbor.Unmarshal.
+//
+// Codecs should be registered during initialization, before any values of
+// their type are encoded or decoded.
+package codec
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Codec converts values of a registered type to and from the representation
+// they are encoded as.
+type Codec struct {
+	typ    reflect.Type
+	repr   reflect.Type
+	encode func(any) (any, error)
+	decode func(any) (any, error)
+}
+
+// Type returns the type of values converted by c.
+func (c *Codec) Type() reflect.Type {
+	return c.typ
+}
+
+// Repr returns the type of the representation of values converted by c.
+func (c *Codec) Repr() reflect.Type {
+	return c.repr
+}
+
+// Encode returns the representation of value, which must be of c's type.
+func (c *Codec) Encode(value any) (any, error) {
+	return c.encode(value)
+}
+
+// Decode returns the value of c's type represented by repr, which must be of
+// c's representation type.
+func (c *Codec) Decode(repr any) (any, error) {
+	return c.decode(repr)
+}
+
+var (
+	registry sync.Map // Codecs by the reflect.Type they convert
+	count    atomic.Int64
+)
+
+// Register registers a codec for values of type T, which are encoded as their
+// representation of type R returned by encode, and decoded by calling decode
+// with a decoded representation. Register panics if a codec is already
+// registered for T, if T is an interface type, or if R is the same type as T.
+func Register[T any, R any](encode func(T) (R, error), decode func(R) (T, error)) {
+	var typ, repr = reflect.TypeFor[T](), reflect.TypeFor[R]()
+	if typ.Kind() == reflect.Interface {
+		panic(fmt.Sprintf("codec: cannot register a codec for interface type %s", typ))
+	}
+	if typ == repr {
+		panic(fmt.Sprintf("codec: the representation of %s must be a different type", typ))
+	}
+
+	var c = &Codec{
+		typ:  typ,
+		repr: repr,
+		encode: func(value any) (any, error) {
+			return encode(value.(T))
+		},
+		decode: func(repr any) (any, error) {
+			var r R
+			if repr != nil {
+				r = repr.(R)
+			}
+			return decode(r)
+		},
+	}
+	if _, loaded := registry.LoadOrStore(typ, c); loaded {
+		panic(fmt.Sprintf("codec: Register called twice for type %s", typ))
+	}
+	count.Add(1)
+}
+
+// Lookup returns the codec registered for values of type t, or nil if there
+// isn't one.
+func Lookup(t reflect.Type) *Codec {
+	if count.Load() == 0 || t == nil {
+		return nil
+	}
+	if c, ok := registry.Load(t); ok {
+		return c.(*Codec)
+	}
+	return nil
+}
+
+// For returns the codec registered for values of type T, or nil if there
+// isn't one.
+func For[T any]() *Codec {
+	return Lookup(reflect.TypeFor[T]())
+}
+
+// Applies returns true if values of type T may need to be converted by a
+// registered codec before being encoded, which is when a codec is registered
+// for T itself, or when T is an interface type and any codec is registered.
+func Applies[T any]() bool {
+	var t = reflect.TypeFor[T]()
+	if t.Kind() == reflect.Interface {
+		return count.Load() > 0
+	}
+	return Lookup(t) != nil
+}
+
+// Encode returns the representation of value given by the codec registered
+// for its dynamic type, or value itself if there isn't one.
+func Encode(value any) (any, error) {
+	if c := Lookup(reflect.TypeOf(value)); c != nil {
+		return c.encode(value)
+	}
+	return value, nil
+}
+
+// DecodeWith sets value to the value decoded by calling decode with a pointer
+// to fill in. If a codec is registered for T, decode is given a pointer to its
+// representation, which is then converted to a T by the codec. Otherwise
+// decode is given value itself.
+func DecodeWith[T any](value *T, decode func(ptr any) error) error {
+	var c = For[T]()
+	if c == nil {
+		return decode(value)
+	}
+
+	var repr = reflect.New(c.repr)
+	if err := decode(repr.Interface()); err != nil {
+		return err
+	}
+	var v, err = c.decode(repr.Elem().Interface())
+	if err != nil {
+		return err
+	}
+	*value = v.(T)
+	return nil
+}
@@ -0,0 +1,172 @@
+package codec_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/toddgaunt/persistent/codec"
+)
+
+type cents int64
+
+type unregistered int64
+
+func init() {
+	codec.Register(
+		func(c cents) (string, error) {
+			if c < 0 {
+				return "", errors.New("negative amount")
+			}
+			return fmt.Sprintf("%d.%02d", c/100, c%100), nil
+		},
+		func(s string) (cents, error) {
+			var f, err = strconv.ParseFloat(s, 64)
+			return cents(f*100 + 0.5), err
+		},
+	)
+}
+
+func TestLookup(t *testing.T) {
+	var c = codec.Lookup(reflect.TypeFor[cents]())
+	if c == nil {
+		t.Fatalf("got nil codec for cents, want a codec")
+	}
+	if c != codec.For[cents]() {
+		t.Fatalf("got a different codec from For than from Lookup")
+	}
+	if got, want := c.Type(), reflect.TypeFor[cents](); got != want {
+		t.Fatalf("got Type()=%s, want Type()=%s", got, want)
+	}
+	if got, want := c.Repr(), reflect.TypeFor[string](); got != want {
+		t.Fatalf("got Repr()=%s, want Repr()=%s", got, want)
+	}
+	if c := codec.For[unregistered](); c != nil {
+		t.Fatalf("got a codec for an unregistered type, want nil")
+	}
+	if c := codec.Lookup(nil); c != nil {
+		t.Fatalf("got a codec for a nil type, want nil")
+	}
+}
+
+func TestApplies(t *testing.T) {
+	var testCases = []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"Registered", codec.Applies[cents](), true},
+		{"Unregistered", codec.Applies[unregistered](), false},
+		{"Interface", codec.Applies[any](), true},
+		{"Pointer", codec.Applies[*cents](), false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("got %t, want %t", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{"Registered", cents(1205), "12.05"},
+		{"Unregistered", unregistered(1205), unregistered(1205)},
+		{"Nil", nil, nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = codec.Encode(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+
+	if got, err := codec.Encode(cents(-1)); err == nil {
+		t.Fatalf("got %v and nil error, want an error", got)
+	}
+}
+
+func TestDecodeWith(t *testing.T) {
+	var c cents
+	var err = codec.DecodeWith(&c, func(ptr any) error {
+		var s, ok = ptr.(*string)
+		if !ok {
+			t.Fatalf("got %T to decode into, want *string", ptr)
+		}
+		*s = "3.50"
+		return nil
+	})
+	if err != nil || c != 350 {
+		t.Fatalf("got %d, %v, want 350, nil", c, err)
+	}
+
+	var u unregistered
+	err = codec.DecodeWith(&u, func(ptr any) error {
+		*ptr.(*unregistered) = 7
+		return nil
+	})
+	if err != nil || u != 7 {
+		t.Fatalf("got %d, %v, want 7, nil", u, err)
+	}
+
+	var want = errors.New("decode failed")
+	if err = codec.DecodeWith(&c, func(any) error { return want }); err != want {
+		t.Fatalf("got error %v, want %v", err, want)
+	}
+	err = codec.DecodeWith(&c, func(ptr any) error {
+		*ptr.(*string) = "x"
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("got nil error for an invalid representation, want an error")
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		register func()
+	}{
+		{"Twice", func() {
+			codec.Register(func(c cents) (int64, error) { return int64(c), nil }, func(i int64) (cents, error) { return cents(i), nil })
+		}},
+		{"Interface", func() {
+			codec.Register(func(v any) (string, error) { return "", nil }, func(string) (any, error) { return nil, nil })
+		}},
+		{"SameType", func() {
+			codec.Register(func(u unregistered) (unregistered, error) { return u, nil }, func(u unregistered) (unregistered, error) { return u, nil })
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			tc.register()
+		})
+	}
+
+	if c := codec.For[unregistered](); c != nil {
+		t.Fatalf("got a codec registered by a call which panicked, want nil")
+	}
+}
@@ -0,0 +1,86 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package crdt
+
+import "github.com/toddgaunt/persistent/maps"
+
+// GCounter is a grow-only counter, which is a counter that can be incremented
+// on any replica but never decremented. Each replica keeps a count of its own
+// increments, and merging keeps the greater count of each replica. The zero
+// value of GCounter is a counter of zero ready to use.
+type GCounter struct {
+	counts maps.Map[string, uint64] // Increments made by each replica
+}
+
+// NewGCounter creates a new grow-only counter with a value of zero.
+func NewGCounter() GCounter {
+	return GCounter{}
+}
+
+// Value returns the sum of the increments made by every replica.
+func (c GCounter) Value() uint64 {
+	var sum uint64
+	for _, count := range c.counts.All() {
+		sum += count
+	}
+	return sum
+}
+
+// Increment returns a new counter with n added to the count of replica, which
+// must be a name unique to the replica making the increment.
+func (c GCounter) Increment(replica string, n uint64) GCounter {
+	if n == 0 {
+		return c
+	}
+	return GCounter{counts: c.counts.Update(replica, func(count uint64, _ bool) uint64 {
+		return count + n
+	})}
+}
+
+// Merge returns a new counter containing the increments of both c and other.
+// Merging is commutative, associative and idempotent, so replicas converge on
+// the same value once each has merged the increments of all the others.
+func (c GCounter) Merge(other GCounter) GCounter {
+	return GCounter{counts: maps.MergeWith(c.counts, other.counts, latest)}
+}
+
+// PNCounter is a counter that can be both incremented and decremented on any
+// replica. It is made of two grow-only counters, one counting increments and
+// the other decrements, with its value being their difference. The zero value
+// of PNCounter is a counter of zero ready to use.
+type PNCounter struct {
+	inc GCounter
+	dec GCounter
+}
+
+// NewPNCounter creates a new counter with a value of zero.
+func NewPNCounter() PNCounter {
+	return PNCounter{}
+}
+
+// Value returns the sum of the increments made by every replica less the sum
+// of their decrements.
+func (c PNCounter) Value() int64 {
+	return int64(c.inc.Value() - c.dec.Value())
+}
+
+// Increment returns a new counter with n added to the increments of replica,
+// which must be a name unique to the replica making the increment.
+func (c PNCounter) Increment(replica string, n uint64) PNCounter {
+	return PNCounter{inc: c.inc.Increment(replica, n), dec: c.dec}
+}
+
+// Decrement returns a new counter with n added to the decrements of replica,
+// which must be a name unique to the replica making the decrement.
+func (c PNCounter) Decrement(replica string, n uint64) PNCounter {
+	return PNCounter{inc: c.inc, dec: c.dec.Increment(replica, n)}
+}
+
+// Merge returns a new counter containing the increments and decrements of
+// both c and other. Merging is commutative, associative and idempotent, as
+// for GCounter.
+func (c PNCounter) Merge(other PNCounter) PNCounter {
+	return PNCounter{inc: c.inc.Merge(other.inc), dec: c.dec.Merge(other.dec)}
+}
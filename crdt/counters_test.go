@@ -0,0 +1,96 @@
+package crdt_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/toddgaunt/persistent/crdt"
+)
+
+func TestGCounter(t *testing.T) {
+	var base = crdt.NewGCounter().Increment("r1", 2)
+	var a = base.Increment("r1", 3)
+	var b = base.Increment("r2", 4).Increment("r2", 0)
+
+	var testCases = []struct {
+		name string
+		got  uint64
+		want uint64
+	}{
+		{"Base", base.Value(), 2},
+		{"Increment", a.Value(), 5},
+		{"Merge", a.Merge(b).Value(), 9},
+		{"Commutative", b.Merge(a).Value(), 9},
+		{"Idempotent", a.Merge(b).Merge(b).Merge(a).Value(), 9},
+		{"Stale", a.Merge(base).Value(), 5},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("got %d, want %d", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPNCounter(t *testing.T) {
+	var base = crdt.NewPNCounter().Increment("r1", 5)
+	var a = base.Decrement("r1", 7)
+	var b = base.Increment("r2", 1).Decrement("r2", 2)
+
+	var testCases = []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		{"Base", base.Value(), 5},
+		{"Negative", a.Value(), -2},
+		{"Merge", a.Merge(b).Value(), -3},
+		{"Commutative", b.Merge(a).Value(), -3},
+		{"Idempotent", a.Merge(b).Merge(a).Value(), -3},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("got %d, want %d", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPNCounterConverge(t *testing.T) {
+	var r = rand.New(rand.NewPCG(15, 16))
+	var replicas = []string{"r1", "r2", "r3"}
+	var counters = make([]crdt.PNCounter, len(replicas))
+	var want int64
+
+	for i := 0; i < 3000; i++ {
+		var n = r.IntN(len(replicas))
+		var by = uint64(r.IntN(10))
+		switch r.IntN(3) {
+		case 0:
+			counters[n] = counters[n].Merge(counters[r.IntN(len(counters))])
+		case 1:
+			counters[n] = counters[n].Decrement(replicas[n], by)
+			want -= int64(by)
+		default:
+			counters[n] = counters[n].Increment(replicas[n], by)
+			want += int64(by)
+		}
+	}
+
+	var orders = [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0, 1}}
+	for _, order := range orders {
+		var merged crdt.PNCounter
+		for _, n := range order {
+			merged = merged.Merge(counters[n])
+		}
+		if got := merged.Value(); got != want {
+			t.Fatalf("got %d merging in order %v, want %d", got, order, want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package crdt provides state-based conflict-free replicated data types built
+// on the persistent maps of this module. Each replica of a CRDT is updated
+// independently, and any two replicas can be merged into one which reflects
+// the updates made to both, no matter the order in which replicas are merged
+// or how many times the same updates are merged. Since every version of a CRDT
+// is a persistent value, a replica can be merged with others while readers
+// continue to use earlier versions of it, and merging shares all the unchanged
+// parts of each replica.
+package crdt
+
+import (
+	"iter"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+// tag uniquely identifies a single Add to an ORSet, being made of the replica
+// which made the Add and the number of Adds made by that replica so far.
+type tag struct {
+	replica string
+	seq     uint64
+}
+
+// ORSet is an observed-remove set, which is a set that can be added to and
+// removed from on any replica. Each Add of an item is given a unique tag, and
+// a Remove of an item removes only the tags of it which have been observed by
+// the replica making the Remove. This means that when an item is added on one
+// replica at the same time as it is removed on another, the item is present
+// in the merged set. The zero value of ORSet is an empty set ready to use.
+type ORSet[T comparable] struct {
+	items   maps.Map[T, maps.Map[tag, struct{}]] // Tags of each item not yet removed
+	removed maps.Map[tag, struct{}]              // Tags of every Add since removed
+	clock   maps.Map[string, uint64]             // Number of Adds made by each replica
+}
+
+// NewORSet creates a new empty observed-remove set.
+func NewORSet[T comparable]() ORSet[T] {
+	return ORSet[T]{}
+}
+
+// Len returns the number of items in s.
+func (s ORSet[T]) Len() int {
+	return s.items.Len()
+}
+
+// Contains returns true if item is in s.
+func (s ORSet[T]) Contains(item T) bool {
+	return s.items.Contains(item)
+}
+
+// All returns an iterator over each item of s. The order of iteration is
+// unspecified, but is the same each time s is iterated over.
+func (s ORSet[T]) All() iter.Seq[T] {
+	return s.items.Keys()
+}
+
+// Add returns a new set with item added to it by replica, which must be a
+// name unique to the replica making the Add.
+func (s ORSet[T]) Add(replica string, item T) ORSet[T] {
+	var seq = s.clock.Get(replica) + 1
+	var t = tag{replica: replica, seq: seq}
+
+	return ORSet[T]{
+		items: s.items.Update(item, func(tags maps.Map[tag, struct{}], _ bool) maps.Map[tag, struct{}] {
+			return tags.Assoc(t, struct{}{})
+		}),
+		removed: s.removed,
+		clock:   s.clock.Assoc(replica, seq),
+	}
+}
+
+// Remove returns a new set without item. Only the Adds of item which s has
+// observed are removed, so item is still present after merging with a replica
+// which added it concurrently. If item isn't in s, s itself is returned.
+func (s ORSet[T]) Remove(item T) ORSet[T] {
+	var tags, ok = s.items.GetOK(item)
+	if !ok {
+		return s
+	}
+
+	var removed = s.removed
+	for t := range tags.Keys() {
+		removed = removed.Assoc(t, struct{}{})
+	}
+
+	return ORSet[T]{
+		items:   s.items.Dissoc(item),
+		removed: removed,
+		clock:   s.clock,
+	}
+}
+
+// Merge returns a new set containing the Adds and Removes of both s and other.
+// Merging is commutative, associative and idempotent, so replicas converge on
+// the same set once each has merged the updates of all the others.
+func (s ORSet[T]) Merge(other ORSet[T]) ORSet[T] {
+	var removed = maps.MergeWith(s.removed, other.removed, keep)
+	var items = maps.MergeWith(s.items, other.items, func(old, new maps.Map[tag, struct{}]) maps.Map[tag, struct{}] {
+		return maps.MergeWith(old, new, keep)
+	})
+
+	// Tags removed by either set are removed from the tags of both, dropping
+	// items once all of their tags are removed.
+	items = maps.MapValues(items, func(tags maps.Map[tag, struct{}]) maps.Map[tag, struct{}] {
+		return maps.Filter(tags, func(t tag, _ struct{}) bool {
+			return !removed.Contains(t)
+		})
+	})
+	items = maps.Filter(items, func(_ T, tags maps.Map[tag, struct{}]) bool {
+		return tags.Len() > 0
+	})
+
+	return ORSet[T]{
+		items:   items,
+		removed: removed,
+		clock:   maps.MergeWith(s.clock, other.clock, latest),
+	}
+}
+
+// latest is used to merge clocks, keeping the greater count of each replica.
+func latest(old, new uint64) uint64 {
+	return max(old, new)
+}
+
+// keep is used to merge maps used as sets, where the values don't matter.
+func keep(old, _ struct{}) struct{} {
+	return old
+}
@@ -0,0 +1,91 @@
+package crdt_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/crdt"
+)
+
+func itemsOf(s crdt.ORSet[string]) []string {
+	return slices.Sorted(s.All())
+}
+
+func TestORSetAddRemove(t *testing.T) {
+	var s1 = crdt.NewORSet[string]().Add("r1", "a").Add("r1", "b")
+	var s2 = s1.Remove("a").Remove("c")
+
+	if got, want := itemsOf(s2), []string{"b"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := itemsOf(s1), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("got original %v, want %v", got, want)
+	}
+	if got, want := s2.Len(), 1; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if s2.Contains("a") {
+		t.Fatalf("got Contains(a)=true after Remove, want false")
+	}
+}
+
+func TestORSetMerge(t *testing.T) {
+	var base = crdt.NewORSet[string]().Add("r1", "a").Add("r1", "b")
+
+	var testCases = []struct {
+		name string
+		a, b crdt.ORSet[string]
+		want []string
+	}{
+		{"Disjoint adds", base.Add("r1", "c"), base.Add("r2", "d"), []string{"a", "b", "c", "d"}},
+		{"Observed remove", base.Remove("a"), base, []string{"b"}},
+		{"Remove on both", base.Remove("a"), base.Remove("a"), []string{"b"}},
+		{"Add wins", base.Remove("a"), base.Add("r2", "a"), []string{"a", "b"}},
+		{"Re-add after remove", base.Remove("a").Add("r1", "a"), base, []string{"a", "b"}},
+		{"Unobserved add", crdt.NewORSet[string]().Add("r2", "a").Remove("a"), base, []string{"a", "b"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := itemsOf(tc.a.Merge(tc.b)); !slices.Equal(got, tc.want) {
+				t.Fatalf("got a.Merge(b) %v, want %v", got, tc.want)
+			}
+			if got := itemsOf(tc.b.Merge(tc.a)); !slices.Equal(got, tc.want) {
+				t.Fatalf("got b.Merge(a) %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestORSetConverge(t *testing.T) {
+	var r = rand.New(rand.NewPCG(13, 14))
+	var replicas = []string{"r1", "r2", "r3"}
+	var sets = make([]crdt.ORSet[int], len(replicas))
+
+	for i := 0; i < 3000; i++ {
+		var n = r.IntN(len(replicas))
+		switch r.IntN(5) {
+		case 0:
+			sets[n] = sets[n].Merge(sets[r.IntN(len(sets))])
+		case 1, 2:
+			sets[n] = sets[n].Remove(r.IntN(20))
+		default:
+			sets[n] = sets[n].Add(replicas[n], r.IntN(20))
+		}
+	}
+
+	// Merging in any order, any number of times, gives the same set.
+	var want = slices.Sorted(sets[0].Merge(sets[1]).Merge(sets[2]).All())
+	var orders = [][]int{{2, 1, 0}, {1, 0, 2}, {0, 2, 1, 0, 2}}
+	for _, order := range orders {
+		var merged crdt.ORSet[int]
+		for _, n := range order {
+			merged = merged.Merge(sets[n])
+		}
+		if got := slices.Sorted(merged.All()); !slices.Equal(got, want) {
+			t.Fatalf("got %v merging in order %v, want %v", got, order, want)
+		}
+	}
+}
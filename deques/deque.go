@@ -0,0 +1,325 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package deques provides a persistent double-ended queue. The implementation
+// is a 2-3 finger tree, which keeps a few items at each end of the tree within
+// immediate reach while the items in between are grouped into nodes of two or
+// three, nested one level deeper in the tree for each level of grouping.
+package deques
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// node is either a leaf holding a single item of the deque, or a node of the
+// finger tree holding two or three nodes from one level further down.
+type node[T any] struct {
+	item     T
+	children []*node[T] // Nil for a leaf
+}
+
+// forEach calls yield with each item within n in order. Iteration stops early
+// if yield returns false, in which case forEach also returns false.
+func (n *node[T]) forEach(yield func(T) bool) bool {
+	if n.children == nil {
+		return yield(n.item)
+	}
+	for _, child := range n.children {
+		if !child.forEach(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// backward is forEach in reverse order.
+func (n *node[T]) backward(yield func(T) bool) bool {
+	if n.children == nil {
+		return yield(n.item)
+	}
+	for i := len(n.children) - 1; i >= 0; i-- {
+		if !n.children[i].backward(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// tree is a finger tree of nodes which are all at the same level. A nil tree
+// is empty, a tree with a single node holds only that node, and any other
+// tree holds between one and four nodes in each of its prefix and suffix,
+// with a tree of nodes from the next level up in between. The digit slices
+// are never modified once a tree is made.
+type tree[T any] struct {
+	single *node[T]
+	prefix []*node[T]
+	middle *tree[T]
+	suffix []*node[T]
+}
+
+// digits returns a new tree holding the nodes of d, which holds between one
+// and four nodes.
+func digits[T any](d []*node[T]) *tree[T] {
+	if len(d) == 1 {
+		return &tree[T]{single: d[0]}
+	}
+	return &tree[T]{prefix: d[:1:1], suffix: d[1:]}
+}
+
+func (t *tree[T]) pushFront(n *node[T]) *tree[T] {
+	switch {
+	case t == nil:
+		return &tree[T]{single: n}
+	case t.single != nil:
+		return &tree[T]{prefix: []*node[T]{n}, suffix: []*node[T]{t.single}}
+	case len(t.prefix) == 4:
+		// The prefix is full, so three of its nodes are grouped into a node
+		// and pushed onto the middle of the tree.
+		var group = &node[T]{children: []*node[T]{t.prefix[1], t.prefix[2], t.prefix[3]}}
+		return &tree[T]{
+			prefix: []*node[T]{n, t.prefix[0]},
+			middle: t.middle.pushFront(group),
+			suffix: t.suffix,
+		}
+	default:
+		return &tree[T]{
+			prefix: append([]*node[T]{n}, t.prefix...),
+			middle: t.middle,
+			suffix: t.suffix,
+		}
+	}
+}
+
+func (t *tree[T]) pushBack(n *node[T]) *tree[T] {
+	switch {
+	case t == nil:
+		return &tree[T]{single: n}
+	case t.single != nil:
+		return &tree[T]{prefix: []*node[T]{t.single}, suffix: []*node[T]{n}}
+	case len(t.suffix) == 4:
+		// The suffix is full, so three of its nodes are grouped into a node
+		// and pushed onto the middle of the tree.
+		var group = &node[T]{children: []*node[T]{t.suffix[0], t.suffix[1], t.suffix[2]}}
+		return &tree[T]{
+			prefix: t.prefix,
+			middle: t.middle.pushBack(group),
+			suffix: []*node[T]{t.suffix[3], n},
+		}
+	default:
+		var suffix = make([]*node[T], len(t.suffix), len(t.suffix)+1)
+		copy(suffix, t.suffix)
+		return &tree[T]{
+			prefix: t.prefix,
+			middle: t.middle,
+			suffix: append(suffix, n),
+		}
+	}
+}
+
+// popFront returns the first node of the non-empty tree t, and the tree
+// without it.
+func (t *tree[T]) popFront() (*node[T], *tree[T]) {
+	switch {
+	case t.single != nil:
+		return t.single, nil
+	case len(t.prefix) > 1:
+		return t.prefix[0], &tree[T]{prefix: t.prefix[1:], middle: t.middle, suffix: t.suffix}
+	case t.middle == nil:
+		return t.prefix[0], digits(t.suffix)
+	default:
+		// The prefix is emptied, so it is refilled with the nodes grouped
+		// within the first node of the middle of the tree.
+		var group, middle = t.middle.popFront()
+		return t.prefix[0], &tree[T]{prefix: group.children, middle: middle, suffix: t.suffix}
+	}
+}
+
+// popBack returns the last node of the non-empty tree t, and the tree without
+// it.
+func (t *tree[T]) popBack() (*node[T], *tree[T]) {
+	var last = len(t.suffix) - 1
+	switch {
+	case t.single != nil:
+		return t.single, nil
+	case last > 0:
+		return t.suffix[last], &tree[T]{prefix: t.prefix, middle: t.middle, suffix: t.suffix[:last:last]}
+	case t.middle == nil:
+		return t.suffix[0], digits(t.prefix)
+	default:
+		// The suffix is emptied, so it is refilled with the nodes grouped
+		// within the last node of the middle of the tree.
+		var group, middle = t.middle.popBack()
+		return t.suffix[0], &tree[T]{prefix: t.prefix, middle: middle, suffix: group.children}
+	}
+}
+
+func (t *tree[T]) forEach(yield func(T) bool) bool {
+	switch {
+	case t == nil:
+		return true
+	case t.single != nil:
+		return t.single.forEach(yield)
+	}
+	for _, n := range t.prefix {
+		if !n.forEach(yield) {
+			return false
+		}
+	}
+	if !t.middle.forEach(yield) {
+		return false
+	}
+	for _, n := range t.suffix {
+		if !n.forEach(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *tree[T]) backward(yield func(T) bool) bool {
+	switch {
+	case t == nil:
+		return true
+	case t.single != nil:
+		return t.single.backward(yield)
+	}
+	for i := len(t.suffix) - 1; i >= 0; i-- {
+		if !t.suffix[i].backward(yield) {
+			return false
+		}
+	}
+	if !t.middle.backward(yield) {
+		return false
+	}
+	for i := len(t.prefix) - 1; i >= 0; i-- {
+		if !t.prefix[i].backward(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Deque is a persistent double-ended queue that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when pushing an item onto a Deque, the previous version of
+// that Deque can be used in more operations and referenced without having
+// been mutated from any operations it was used as input for. Items are pushed
+// onto and popped from either end in amortized constant time, and in
+// logarithmic time at worst. The zero value of Deque is an empty deque ready
+// to use.
+type Deque[T any] struct {
+	count int
+	tree  *tree[T]
+}
+
+// New creates a new persistent deque containing items, with the first of items
+// at the front of the deque and the last at the back.
+func New[T any](items ...T) Deque[T] {
+	var d Deque[T]
+	for _, item := range items {
+		d = d.PushBack(item)
+	}
+	return d
+}
+
+// Len returns the number of items in d.
+func (d Deque[T]) Len() int {
+	return d.count
+}
+
+// PushFront returns a new deque with item added to the front of d.
+func (d Deque[T]) PushFront(item T) Deque[T] {
+	return Deque[T]{count: d.count + 1, tree: d.tree.pushFront(&node[T]{item: item})}
+}
+
+// PushBack returns a new deque with item added to the back of d.
+func (d Deque[T]) PushBack(item T) Deque[T] {
+	return Deque[T]{count: d.count + 1, tree: d.tree.pushBack(&node[T]{item: item})}
+}
+
+// PopFront returns a new deque without the item at the front of d, along with
+// that item and true. If d is empty, d itself is returned along with the zero
+// value and false.
+func (d Deque[T]) PopFront() (Deque[T], T, bool) {
+	if d.count == 0 {
+		var zero T
+		return d, zero, false
+	}
+	var n, rest = d.tree.popFront()
+	return Deque[T]{count: d.count - 1, tree: rest}, n.item, true
+}
+
+// PopBack returns a new deque without the item at the back of d, along with
+// that item and true. If d is empty, d itself is returned along with the zero
+// value and false.
+func (d Deque[T]) PopBack() (Deque[T], T, bool) {
+	if d.count == 0 {
+		var zero T
+		return d, zero, false
+	}
+	var n, rest = d.tree.popBack()
+	return Deque[T]{count: d.count - 1, tree: rest}, n.item, true
+}
+
+// Front returns the item at the front of d and true, or the zero value and
+// false if d is empty.
+func (d Deque[T]) Front() (T, bool) {
+	var front T
+	var found = false
+	d.tree.forEach(func(item T) bool {
+		front, found = item, true
+		return false
+	})
+	return front, found
+}
+
+// Back returns the item at the back of d and true, or the zero value and false
+// if d is empty.
+func (d Deque[T]) Back() (T, bool) {
+	var back T
+	var found = false
+	d.tree.backward(func(item T) bool {
+		back, found = item, true
+		return false
+	})
+	return back, found
+}
+
+// All returns an iterator over each item of d from front to back.
+func (d Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		d.tree.forEach(yield)
+	}
+}
+
+// Backward returns an iterator over each item of d from back to front.
+func (d Deque[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		d.tree.backward(yield)
+	}
+}
+
+// String returns a representation of a deque from front to back, similar to
+// a slice when using the "%v" formatting verb as in the standard fmt package:
+//
+//	With no items: []
+//	With one item: [1]
+//	With more than one item: [1 2 3]
+func (d Deque[T]) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	var first = true
+	for item := range d.All() {
+		if !first {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprint(&sb, item)
+		first = false
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,152 @@
+package deques_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/deques"
+)
+
+func TestDequeEmpty(t *testing.T) {
+	var d deques.Deque[int]
+	if got, want := d.Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if _, _, ok := d.PopFront(); ok {
+		t.Fatalf("got PopFront() ok on an empty deque, want !ok")
+	}
+	if _, _, ok := d.PopBack(); ok {
+		t.Fatalf("got PopBack() ok on an empty deque, want !ok")
+	}
+	if _, ok := d.Front(); ok {
+		t.Fatalf("got Front() ok on an empty deque, want !ok")
+	}
+	if _, ok := d.Back(); ok {
+		t.Fatalf("got Back() ok on an empty deque, want !ok")
+	}
+	if got, want := d.String(), "[]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestDequeNew(t *testing.T) {
+	var d = deques.New(1, 2, 3)
+	if got, want := d.String(), "[1 2 3]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := slices.Collect(d.Backward()), []int{3, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("got %v backward, want %v", got, want)
+	}
+	if got, _ := d.Front(); got != 1 {
+		t.Fatalf("got Front()=%d, want 1", got)
+	}
+	if got, _ := d.Back(); got != 3 {
+		t.Fatalf("got Back()=%d, want 3", got)
+	}
+}
+
+func TestDequeDrain(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 10, 100, 10000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			var d deques.Deque[int]
+			for i := 0; i < n; i++ {
+				d = d.PushBack(i)
+			}
+
+			// Popping from the front must give the items in order, with the
+			// same items left for popping from the back.
+			var front, back = d, d
+			for i := 0; i < n; i++ {
+				var item int
+				var ok bool
+				if front, item, ok = front.PopFront(); !ok || item != i {
+					t.Fatalf("got PopFront()=%d, %t, want %d, true", item, ok, i)
+				}
+				if back, item, ok = back.PopBack(); !ok || item != n-i-1 {
+					t.Fatalf("got PopBack()=%d, %t, want %d, true", item, ok, n-i-1)
+				}
+			}
+			if front.Len() != 0 || back.Len() != 0 {
+				t.Fatalf("got Len()=%d and %d after draining, want 0", front.Len(), back.Len())
+			}
+			if got, want := d.Len(), n; got != want {
+				t.Fatalf("got original Len()=%d, want Len()=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestDequeModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(11, 12))
+	var model []int
+	var d deques.Deque[int]
+	var versions []deques.Deque[int]
+	var models [][]int
+
+	for i := 0; i < 20000; i++ {
+		switch r.IntN(4) {
+		case 0:
+			d = d.PushFront(i)
+			model = append([]int{i}, model...)
+		case 1:
+			d = d.PushBack(i)
+			model = append(model, i)
+		case 2:
+			var item int
+			var ok bool
+			d, item, ok = d.PopFront()
+			if ok != (len(model) > 0) || ok && item != model[0] {
+				t.Fatalf("got PopFront()=%d, %t, want %v", item, ok, model[:min(1, len(model))])
+			}
+			if ok {
+				model = model[1:]
+			}
+		case 3:
+			var item int
+			var ok bool
+			d, item, ok = d.PopBack()
+			if ok != (len(model) > 0) || ok && item != model[len(model)-1] {
+				t.Fatalf("got PopBack()=%d, %t, want %v", item, ok, model[max(0, len(model)-1):])
+			}
+			if ok {
+				model = model[:len(model)-1]
+			}
+		}
+
+		if i%1000 == 0 {
+			versions = append(versions, d)
+			models = append(models, slices.Clone(model))
+		}
+	}
+
+	// Every version must still match the model at the time it was made.
+	for i, version := range versions {
+		if got, want := version.Len(), len(models[i]); got != want {
+			t.Fatalf("got version %d Len()=%d, want Len()=%d", i, got, want)
+		}
+		if got := slices.Collect(version.All()); !slices.Equal(got, models[i]) {
+			t.Fatalf("got version %d %v, want %v", i, got, models[i])
+		}
+	}
+}
+
+func BenchmarkDequePushBack(b *testing.B) {
+	var d deques.Deque[int]
+	for i := 0; i < b.N; i++ {
+		d = d.PushBack(i)
+	}
+}
+
+func BenchmarkDequeSlidingWindow(b *testing.B) {
+	var d deques.Deque[int]
+	for i := 0; i < 1000; i++ {
+		d = d.PushBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d, _, _ = d.PopFront()
+		d = d.PushBack(i)
+	}
+}
@@ -0,0 +1,386 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package edn
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+// Unmarshal parses the single EDN value in data, returning it as a value of
+// one of the types described in the package documentation. Whitespace,
+// commas, comments and discarded elements may surround the value.
+func Unmarshal(data []byte) (any, error) {
+	var d = decoder{data: data}
+	var v, err = d.value()
+	if err != nil {
+		return nil, err
+	}
+	if err = d.skip(); err != nil {
+		return nil, err
+	}
+	if d.pos < len(d.data) {
+		return nil, d.errorf("unexpected %q after value", d.data[d.pos])
+	}
+	return v, nil
+}
+
+// errEnd is returned by decoder.element when it reaches the closing delimiter
+// of a collection rather than an element.
+var errEnd = errors.New("edn: end of collection")
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) errorf(format string, args ...any) error {
+	return fmt.Errorf("edn: offset %d: %s", d.pos, fmt.Sprintf(format, args...))
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == ','
+}
+
+func isDelimiter(c byte) bool {
+	return isSpace(c) || strings.IndexByte(`()[]{}";`, c) >= 0
+}
+
+// skip moves past any whitespace, comments and discarded elements.
+func (d *decoder) skip() error {
+	for d.pos < len(d.data) {
+		switch c := d.data[d.pos]; {
+		case isSpace(c):
+			d.pos += 1
+		case c == ';':
+			for d.pos < len(d.data) && d.data[d.pos] != '\n' {
+				d.pos += 1
+			}
+		case c == '#' && d.pos+1 < len(d.data) && d.data[d.pos+1] == '_':
+			d.pos += 2
+			if _, err := d.value(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// value parses the next value, which must be present.
+func (d *decoder) value() (any, error) {
+	var v, err = d.element(0)
+	if err == errEnd {
+		return nil, d.errorf("unexpected %q", d.data[d.pos-1])
+	}
+	return v, err
+}
+
+// element parses the next value within a collection ending with close, or
+// returns errEnd if the collection ends instead.
+func (d *decoder) element(close byte) (any, error) {
+	if err := d.skip(); err != nil {
+		return nil, err
+	}
+	if d.pos == len(d.data) {
+		return nil, d.errorf("unexpected end of input")
+	}
+
+	var c = d.data[d.pos]
+	switch {
+	case c == close:
+		d.pos += 1
+		return nil, errEnd
+	case c == '(':
+		d.pos += 1
+		var b lists.Builder[any]
+		if err := d.elements(')', b.Append); err != nil {
+			return nil, err
+		}
+		return b.List(), nil
+	case c == '[':
+		d.pos += 1
+		var b vectors.Builder[any]
+		if err := d.elements(']', b.Append); err != nil {
+			return nil, err
+		}
+		return b.Vector(), nil
+	case c == '{':
+		d.pos += 1
+		return d.mapElements()
+	case c == '"':
+		return d.string()
+	case c == '\\':
+		return d.char()
+	case c == '#':
+		return d.dispatch()
+	case c == ':':
+		var token = d.token()
+		if len(token) == 1 {
+			return nil, d.errorf("empty keyword")
+		}
+		return Keyword(token[1:]), nil
+	case strings.IndexByte(")]}", c) >= 0:
+		d.pos += 1
+		return nil, errEnd
+	}
+
+	var token = d.token()
+	switch {
+	case token == "":
+		return nil, d.errorf("unexpected %q", c)
+	case token == "nil":
+		return nil, nil
+	case token == "true":
+		return true, nil
+	case token == "false":
+		return false, nil
+	case isNumber(token):
+		return d.number(token)
+	default:
+		return Symbol(token), nil
+	}
+}
+
+// elements parses the elements of a collection up to close, calling add with
+// each.
+func (d *decoder) elements(close byte, add func(any)) error {
+	for {
+		var v, err = d.element(close)
+		if err == errEnd {
+			if d.data[d.pos-1] != close {
+				return d.errorf("unexpected %q", d.data[d.pos-1])
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		add(v)
+	}
+}
+
+func (d *decoder) mapElements() (any, error) {
+	var m = maps.NewWith[any, any](Hash, Equal)
+	var items []any
+	if err := d.elements('}', func(v any) { items = append(items, v) }); err != nil {
+		return nil, err
+	}
+	if len(items)%2 != 0 {
+		return nil, d.errorf("map literal must contain an even number of forms")
+	}
+	for i := 0; i < len(items); i += 2 {
+		if err := d.checkKey(items[i]); err != nil {
+			return nil, err
+		}
+		if m.Contains(items[i]) {
+			return nil, d.errorf("duplicate map key %v", items[i])
+		}
+		m = m.Assoc(items[i], items[i+1])
+	}
+	return m, nil
+}
+
+func (d *decoder) setElements() (any, error) {
+	var s = maps.NewWith[any, struct{}](Hash, Equal)
+	var items []any
+	if err := d.elements('}', func(v any) { items = append(items, v) }); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if err := d.checkKey(item); err != nil {
+			return nil, err
+		}
+		if s.Contains(item) {
+			return nil, d.errorf("duplicate set element %v", item)
+		}
+		s = s.Assoc(item, struct{}{})
+	}
+	return s, nil
+}
+
+// checkKey returns an error if key can't be used as the key of a map.
+func (d *decoder) checkKey(key any) error {
+	if f, ok := key.(float64); ok && math.IsNaN(f) {
+		return d.errorf("map key ##NaN is not equal to itself")
+	}
+	return nil
+}
+
+// dispatch parses a value starting with #.
+func (d *decoder) dispatch() (any, error) {
+	d.pos += 1
+	if d.pos == len(d.data) {
+		return nil, d.errorf("unexpected end of input")
+	}
+
+	switch d.data[d.pos] {
+	case '{':
+		d.pos += 1
+		return d.setElements()
+	case '#':
+		d.pos += 1
+		switch token := d.token(); token {
+		case "Inf":
+			return math.Inf(1), nil
+		case "-Inf":
+			return math.Inf(-1), nil
+		case "NaN":
+			return math.NaN(), nil
+		default:
+			return nil, d.errorf("unknown symbolic value ##%s", token)
+		}
+	}
+
+	var tag = d.token()
+	if tag == "" || isNumber(tag) || tag[0] == ':' {
+		return nil, d.errorf("invalid tag #%s", tag)
+	}
+	var v, err = d.value()
+	if err != nil {
+		return nil, err
+	}
+	return Tagged{Tag: Symbol(tag), Value: v}, nil
+}
+
+// token returns the run of bytes up to the next delimiter.
+func (d *decoder) token() string {
+	var start = d.pos
+	for d.pos < len(d.data) && !isDelimiter(d.data[d.pos]) {
+		d.pos += 1
+	}
+	return string(d.data[start:d.pos])
+}
+
+func isNumber(token string) bool {
+	if len(token) > 1 && (token[0] == '+' || token[0] == '-') {
+		token = token[1:]
+	}
+	return token[0] >= '0' && token[0] <= '9'
+}
+
+func (d *decoder) number(token string) (any, error) {
+	var s = token
+	if strings.HasSuffix(s, "M") {
+		s = s[:len(s)-1]
+		var f, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, d.errorf("invalid number %s", token)
+		}
+		return f, nil
+	}
+	if strings.HasSuffix(s, "N") {
+		s = s[:len(s)-1]
+	} else if strings.ContainsAny(s, ".eE") {
+		var f, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, d.errorf("invalid number %s", token)
+		}
+		return f, nil
+	}
+
+	s = strings.TrimPrefix(s, "+")
+	var i, err = strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		// Integers too large for an int64 may still fit in a uint64.
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return u, nil
+		}
+		return nil, d.errorf("invalid integer %s", token)
+	}
+	return i, nil
+}
+
+func (d *decoder) string() (any, error) {
+	d.pos += 1
+	var sb strings.Builder
+	for d.pos < len(d.data) {
+		var c = d.data[d.pos]
+		d.pos += 1
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if d.pos == len(d.data) {
+				return nil, d.errorf("unterminated string")
+			}
+			var e = d.data[d.pos]
+			d.pos += 1
+			switch e {
+			case '"', '\\':
+				sb.WriteByte(e)
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				var r, err = d.hex()
+				if err != nil {
+					return nil, err
+				}
+				sb.WriteRune(r)
+			default:
+				return nil, d.errorf("invalid escape \\%c in string", e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return nil, d.errorf("unterminated string")
+}
+
+// hex parses the four hex digits of a \u escape.
+func (d *decoder) hex() (rune, error) {
+	if d.pos+4 > len(d.data) {
+		return 0, d.errorf("invalid unicode escape")
+	}
+	var n, err = strconv.ParseUint(string(d.data[d.pos:d.pos+4]), 16, 16)
+	if err != nil {
+		return 0, d.errorf("invalid unicode escape")
+	}
+	d.pos += 4
+	return rune(n), nil
+}
+
+func (d *decoder) char() (any, error) {
+	d.pos += 1
+	if d.pos == len(d.data) {
+		return nil, d.errorf("unexpected end of input")
+	}
+
+	// The first character is taken even if it is a delimiter, as in \( or
+	// \space.
+	var r, size = utf8.DecodeRune(d.data[d.pos:])
+	d.pos += size
+	var start = d.pos - size
+	var rest = d.token()
+	if rest == "" {
+		return Char(r), nil
+	}
+
+	var name = string(d.data[start:d.pos])
+	for c, n := range charNames {
+		if n == name {
+			return c, nil
+		}
+	}
+	if name[0] == 'u' && len(name) == 5 {
+		d.pos = start + 1
+		var r, err = d.hex()
+		return Char(r), err
+	}
+	return nil, d.errorf("invalid character \\%s", name)
+}
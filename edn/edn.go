@@ -0,0 +1,52 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package edn encodes persistent data structures as text in the extensible
+// data notation (EDN) used by the Clojure programming language, and decodes
+// EDN text back into persistent data structures.
+//
+// Values are encoded according to their type:
+//
+//   - nil, booleans, integers, floating point numbers and strings as their EDN
+//     counterparts, with infinities and NaN as ##Inf, ##-Inf and ##NaN, and
+//     unsigned integers too large for an int64 as bigints such as 2N.
+//   - Keyword, Symbol, Char and Tagged values as keywords, symbols,
+//     characters and tagged elements.
+//   - Maps with struct{} values as sets of their keys, and other maps as maps.
+//     As with the paths package, maps are any values with GetOK(key),
+//     Assoc(key, value) and All() methods, such as those of the maps,
+//     sortedmap and btree packages.
+//   - Vectors, being any values with Len(), Nth(index) and Assoc(index, value)
+//     methods, as vectors.
+//   - Lists, being any values with Len(), All(), Insert(index, value) and
+//     Remove(index) methods, as lists.
+//   - Go slices, arrays and maps as vectors and maps, and pointers as the value
+//     they point to.
+//
+// Decoding produces values of dynamic types: nil, bool, int64, uint64 for
+// integers too large for an int64, float64, string, Keyword, Symbol, Char,
+// Tagged, vectors.Vector[any], lists.List[any], maps.HashMap[any, any] for
+// maps and maps.HashMap[any, struct{}] for sets. The keys of decoded maps and
+// sets are hashed and compared with Hash and Equal, so collections such as
+// vectors may be used as keys, and are equal to other collections holding
+// equal values.
+package edn
+
+// Keyword is an EDN keyword, written with a leading colon such as :name or
+// :user/name. The colon isn't part of the Keyword.
+type Keyword string
+
+// Symbol is an EDN symbol, such as name or user/name.
+type Symbol string
+
+// Char is an EDN character, such as \a or \newline.
+type Char rune
+
+// Tagged is an EDN tagged element, such as #inst "1985-04-12T23:20:50.52Z",
+// holding the symbol of the tag without its leading # and the value which
+// follows it.
+type Tagged struct {
+	Tag   Symbol
+	Value any
+}
@@ -0,0 +1,268 @@
+package edn_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/edn"
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/sortedmap"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestMarshal(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"Nil", nil, "nil"},
+		{"Bool", true, "true"},
+		{"Int", -42, "-42"},
+		{"Uint", uint8(7), "7"},
+		{"BigUint", uint64(math.MaxUint64), "18446744073709551615N"},
+		{"Float", 1.5, "1.5"},
+		{"WholeFloat", 2.0, "2.0"},
+		{"Exponent", 1e21, "1e+21"},
+		{"Inf", math.Inf(-1), "##-Inf"},
+		{"NaN", math.NaN(), "##NaN"},
+		{"String", "a \"b\"\n\x01", `"a \"b\"\n\u0001"`},
+		{"Keyword", edn.Keyword("user/name"), ":user/name"},
+		{"Symbol", edn.Symbol("inc"), "inc"},
+		{"Char", edn.Char('a'), `\a`},
+		{"NamedChar", edn.Char('\n'), `\newline`},
+		{"Tagged", edn.Tagged{Tag: "inst", Value: "1985-04-12T23:20:50.52Z"}, `#inst "1985-04-12T23:20:50.52Z"`},
+		{"Vector", vectors.New(1, 2, 3), "[1 2 3]"},
+		{"List", lists.New("a", "b"), `("a" "b")`},
+		{"EmptyList", lists.New[int](), "()"},
+		{"SortedMap", sortedmap.New[edn.Keyword, int]().Assoc("b", 2).Assoc("a", 1), "{:a 1, :b 2}"},
+		{"Set", maps.New[int, struct{}]().Assoc(1, struct{}{}), "#{1}"},
+		{"Nested", vectors.New[any](lists.New[any](), vectors.New[any](nil)), "[() [nil]]"},
+		{"Slice", []string{"x"}, `["x"]`},
+		{"Pointer", new(int), "0"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = edn.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	if _, err := edn.Marshal(vectors.New[any](make(chan int))); err == nil {
+		t.Fatalf("got nil error, want an error")
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Nil", "nil", "nil"},
+		{"Bools", "[true false]", "[true false]"},
+		{"Integers", "[1 -2 +3 4N]", "[1 -2 3 4]"},
+		{"Floats", "[1.5 -2e3 3M]", "[1.5 -2000.0 3.0]"},
+		{"Symbolic", "[##Inf ##-Inf]", "[##Inf ##-Inf]"},
+		{"String", `"a\tbé\\"`, `"a\tbé\\"`},
+		{"Keywords", "[:a :ns/b]", "[:a :ns/b]"},
+		{"Symbols", "[a + ns/b - ->x]", "[a + ns/b - ->x]"},
+		{"Chars", `[\a \newline \A \( \space]`, `[\a \newline \A \( \space]`},
+		{"List", "(1 (2) ())", "(1 (2) ())"},
+		{"Map", "{:a 1}", "{:a 1}"},
+		{"Set", "#{:a}", "#{:a}"},
+		{"Tagged", `#inst "2020"`, `#inst "2020"`},
+		{"Whitespace", " , [1,2 ; comment\n 3] ; end", "[1 2 3]"},
+		{"Discard", "[1 #_ 2 3 #_(4)]", "[1 3]"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var v, err = edn.Unmarshal([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			var got, _ = edn.Marshal(v)
+			if string(got) != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTypes(t *testing.T) {
+	var v, err = edn.Unmarshal([]byte(`{:users [{:name "ann" :age 30}] :tags #{"a" "b"} :log (1 2)}`))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	var root = v.(maps.HashMap[any, any])
+	var users = root.Get(edn.Keyword("users")).(vectors.Vector[any])
+	if got, want := users.Nth(0).(maps.HashMap[any, any]).Get(edn.Keyword("age")), int64(30); got != want {
+		t.Fatalf("got age %v, want %v", got, want)
+	}
+	var tags = root.Get(edn.Keyword("tags")).(maps.HashMap[any, struct{}])
+	if !tags.Contains("a") || tags.Len() != 2 {
+		t.Fatalf("got tags %v, want a set of a and b", tags)
+	}
+	var log = root.Get(edn.Keyword("log")).(lists.List[any])
+	if got, want := log.String(), "(1 2)"; got != want {
+		t.Fatalf("got log %s, want %s", got, want)
+	}
+}
+
+func TestUnmarshalCollectionKeys(t *testing.T) {
+	var v, err = edn.Unmarshal([]byte(`{[1 2] :vector, {:a 1} :map, (1 2) :list, #{1} :set, #tag [1] :tagged}`))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	var m = v.(maps.HashMap[any, any])
+	var keys = []struct {
+		key  any
+		want edn.Keyword
+	}{
+		{vectors.New[any](int64(1), int64(2)), "vector"},
+		{maps.NewWith[any, any](edn.Hash, edn.Equal).Assoc(edn.Keyword("a"), int64(1)), "map"},
+		{lists.New[any](int64(1), int64(2)), "list"},
+		{maps.NewWith[any, struct{}](edn.Hash, edn.Equal).Assoc(int64(1), struct{}{}), "set"},
+		{edn.Tagged{Tag: "tag", Value: vectors.New[any](int64(1))}, "tagged"},
+	}
+	for _, k := range keys {
+		if got, ok := m.GetOK(k.key); !ok || got != k.want {
+			t.Fatalf("got %v, %t for key %v, want %v, true", got, ok, k.key, k.want)
+		}
+	}
+	if m.Contains(vectors.New[any](int64(2), int64(1))) {
+		t.Fatalf("got Contains([2 1])=true, want false")
+	}
+	if m.Contains(lists.New[any](int64(1))) {
+		t.Fatalf("got Contains((1))=true, want false")
+	}
+}
+
+func TestUnmarshalSets(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		input string
+		len   int
+	}{
+		{"Vectors", "#{[1 2] [3]}", 2},
+		{"Maps", "#{{:a 1} {:a 2} {:b 1}}", 3},
+		{"Sets", "#{#{1} #{1 2}}", 2},
+		{"VectorAndList", "#{[1] (1)}", 2},
+		{"IntAndFloat", "#{1 1.0}", 2},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var v, err = edn.Unmarshal([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got := v.(maps.HashMap[any, struct{}]).Len(); got != tc.len {
+				t.Fatalf("got Len()=%d, want Len()=%d", got, tc.len)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	var testCases = []struct {
+		a, b string
+		want bool
+	}{
+		{"{:a [1 2], :b #{3}}", "{:b #{3}, :a [1 2]}", true},
+		{"{:a [1 2]}", "{:a [1 3]}", false},
+		{"{:a 1}", "{:a 1, :b 2}", false},
+		{"#{1 2 3}", "#{3 2 1}", true},
+		{"(1 [2])", "(1 [2])", true},
+		{"(1 2)", "[1 2]", false},
+		{`#tag {:a 1}`, `#tag {:a 1}`, true},
+		{`#tag {:a 1}`, `#other {:a 1}`, false},
+	}
+
+	for _, tc := range testCases {
+		var a, err = edn.Unmarshal([]byte(tc.a))
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		var b any
+		if b, err = edn.Unmarshal([]byte(tc.b)); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got := edn.Equal(a, b); got != tc.want {
+			t.Fatalf("got Equal(%s, %s)=%t, want %t", tc.a, tc.b, got, tc.want)
+		}
+		if tc.want && edn.Hash(a) != edn.Hash(b) {
+			t.Fatalf("got different hashes for equal values %s and %s", tc.a, tc.b)
+		}
+	}
+}
+
+func TestRoundTripIntegers(t *testing.T) {
+	for _, want := range []any{int64(math.MinInt64), int64(math.MaxInt64), uint64(math.MaxInt64 + 1), uint64(math.MaxUint64)} {
+		var data, err = edn.Marshal(want)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		var got any
+		if got, err = edn.Unmarshal(data); err != nil {
+			t.Fatalf("got error %v for %s, want nil", err, data)
+		}
+		if got != want {
+			t.Fatalf("got %#v, want %#v", got, want)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		input string
+	}{
+		{"Empty", ""},
+		{"Unclosed", "[1 2"},
+		{"Mismatched", "[1 2)"},
+		{"Unexpected", ")"},
+		{"Trailing", "1 2"},
+		{"OddMap", "{:a}"},
+		{"DuplicateKey", "{:a 1 :a 2}"},
+		{"DuplicateElement", "#{1 1}"},
+		{"DuplicateMapKey", "{{:a 1} 1, {:a 1} 2}"},
+		{"DuplicateVectorKey", "{[1 2] 1, [1 2] 2}"},
+		{"DuplicateSetElement", "#{#{1 2} #{2 1}}"},
+		{"String", `"abc`},
+		{"Escape", `"\q"`},
+		{"Number", "1.2.3"},
+		{"BigInt", "18446744073709551616N"},
+		{"Char", `\nope`},
+		{"Symbolic", "##Nope"},
+		{"Keyword", ": 1"},
+		{"Tag", "#1 2"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if v, err := edn.Unmarshal([]byte(tc.input)); err == nil {
+				t.Fatalf("got %v and nil error, want an error", v)
+			} else if !strings.HasPrefix(err.Error(), "edn: ") {
+				t.Fatalf("got error %q, want an edn error", err)
+			}
+		})
+	}
+}
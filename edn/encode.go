@@ -0,0 +1,222 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package edn
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Marshal returns the EDN encoding of v. An error is returned if v or any
+// value within it is of a type which can't be encoded.
+func Marshal(v any) ([]byte, error) {
+	return appendValue(nil, reflect.ValueOf(v))
+}
+
+var charNames = map[Char]string{
+	'\n': "newline",
+	'\r': "return",
+	' ':  "space",
+	'\t': "tab",
+}
+
+func appendValue(data []byte, v reflect.Value) ([]byte, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return append(data, "nil"...), nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return append(data, "nil"...), nil
+	}
+
+	switch x := v.Interface().(type) {
+	case Keyword:
+		return append(append(data, ':'), x...), nil
+	case Symbol:
+		return append(data, x...), nil
+	case Char:
+		if name, ok := charNames[x]; ok {
+			return append(append(data, '\\'), name...), nil
+		}
+		if x < ' ' || x == utf8.RuneError {
+			return fmt.Appendf(data, "\\u%04x", x), nil
+		}
+		return utf8.AppendRune(append(data, '\\'), rune(x)), nil
+	case Tagged:
+		data = append(append(append(data, '#'), x.Tag...), ' ')
+		return appendValue(data, reflect.ValueOf(x.Value))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(data, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(data, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		data = strconv.AppendUint(data, v.Uint(), 10)
+		if v.Uint() > math.MaxInt64 {
+			// Integers too large for a long are written as EDN bigints.
+			data = append(data, 'N')
+		}
+		return data, nil
+	case reflect.Float32, reflect.Float64:
+		return appendFloat(data, v.Float()), nil
+	case reflect.String:
+		return appendString(data, v.String()), nil
+	}
+
+	if has(v, "GetOK", "Assoc", "All") {
+		if v.MethodByName("GetOK").Type().Out(0) == reflect.TypeFor[struct{}]() {
+			return appendSet(data, v)
+		}
+		return appendMap(data, v)
+	}
+	if has(v, "Len", "Nth", "Assoc") {
+		var n = int(v.MethodByName("Len").Call(nil)[0].Int())
+		var nth = v.MethodByName("Nth")
+		return appendSeq(data, '[', ']', n, func(i int) reflect.Value {
+			return nth.Call([]reflect.Value{reflect.ValueOf(i)})[0]
+		})
+	}
+	if has(v, "Len", "All", "Insert", "Remove") {
+		var items = collect(v)
+		return appendSeq(data, '(', ')', len(items), func(i int) reflect.Value {
+			return items[i][0]
+		})
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return appendSeq(data, '[', ']', v.Len(), v.Index)
+	case reflect.Map:
+		var entries = make([][2]reflect.Value, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			entries = append(entries, [2]reflect.Value{it.Key(), it.Value()})
+		}
+		return appendEntries(data, entries)
+	}
+
+	return nil, fmt.Errorf("edn: cannot encode a value of type %s", v.Type())
+}
+
+func appendFloat(data []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(data, "##NaN"...)
+	case math.IsInf(f, 1):
+		return append(data, "##Inf"...)
+	case math.IsInf(f, -1):
+		return append(data, "##-Inf"...)
+	}
+
+	var start = len(data)
+	data = strconv.AppendFloat(data, f, 'g', -1, 64)
+	if !strings.ContainsAny(string(data[start:]), ".e") {
+		// Without a decimal point or exponent the number would be read as
+		// an integer.
+		data = append(data, ".0"...)
+	}
+	return data
+}
+
+func appendString(data []byte, s string) []byte {
+	data = append(data, '"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			data = append(data, `\"`...)
+		case '\\':
+			data = append(data, `\\`...)
+		case '\n':
+			data = append(data, `\n`...)
+		case '\r':
+			data = append(data, `\r`...)
+		case '\t':
+			data = append(data, `\t`...)
+		default:
+			if r < ' ' {
+				data = fmt.Appendf(data, `\u%04x`, r)
+			} else {
+				data = utf8.AppendRune(data, r)
+			}
+		}
+	}
+	return append(data, '"')
+}
+
+func appendSeq(data []byte, open, close byte, n int, nth func(int) reflect.Value) ([]byte, error) {
+	data = append(data, open)
+	for i := 0; i < n; i += 1 {
+		if i > 0 {
+			data = append(data, ' ')
+		}
+		var err error
+		if data, err = appendValue(data, nth(i)); err != nil {
+			return nil, err
+		}
+	}
+	return append(data, close), nil
+}
+
+func appendSet(data []byte, v reflect.Value) ([]byte, error) {
+	var entries = collect(v)
+	return appendSeq(append(data, '#'), '{', '}', len(entries), func(i int) reflect.Value {
+		return entries[i][0]
+	})
+}
+
+func appendMap(data []byte, v reflect.Value) ([]byte, error) {
+	return appendEntries(data, collect(v))
+}
+
+func appendEntries(data []byte, entries [][2]reflect.Value) ([]byte, error) {
+	data = append(data, '{')
+	for i, e := range entries {
+		if i > 0 {
+			data = append(data, ", "...)
+		}
+		var err error
+		if data, err = appendValue(data, e[0]); err != nil {
+			return nil, err
+		}
+		data = append(data, ' ')
+		if data, err = appendValue(data, e[1]); err != nil {
+			return nil, err
+		}
+	}
+	return append(data, '}'), nil
+}
+
+// has returns true if v has every method in names.
+func has(v reflect.Value, names ...string) bool {
+	for _, name := range names {
+		if !v.MethodByName(name).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// collect returns the values yielded by each step of the iterator returned by
+// calling the All method of v, with the second value being invalid for an
+// iter.Seq.
+func collect(v reflect.Value) [][2]reflect.Value {
+	var values [][2]reflect.Value
+	var all = v.MethodByName("All").Call(nil)[0]
+	var yield = reflect.MakeFunc(all.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		var step [2]reflect.Value
+		copy(step[:], args)
+		values = append(values, step)
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+	all.Call([]reflect.Value{yield})
+	return values
+}
@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package edn
+
+import (
+	"hash/maphash"
+
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+// seed is the seed used by Hash, chosen randomly for each process.
+var seed = maphash.MakeSeed()
+
+// Equal returns true if a and b, which are values as produced by Unmarshal,
+// are equal. Vectors and lists are equal if they hold equal values in the
+// same order, maps if they associate equal keys to equal values, sets if they
+// hold equal elements, and tagged values if they have the same tag and equal
+// values. Any other values are equal if they have the same type and are ==.
+func Equal(a, b any) bool {
+	switch x := a.(type) {
+	case vectors.Vector[any]:
+		var y, ok = b.(vectors.Vector[any])
+		return ok && vectors.EqualFunc(x, y, Equal)
+	case lists.List[any]:
+		var y, ok = b.(lists.List[any])
+		if !ok || x.Len() != y.Len() {
+			return false
+		}
+		for ; x.Len() > 0; x, y = x.Rest(), y.Rest() {
+			if !Equal(x.First(), y.First()) {
+				return false
+			}
+		}
+		return true
+	case maps.HashMap[any, any]:
+		var y, ok = b.(maps.HashMap[any, any])
+		if !ok || x.Len() != y.Len() {
+			return false
+		}
+		for key, value := range x.All() {
+			if other, ok := y.GetOK(key); !ok || !Equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case maps.HashMap[any, struct{}]:
+		var y, ok = b.(maps.HashMap[any, struct{}])
+		if !ok || x.Len() != y.Len() {
+			return false
+		}
+		for key := range x.Keys() {
+			if !y.Contains(key) {
+				return false
+			}
+		}
+		return true
+	case Tagged:
+		var y, ok = b.(Tagged)
+		return ok && x.Tag == y.Tag && Equal(x.Value, y.Value)
+	}
+	return a == b
+}
+
+// Hash returns a hash of v, which is a value as produced by Unmarshal, such
+// that values which are Equal have the same hash. Hashes are computed with a
+// seed chosen randomly for each process. Maps and sets produced by Unmarshal
+// hash and compare their keys with Hash and Equal.
+func Hash(v any) uint64 {
+	switch x := v.(type) {
+	case vectors.Vector[any]:
+		return mix('[', vectors.Hash(x, Hash), 0)
+	case lists.List[any]:
+		var sum uint64
+		for item := range x.All() {
+			sum = mix('(', sum, Hash(item))
+		}
+		return sum
+	case maps.HashMap[any, any]:
+		// Entries are summed, so the hash doesn't depend on their order.
+		var sum uint64
+		for key, value := range x.All() {
+			sum += mix(':', Hash(key), Hash(value))
+		}
+		return mix('{', sum, uint64(x.Len()))
+	case maps.HashMap[any, struct{}]:
+		var sum uint64
+		for key := range x.Keys() {
+			sum += Hash(key)
+		}
+		return mix('#', sum, uint64(x.Len()))
+	case Tagged:
+		return mix('t', Hash(x.Tag), Hash(x.Value))
+	}
+	return maphash.Comparable(seed, v)
+}
+
+// mix returns a hash of a and b, which is different for each kind of value
+// given by tag.
+func mix(tag byte, a, b uint64) uint64 {
+	return maphash.Comparable(seed, struct {
+		tag  byte
+		a, b uint64
+	}{tag, a, b})
+}
@@ -0,0 +1,150 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package fenwick provides a persistent Fenwick tree, also known as a binary
+// indexed tree, which holds a sequence of numbers and finds the sum of any
+// range of them in O(log n) time. The tree is stored in a persistent vector
+// of partial sums, so updating a value copies only the O(log n) paths through
+// the vector to the partial sums including it, while every version of the
+// tree remains usable.
+package fenwick
+
+import (
+	"fmt"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+// Number is the set of types which can be summed by a Tree.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// lowbit returns the lowest set bit of i.
+func lowbit(i int) int {
+	return i & -i
+}
+
+// Tree is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Add'ing to a value of a Tree, the previous version of
+// that Tree can be used in more operations and referenced without having been
+// mutated from any operations it was used as input for. The zero value of Tree
+// is an empty tree ready to use.
+type Tree[T Number] struct {
+	// Partial sums, where sums[i] is the sum of the values from index
+	// i+1-lowbit(i+1) to index i inclusive.
+	sums vectors.Vector[T]
+}
+
+// New creates a new tree holding values, in O(n) time.
+func New[T Number](values ...T) Tree[T] {
+	var sums = make([]T, len(values))
+	copy(sums, values)
+	for i := range sums {
+		if j := i + lowbit(i+1); j < len(sums) {
+			sums[j] += sums[i]
+		}
+	}
+	return Tree[T]{sums: vectors.New(sums...)}
+}
+
+// FromVector creates a new tree holding the values of v, in O(n) time.
+func FromVector[T Number](v vectors.Vector[T]) Tree[T] {
+	var values = make([]T, v.Len())
+	v.CopyTo(values)
+	return New(values...)
+}
+
+// Len returns the number of values in t.
+func (t Tree[T]) Len() int {
+	return t.sums.Len()
+}
+
+func (t Tree[T]) check(index int) {
+	if index < 0 || index >= t.sums.Len() {
+		panic(fmt.Sprintf("fenwick: index out of range [%d] with length %d", index, t.sums.Len()))
+	}
+}
+
+// PrefixSum returns the sum of the first n values of t. The count n must be
+// between zero and t.Len(), inclusive.
+func (t Tree[T]) PrefixSum(n int) T {
+	if n < 0 || n > t.sums.Len() {
+		panic(fmt.Sprintf("fenwick: count out of range [%d] with length %d", n, t.sums.Len()))
+	}
+
+	var sum T
+	for ; n > 0; n -= lowbit(n) {
+		sum += t.sums.Nth(n - 1)
+	}
+	return sum
+}
+
+// RangeSum returns the sum of the values from index lo (inclusive) to hi
+// (exclusive). The range must satisfy 0 <= lo <= hi <= t.Len().
+func (t Tree[T]) RangeSum(lo, hi int) T {
+	if lo < 0 || hi < lo || hi > t.sums.Len() {
+		panic(fmt.Sprintf("fenwick: slice bounds out of range [%d:%d] with length %d", lo, hi, t.sums.Len()))
+	}
+
+	// The partial sums shared by both prefixes cancel out, so only those
+	// above their common part are summed.
+	var sum T
+	for ; hi > lo; hi -= lowbit(hi) {
+		sum += t.sums.Nth(hi - 1)
+	}
+	for ; lo > hi; lo -= lowbit(lo) {
+		sum -= t.sums.Nth(lo - 1)
+	}
+	return sum
+}
+
+// Get returns the value at index.
+func (t Tree[T]) Get(index int) T {
+	t.check(index)
+	return t.RangeSum(index, index+1)
+}
+
+// Add returns a new tree with delta added to the value at index.
+func (t Tree[T]) Add(index int, delta T) Tree[T] {
+	t.check(index)
+
+	var tv = t.sums.Transient()
+	for i := index + 1; i <= tv.Len(); i += lowbit(i) {
+		tv.Assoc(i-1, tv.Nth(i-1)+delta)
+	}
+	return Tree[T]{sums: tv.Persistent()}
+}
+
+// Set returns a new tree with the value at index replaced by value.
+func (t Tree[T]) Set(index int, value T) Tree[T] {
+	return t.Add(index, value-t.Get(index))
+}
+
+// Conj returns a new tree with value appended to the end of t.
+func (t Tree[T]) Conj(value T) Tree[T] {
+	// The new partial sum covers value and the values before it which are
+	// covered by the partial sums it replaces as the end of the tree.
+	var n = t.sums.Len()
+	var sum = value + t.RangeSum(n+1-lowbit(n+1), n)
+	return Tree[T]{sums: t.sums.Conj(sum)}
+}
+
+// Values returns a new vector holding the values of t.
+func (t Tree[T]) Values() vectors.Vector[T] {
+	var b vectors.Builder[T]
+	for i := 0; i < t.sums.Len(); i += 1 {
+		b.Append(t.Get(i))
+	}
+	return b.Vector()
+}
+
+// String returns a representation of the values of a tree in the same form as
+// a vector.
+func (t Tree[T]) String() string {
+	return t.Values().String()
+}
@@ -0,0 +1,141 @@
+package fenwick_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/toddgaunt/persistent/fenwick"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestTreeSums(t *testing.T) {
+	var tree = fenwick.New(3, 1, 4, 1, 5, 9, 2, 6)
+
+	var testCases = []struct {
+		name   string
+		lo, hi int
+		want   int
+	}{
+		{"Empty", 3, 3, 0},
+		{"All", 0, 8, 31},
+		{"Prefix", 0, 5, 14},
+		{"Suffix", 5, 8, 17},
+		{"Middle", 2, 6, 19},
+		{"Single", 7, 8, 6},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tree.RangeSum(tc.lo, tc.hi); got != tc.want {
+				t.Fatalf("got RangeSum(%d, %d)=%d, want %d", tc.lo, tc.hi, got, tc.want)
+			}
+			if tc.lo == 0 {
+				if got := tree.PrefixSum(tc.hi); got != tc.want {
+					t.Fatalf("got PrefixSum(%d)=%d, want %d", tc.hi, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTreeUpdate(t *testing.T) {
+	var t1 = fenwick.FromVector(vectors.New(1.5, 2, 3))
+	var t2 = t1.Add(1, 10).Set(2, 0.5).Conj(4)
+
+	if got, want := t2.String(), "[1.5 12 0.5 4]"; got != want {
+		t.Fatalf("got t2 %s, want %s", got, want)
+	}
+	if got, want := t1.String(), "[1.5 2 3]"; got != want {
+		t.Fatalf("got t1 %s, want %s", got, want)
+	}
+	if got, want := t2.PrefixSum(4), 18.0; got != want {
+		t.Fatalf("got PrefixSum(4)=%v, want %v", got, want)
+	}
+}
+
+func TestTreeModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(17, 18))
+	var model []int
+	var tree fenwick.Tree[int]
+	var versions []fenwick.Tree[int]
+	var models [][]int
+
+	for i := 0; i < 5000; i++ {
+		if len(model) == 0 || r.IntN(4) == 0 {
+			var value = r.IntN(100)
+			model = append(model, value)
+			tree = tree.Conj(value)
+		} else {
+			var index, delta = r.IntN(len(model)), r.IntN(21) - 10
+			model[index] += delta
+			tree = tree.Add(index, delta)
+		}
+		if i%500 == 0 {
+			versions = append(versions, tree)
+			models = append(models, append([]int(nil), model...))
+		}
+	}
+	versions = append(versions, fenwick.New(model...))
+	models = append(models, model)
+
+	for i, version := range versions {
+		var want = models[i]
+		if got := version.Len(); got != len(want) {
+			t.Fatalf("version %d: got Len()=%d, want Len()=%d", i, got, len(want))
+		}
+		for j := 0; j < 50; j++ {
+			var lo = r.IntN(len(want) + 1)
+			var hi = lo + r.IntN(len(want)-lo+1)
+			var sum = 0
+			for _, value := range want[lo:hi] {
+				sum += value
+			}
+			if got := version.RangeSum(lo, hi); got != sum {
+				t.Fatalf("version %d: got RangeSum(%d, %d)=%d, want %d", i, lo, hi, got, sum)
+			}
+		}
+	}
+}
+
+func TestTreePanics(t *testing.T) {
+	var tree = fenwick.New(1, 2, 3)
+	var testCases = []struct {
+		name string
+		f    func()
+	}{
+		{"Get", func() { tree.Get(3) }},
+		{"Add", func() { tree.Add(-1, 1) }},
+		{"PrefixSum", func() { tree.PrefixSum(4) }},
+		{"RangeSum", func() { tree.RangeSum(2, 1) }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func BenchmarkTreeAdd(b *testing.B) {
+	var tree = fenwick.New(make([]int, 1000000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree = tree.Add(i%1000000, 1)
+	}
+}
+
+func BenchmarkTreeRangeSum(b *testing.B) {
+	var tree = fenwick.New(make([]int, 1000000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var lo = i % 500000
+		_ = tree.RangeSum(lo, lo+500000)
+	}
+}
@@ -0,0 +1,386 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package intervals provides a persistent Map from half-open intervals to
+// values, which can be queried for the intervals containing a point or
+// overlapping another interval. The implementation is a weight-balanced
+// binary tree ordered by the start of each interval, where each node also
+// records the greatest end of any interval beneath it so that subtrees which
+// can't hold a match are skipped.
+package intervals
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// These constants determine how unbalanced the tree may become before it is
+// rebalanced. A node is rebalanced once one of its subtrees is more than delta
+// times the size of the other, and ratio decides whether a single or double
+// rotation is used to do so.
+const (
+	delta = 3
+	ratio = 2
+)
+
+// Interval is the half-open interval [Lo, Hi), containing every point from Lo
+// up to but not including Hi.
+type Interval[K cmp.Ordered] struct {
+	Lo, Hi K
+}
+
+// Contains returns true if point is within i.
+func (i Interval[K]) Contains(point K) bool {
+	return i.Lo <= point && point < i.Hi
+}
+
+// Overlaps returns true if i and other have any point in common, which an
+// empty interval never does.
+func (i Interval[K]) Overlaps(other Interval[K]) bool {
+	return i.Lo < other.Hi && other.Lo < i.Hi && i.Lo < i.Hi && other.Lo < other.Hi
+}
+
+// String returns a representation of an interval as [Lo, Hi).
+func (i Interval[K]) String() string {
+	return fmt.Sprintf("[%v, %v)", i.Lo, i.Hi)
+}
+
+func compare[K cmp.Ordered](a, b Interval[K]) int {
+	if c := cmp.Compare(a.Lo, b.Lo); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Hi, b.Hi)
+}
+
+type node[K cmp.Ordered, V any] struct {
+	interval Interval[K]
+	value    V
+	size     int // Number of nodes in the subtree rooted at this node
+	max      K   // Greatest Hi of any interval in the subtree rooted at this node
+	left     *node[K, V]
+	right    *node[K, V]
+}
+
+func size[K cmp.Ordered, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func newNode[K cmp.Ordered, V any](interval Interval[K], value V, left, right *node[K, V]) *node[K, V] {
+	var n = &node[K, V]{
+		interval: interval,
+		value:    value,
+		size:     size(left) + size(right) + 1,
+		max:      interval.Hi,
+		left:     left,
+		right:    right,
+	}
+	if left != nil && left.max > n.max {
+		n.max = left.max
+	}
+	if right != nil && right.max > n.max {
+		n.max = right.max
+	}
+	return n
+}
+
+// balance returns a new node for interval and value with the subtrees left
+// and right, rotating them if one has grown too large compared to the other.
+// Only a single entry may have been added to or removed from either subtree
+// since they were last balanced.
+func balance[K cmp.Ordered, V any](interval Interval[K], value V, left, right *node[K, V]) *node[K, V] {
+	var sl, sr = size(left), size(right)
+	switch {
+	case sl+sr <= 1:
+		return newNode(interval, value, left, right)
+	case sr > delta*sl:
+		if size(right.left) < ratio*size(right.right) {
+			// Single left rotation.
+			return newNode(right.interval, right.value,
+				newNode(interval, value, left, right.left),
+				right.right)
+		}
+		// Double left rotation.
+		var rl = right.left
+		return newNode(rl.interval, rl.value,
+			newNode(interval, value, left, rl.left),
+			newNode(right.interval, right.value, rl.right, right.right))
+	case sl > delta*sr:
+		if size(left.right) < ratio*size(left.left) {
+			// Single right rotation.
+			return newNode(left.interval, left.value,
+				left.left,
+				newNode(interval, value, left.right, right))
+		}
+		// Double right rotation.
+		var lr = left.right
+		return newNode(lr.interval, lr.value,
+			newNode(left.interval, left.value, left.left, lr.left),
+			newNode(interval, value, lr.right, right))
+	default:
+		return newNode(interval, value, left, right)
+	}
+}
+
+// assoc returns a new node with interval associated to value within the
+// subtree rooted at n.
+func (n *node[K, V]) assoc(interval Interval[K], value V) *node[K, V] {
+	if n == nil {
+		return newNode[K, V](interval, value, nil, nil)
+	}
+
+	var c = compare(interval, n.interval)
+	switch {
+	case c < 0:
+		return balance(n.interval, n.value, n.left.assoc(interval, value), n.right)
+	case c > 0:
+		return balance(n.interval, n.value, n.left, n.right.assoc(interval, value))
+	default:
+		return newNode(interval, value, n.left, n.right)
+	}
+}
+
+// dissoc returns a new node without interval within the subtree rooted at n,
+// and true if there was an entry for interval to remove. If there wasn't, n
+// itself is returned.
+func (n *node[K, V]) dissoc(interval Interval[K]) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var c = compare(interval, n.interval)
+	switch {
+	case c < 0:
+		var left, removed = n.left.dissoc(interval)
+		if !removed {
+			return n, false
+		}
+		return balance(n.interval, n.value, left, n.right), true
+	case c > 0:
+		var right, removed = n.right.dissoc(interval)
+		if !removed {
+			return n, false
+		}
+		return balance(n.interval, n.value, n.left, right), true
+	default:
+		return glue(n.left, n.right), true
+	}
+}
+
+// glue returns a balanced node containing the entries of left and right, where
+// every interval in left is ordered before every interval in right.
+func glue[K cmp.Ordered, V any](left, right *node[K, V]) *node[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.size > right.size:
+		var max, rest = left.popMax()
+		return balance(max.interval, max.value, rest, right)
+	default:
+		var min, rest = right.popMin()
+		return balance(min.interval, min.value, left, rest)
+	}
+}
+
+// popMin returns the node with the first interval within the subtree rooted
+// at n, and the subtree without it.
+func (n *node[K, V]) popMin() (*node[K, V], *node[K, V]) {
+	if n.left == nil {
+		return n, n.right
+	}
+	var min, left = n.left.popMin()
+	return min, balance(n.interval, n.value, left, n.right)
+}
+
+// popMax returns the node with the last interval within the subtree rooted at
+// n, and the subtree without it.
+func (n *node[K, V]) popMax() (*node[K, V], *node[K, V]) {
+	if n.right == nil {
+		return n, n.left
+	}
+	var max, right = n.right.popMax()
+	return max, balance(n.interval, n.value, n.left, right)
+}
+
+// find returns the node for interval within the subtree rooted at n, or nil if
+// there isn't one.
+func (n *node[K, V]) find(interval Interval[K]) *node[K, V] {
+	for n != nil {
+		var c = compare(interval, n.interval)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// overlapping calls yield in order with each node within the subtree rooted
+// at n with an interval overlapping query. Subtrees with no interval ending
+// after the start of query, or starting before the end of query, are skipped.
+// Iteration stops early if yield returns false, in which case overlapping
+// also returns false.
+func (n *node[K, V]) overlapping(query Interval[K], yield func(*node[K, V]) bool) bool {
+	if n == nil || n.max <= query.Lo {
+		return true
+	}
+	if !n.left.overlapping(query, yield) {
+		return false
+	}
+	if n.interval.Lo >= query.Hi {
+		// Every interval to the right starts at or after this one.
+		return true
+	}
+	if n.interval.Overlaps(query) && !yield(n) {
+		return false
+	}
+	return n.right.overlapping(query, yield)
+}
+
+// stabbing is like overlapping, but for the nodes with an interval containing
+// point.
+func (n *node[K, V]) stabbing(point K, yield func(*node[K, V]) bool) bool {
+	if n == nil || n.max <= point {
+		return true
+	}
+	if !n.left.stabbing(point, yield) {
+		return false
+	}
+	if n.interval.Lo > point {
+		// Every interval to the right starts after point.
+		return true
+	}
+	if n.interval.Contains(point) && !yield(n) {
+		return false
+	}
+	return n.right.stabbing(point, yield)
+}
+
+func (n *node[K, V]) forEach(yield func(*node[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.forEach(yield) && yield(n) && n.right.forEach(yield)
+}
+
+// Map is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Assoc'ing an interval to a Map, the previous version of
+// that Map can be used in more operations and referenced without having been
+// mutated from any operations it was used as input for. Only the nodes along
+// the path to the interval are copied by each operation, while the rest are
+// shared between both versions. Intervals are ordered by their start, and then
+// by their end. The zero value of Map is an empty map ready to use.
+type Map[K cmp.Ordered, V any] struct {
+	root *node[K, V]
+}
+
+// New creates a new empty persistent interval map.
+func New[K cmp.Ordered, V any]() Map[K, V] {
+	return Map[K, V]{}
+}
+
+// Len returns the number of intervals in m.
+func (m Map[K, V]) Len() int {
+	return size(m.root)
+}
+
+// Get returns the value associated with the interval [lo, hi), or the zero
+// value if the interval isn't present in m.
+func (m Map[K, V]) Get(lo, hi K) V {
+	var value, _ = m.GetOK(lo, hi)
+	return value
+}
+
+// GetOK returns the value associated with the interval [lo, hi) and true, or
+// the zero value and false if the interval isn't present in m.
+func (m Map[K, V]) GetOK(lo, hi K) (V, bool) {
+	if n := m.root.find(Interval[K]{lo, hi}); n != nil {
+		return n.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Assoc returns a new map with the interval [lo, hi) associated to value,
+// replacing any value the same interval was already associated to. Intervals
+// which only overlap it are unaffected. The start of the interval must be
+// less than its end.
+func (m Map[K, V]) Assoc(lo, hi K, value V) Map[K, V] {
+	if !(lo < hi) {
+		panic(fmt.Sprintf("intervals: empty interval [%v, %v)", lo, hi))
+	}
+	return Map[K, V]{root: m.root.assoc(Interval[K]{lo, hi}, value)}
+}
+
+// Dissoc returns a new map without the interval [lo, hi). If the interval
+// isn't present in m, m itself is returned.
+func (m Map[K, V]) Dissoc(lo, hi K) Map[K, V] {
+	var root, removed = m.root.dissoc(Interval[K]{lo, hi})
+	if !removed {
+		return m
+	}
+	return Map[K, V]{root: root}
+}
+
+// All returns an iterator over each interval and value of m, in order.
+func (m Map[K, V]) All() iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		m.root.forEach(func(n *node[K, V]) bool {
+			return yield(n.interval, n.value)
+		})
+	}
+}
+
+// Stab returns an iterator over each interval and value of m with an interval
+// containing point, in order.
+func (m Map[K, V]) Stab(point K) iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		m.root.stabbing(point, func(n *node[K, V]) bool {
+			return yield(n.interval, n.value)
+		})
+	}
+}
+
+// Overlapping returns an iterator over each interval and value of m with an
+// interval overlapping [lo, hi), in order.
+func (m Map[K, V]) Overlapping(lo, hi K) iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		m.root.overlapping(Interval[K]{lo, hi}, func(n *node[K, V]) bool {
+			return yield(n.interval, n.value)
+		})
+	}
+}
+
+// String returns a representation of a map with its intervals in order:
+//
+//	With no entries: map[]
+//	With one entry: map[[1, 2):a]
+//	With more than one entry: map[[1, 2):a [1, 3):b]
+func (m Map[K, V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("map[")
+	var first = true
+	for interval, value := range m.All() {
+		if !first {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%v:%v", interval, value)
+		first = false
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,168 @@
+package intervals_test
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/intervals"
+)
+
+type entry struct {
+	interval intervals.Interval[int]
+	value    int
+}
+
+func collect(seq func(func(intervals.Interval[int], int) bool)) []entry {
+	var entries []entry
+	for interval, value := range seq {
+		entries = append(entries, entry{interval, value})
+	}
+	return entries
+}
+
+func compareEntries(a, b entry) int {
+	if c := cmp.Compare(a.interval.Lo, b.interval.Lo); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.interval.Hi, b.interval.Hi)
+}
+
+func TestMapEmpty(t *testing.T) {
+	var m intervals.Map[int, string]
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if _, ok := m.GetOK(1, 2); ok {
+		t.Fatalf("got GetOK(1, 2) ok on an empty map, want !ok")
+	}
+	if got := collect(intervals.Map[int, int]{}.Stab(1)); len(got) != 0 {
+		t.Fatalf("got %v, want no intervals", got)
+	}
+	if got, want := m.String(), "map[]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMapQueries(t *testing.T) {
+	var m = intervals.New[int, int]().
+		Assoc(0, 10, 0).
+		Assoc(5, 15, 1).
+		Assoc(10, 20, 2).
+		Assoc(12, 13, 3).
+		Assoc(30, 40, 4)
+
+	var testCases = []struct {
+		name string
+		got  []entry
+		want []int
+	}{
+		{"StabStart", collect(m.Stab(10)), []int{1, 2}},
+		{"StabInside", collect(m.Stab(12)), []int{1, 2, 3}},
+		{"StabEnd", collect(m.Stab(20)), nil},
+		{"StabGap", collect(m.Stab(25)), nil},
+		{"StabBefore", collect(m.Stab(-1)), nil},
+		{"Overlapping", collect(m.Overlapping(13, 31)), []int{1, 2, 4}},
+		{"OverlappingTouching", collect(m.Overlapping(20, 30)), nil},
+		{"OverlappingAll", collect(m.Overlapping(-100, 100)), []int{0, 1, 2, 3, 4}},
+		{"OverlappingEmpty", collect(m.Overlapping(12, 12)), nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []int
+			for _, e := range tc.got {
+				got = append(got, e.value)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if got, want := m.String(), "map[[0, 10):0 [5, 15):1 [10, 20):2 [12, 13):3 [30, 40):4]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMapAssocDissoc(t *testing.T) {
+	var m1 = intervals.New[int, string]().Assoc(1, 5, "a").Assoc(1, 3, "b")
+	var m2 = m1.Assoc(1, 5, "c").Dissoc(1, 3)
+
+	if got, want := m1.String(), "map[[1, 3):b [1, 5):a]"; got != want {
+		t.Fatalf("got m1 %s, want %s", got, want)
+	}
+	if got, want := m2.String(), "map[[1, 5):c]"; got != want {
+		t.Fatalf("got m2 %s, want %s", got, want)
+	}
+	if got, ok := m2.GetOK(1, 5); got != "c" || !ok {
+		t.Fatalf("got GetOK(1, 5)=%q, %t, want c, true", got, ok)
+	}
+	if got := m2.Get(1, 5); got != "c" {
+		t.Fatalf("got Get(1, 5)=%q, want c", got)
+	}
+	if got := m2.Get(1, 4); got != "" {
+		t.Fatalf("got Get(1, 4)=%q for a missing interval, want the zero value", got)
+	}
+	if got := m2.Dissoc(2, 5); got.Len() != 1 {
+		t.Fatalf("got Len()=%d after dissociating a missing interval, want 1", got.Len())
+	}
+}
+
+func TestMapAssocEmpty(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	intervals.New[int, int]().Assoc(5, 5, 0)
+}
+
+func TestMapModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(13, 14))
+	var model = map[intervals.Interval[int]]int{}
+	var m intervals.Map[int, int]
+
+	for i := 0; i < 5000; i++ {
+		var lo = r.IntN(1000)
+		var interval = intervals.Interval[int]{Lo: lo, Hi: lo + 1 + r.IntN(50)}
+		if r.IntN(4) == 0 {
+			delete(model, interval)
+			m = m.Dissoc(interval.Lo, interval.Hi)
+		} else {
+			model[interval] = i
+			m = m.Assoc(interval.Lo, interval.Hi, i)
+		}
+
+		if i%250 != 0 {
+			continue
+		}
+
+		if got, want := m.Len(), len(model); got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		var point, query = r.IntN(1100), intervals.Interval[int]{Lo: r.IntN(1000)}
+		query.Hi = query.Lo + r.IntN(100)
+
+		var stabbed, overlapping []entry
+		for interval, value := range model {
+			if interval.Contains(point) {
+				stabbed = append(stabbed, entry{interval, value})
+			}
+			if interval.Overlaps(query) {
+				overlapping = append(overlapping, entry{interval, value})
+			}
+		}
+		slices.SortFunc(stabbed, compareEntries)
+		slices.SortFunc(overlapping, compareEntries)
+
+		if got := collect(m.Stab(point)); !slices.Equal(got, stabbed) {
+			t.Fatalf("got Stab(%d)=%v, want %v", point, got, stabbed)
+		}
+		if got := collect(m.Overlapping(query.Lo, query.Hi)); !slices.Equal(got, overlapping) {
+			t.Fatalf("got Overlapping(%v)=%v, want %v", query, got, overlapping)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lazy
+
+// Iterate returns the infinite sequence of seed, f(seed), f(f(seed)) and so
+// on. The function f is called once for each item after the first as it is
+// first needed.
+func Iterate[T any](f func(T) T, seed T) Seq[T] {
+	return Cons(seed, Lazy(func() Seq[T] {
+		return Iterate(f, f(seed))
+	}))
+}
+
+// Repeat returns the infinite sequence of x repeated. The sequence refers back
+// to itself, so it takes up the same memory no matter how far it is walked.
+func Repeat[T any](x T) Seq[T] {
+	var c = &cell[T]{first: x}
+	c.rest = Seq[T]{cell: c}
+	return c.rest
+}
+
+// Cycle returns the infinite sequence of the items of s repeated over and
+// over, or an empty sequence if s is empty. The items of s are computed once
+// and reused by each repetition.
+func Cycle[T any](s Seq[T]) Seq[T] {
+	return Lazy(func() Seq[T] {
+		if s.IsEmpty() {
+			return Seq[T]{}
+		}
+		return cycle(s, s)
+	})
+}
+
+// cycle returns the items of walk followed by the items of s repeated.
+func cycle[T any](s, walk Seq[T]) Seq[T] {
+	return Lazy(func() Seq[T] {
+		if walk.IsEmpty() {
+			walk = s
+		}
+		return Cons(walk.First(), cycle(s, walk.Rest()))
+	})
+}
@@ -0,0 +1,108 @@
+package lazy_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lazy"
+)
+
+func TestTake(t *testing.T) {
+	type testCase struct {
+		title string
+		seq   lazy.Seq[int]
+		n     int
+		want  []int
+	}
+
+	testCases := []testCase{
+		{"Empty", lazy.Of[int](), 3, nil},
+		{"Zero", lazy.Of(1, 2, 3), 0, nil},
+		{"Negative", lazy.Of(1, 2, 3), -1, nil},
+		{"Prefix", lazy.Of(1, 2, 3), 2, []int{1, 2}},
+		{"All", lazy.Of(1, 2, 3), 3, []int{1, 2, 3}},
+		{"Beyond", lazy.Of(1, 2, 3), 5, []int{1, 2, 3}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got = slices.Collect(lazy.Take(tc.seq, tc.n).All())
+			if got, want := fmt.Sprint(got), fmt.Sprint(tc.want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestTakeRealizesOnlyPrefix(t *testing.T) {
+	var calls = 0
+	var seq = lazy.Generate(counter(100, &calls))
+	for range lazy.Take(seq, 3).All() {
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+}
+
+func TestIterate(t *testing.T) {
+	var calls = 0
+	var seq = lazy.Iterate(func(x int) int {
+		calls += 1
+		return x * 2
+	}, 1)
+
+	var got = slices.Collect(lazy.Take(seq, 5).All())
+	if got, want := fmt.Sprint(got), "[1 2 4 8 16]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	for range lazy.Take(seq, 5).All() {
+	}
+	if calls != 4 {
+		t.Fatalf("got %d calls, want 4", calls)
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	var got = slices.Collect(lazy.Take(lazy.Repeat("x"), 3).All())
+	if got, want := fmt.Sprint(got), "[x x x]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	type testCase struct {
+		title string
+		seq   lazy.Seq[int]
+		want  []int
+	}
+
+	testCases := []testCase{
+		{"Empty", lazy.Of[int](), nil},
+		{"SingleItem", lazy.Of(1), []int{1, 1, 1, 1, 1, 1, 1}},
+		{"MultipleItems", lazy.Of(1, 2, 3), []int{1, 2, 3, 1, 2, 3, 1}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got = slices.Collect(lazy.Take(lazy.Cycle(tc.seq), 7).All())
+			if got, want := fmt.Sprint(got), fmt.Sprint(tc.want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestCycleRealizesOnce(t *testing.T) {
+	var calls = 0
+	var seq = lazy.Cycle(lazy.Generate(counter(2, &calls)))
+	for range lazy.Take(seq, 10).All() {
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+}
@@ -0,0 +1,176 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package lazy provides a persistent lazy sequence similar to the one found in
+// the Clojure programming language. The items of a sequence are computed on
+// demand the first time they are needed and remembered afterwards, so a
+// sequence can be walked any number of times while computing each item only
+// once.
+package lazy
+
+import (
+	"iter"
+	"sync"
+)
+
+// Seq is a persistent sequence of items which are only computed once they are
+// first needed. Like a list, a Seq consists of a first item and a Seq of the
+// rest of the items, except that both are computed on demand. The zero value
+// is an empty sequence.
+//
+// Realized items are remembered for as long as the Seq they belong to is
+// reachable, so walking a long sequence while holding onto its head keeps
+// every item walked over in memory.
+type Seq[T any] struct {
+	cell *cell[T]
+}
+
+type cell[T any] struct {
+	once  sync.Once
+	thunk func() Seq[T] // Computes the contents of the cell, nil once realized
+	empty bool
+	first T
+	rest  Seq[T]
+}
+
+// realize computes the contents of c if they haven't been already. It is safe
+// to call from multiple goroutines, though thunk is only ever called once.
+// Since other goroutines calling realize wait for thunk to return, a call made
+// by thunk itself on the same cell deadlocks rather than being detected; Lazy
+// documents this restriction.
+func (c *cell[T]) realize() *cell[T] {
+	c.once.Do(func() {
+		if c.thunk == nil {
+			return
+		}
+
+		var s = c.thunk()
+		c.thunk = nil
+		if s.cell == nil {
+			c.empty = true
+			return
+		}
+
+		var r = s.cell.realize()
+		c.empty, c.first, c.rest = r.empty, r.first, r.rest
+	})
+	return c
+}
+
+// Cons returns a sequence with first as its first item, followed by the items
+// of rest.
+func Cons[T any](first T, rest Seq[T]) Seq[T] {
+	return Seq[T]{cell: &cell[T]{first: first, rest: rest}}
+}
+
+// Lazy returns a sequence of the items of the sequence returned by f. The
+// function f isn't called until an item of the sequence is first needed, and
+// is called at most once.
+//
+// The first item of the sequence returned by f must not depend on the
+// sequence Lazy returns, such as by f calling IsEmpty or First on it or on a
+// sequence derived from it with Map or Filter. Realizing the sequence would
+// then wait on itself and never return. Referring to the sequence only as the
+// rest of an item, as in Cons(x, s), is fine, since the rest isn't realized
+// until it is needed.
+func Lazy[T any](f func() Seq[T]) Seq[T] {
+	return Seq[T]{cell: &cell[T]{thunk: f}}
+}
+
+// Of returns a sequence of the values provided.
+func Of[T any](values ...T) Seq[T] {
+	var s Seq[T]
+	for i := len(values) - 1; i >= 0; i-- {
+		s = Cons(values[i], s)
+	}
+	return s
+}
+
+// Generate returns a sequence of the values returned by successive calls to f,
+// ending at the first call which returns false. The function f is called once
+// for each item as it is first needed, in order.
+func Generate[T any](f func() (T, bool)) Seq[T] {
+	return Lazy(func() Seq[T] {
+		var value, ok = f()
+		if !ok {
+			return Seq[T]{}
+		}
+		return Cons(value, Generate(f))
+	})
+}
+
+// Map returns a sequence of the results of calling f on each item of s. The
+// function f is called once for each item as it is first needed.
+func Map[T, U any](s Seq[T], f func(T) U) Seq[U] {
+	return Lazy(func() Seq[U] {
+		if s.IsEmpty() {
+			return Seq[U]{}
+		}
+		return Cons(f(s.First()), Map(s.Rest(), f))
+	})
+}
+
+// Filter returns a sequence of the items of s for which pred returns true.
+// Items are tested as they are first needed, so filtering an infinite
+// sequence without any further matches never returns.
+func Filter[T any](s Seq[T], pred func(T) bool) Seq[T] {
+	return Lazy(func() Seq[T] {
+		for ; !s.IsEmpty(); s = s.Rest() {
+			if pred(s.First()) {
+				return Cons(s.First(), Filter(s.Rest(), pred))
+			}
+		}
+		return Seq[T]{}
+	})
+}
+
+// Take returns a sequence of the first n items of s, or all of the items of s
+// if it has fewer than n. Only the items taken are ever computed, so Take can
+// be used to realize a finite prefix of an infinite sequence.
+func Take[T any](s Seq[T], n int) Seq[T] {
+	return Lazy(func() Seq[T] {
+		if n <= 0 || s.IsEmpty() {
+			return Seq[T]{}
+		}
+		return Cons(s.First(), Take(s.Rest(), n-1))
+	})
+}
+
+// IsEmpty returns true if the sequence has no items, false otherwise. The
+// first item of s is computed if it hasn't been already.
+func (s Seq[T]) IsEmpty() bool {
+	return s.cell == nil || s.cell.realize().empty
+}
+
+// First returns the first item of the sequence, or the zero value if the
+// sequence is empty.
+func (s Seq[T]) First() T {
+	if s.cell == nil {
+		var zero T
+		return zero
+	}
+	return s.cell.realize().first
+}
+
+// Rest returns a sequence of all but the first item of s, or an empty sequence
+// if s is empty. None of the items of the rest of the sequence are computed
+// until they are needed.
+func (s Seq[T]) Rest() Seq[T] {
+	if s.cell == nil {
+		return s
+	}
+	return s.cell.realize().rest
+}
+
+// All returns an iterator over each item of s, computing items as they are
+// reached.
+func (s Seq[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for walk := s; !walk.IsEmpty(); walk = walk.Rest() {
+			if !yield(walk.First()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package lazy_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lazy"
+)
+
+func counter(n int, calls *int) func() (int, bool) {
+	var next = 0
+	return func() (int, bool) {
+		*calls += 1
+		if next == n {
+			return 0, false
+		}
+		next += 1
+		return next, true
+	}
+}
+
+func TestSeqEmpty(t *testing.T) {
+	type testCase struct {
+		title string
+		seq   lazy.Seq[int]
+	}
+
+	testCases := []testCase{
+		{"Zero", lazy.Seq[int]{}},
+		{"Of", lazy.Of[int]()},
+		{"Lazy", lazy.Lazy(func() lazy.Seq[int] { return lazy.Seq[int]{} })},
+		{"Generate", lazy.Generate(func() (int, bool) { return 0, false })},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			if !tc.seq.IsEmpty() {
+				t.Fatalf("want empty sequence, got first item %d", tc.seq.First())
+			}
+			if got := tc.seq.First(); got != 0 {
+				t.Fatalf("got first item %d, want 0", got)
+			}
+			if !tc.seq.Rest().IsEmpty() {
+				t.Fatalf("want empty rest of sequence")
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestSeqOf(t *testing.T) {
+	var got = slices.Collect(lazy.Of(1, 2, 3).All())
+	if got, want := fmt.Sprint(got), "[1 2 3]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestGenerateMemoized(t *testing.T) {
+	var calls = 0
+	var seq = lazy.Generate(counter(5, &calls))
+	if calls != 0 {
+		t.Fatalf("got %d calls before the sequence was used, want 0", calls)
+	}
+
+	if got, want := seq.Rest().First(), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls after realizing two items, want 2", calls)
+	}
+
+	for i := 0; i < 2; i++ {
+		var got = slices.Collect(seq.All())
+		if got, want := fmt.Sprint(got), "[1 2 3 4 5]"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+	if calls != 6 {
+		t.Fatalf("got %d calls after walking the sequence twice, want 6", calls)
+	}
+}
+
+func TestGenerateConcurrent(t *testing.T) {
+	var calls = 0
+	var mu sync.Mutex
+	var generate = counter(1000, &calls)
+	var seq = lazy.Generate(func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		return generate()
+	})
+
+	var wg sync.WaitGroup
+	var results = make([][]int, 4)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = slices.Collect(seq.All())
+		}()
+	}
+	wg.Wait()
+
+	for _, got := range results {
+		if len(got) != 1000 || got[0] != 1 || got[999] != 1000 {
+			t.Fatalf("got %d items from %d to %d, want 1000 items from 1 to 1000", len(got), got[0], got[len(got)-1])
+		}
+	}
+	if calls != 1001 {
+		t.Fatalf("got %d calls, want 1001", calls)
+	}
+}
+
+func TestMap(t *testing.T) {
+	var calls = 0
+	var seq = lazy.Map(lazy.Of(1, 2, 3), func(x int) string {
+		calls += 1
+		return fmt.Sprint(x * 2)
+	})
+	if calls != 0 {
+		t.Fatalf("got %d calls before the sequence was used, want 0", calls)
+	}
+
+	var got = slices.Collect(seq.All())
+	if got, want := fmt.Sprint(got), "[2 4 6]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	for range seq.All() {
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	var calls = 0
+	var seq = lazy.Filter(lazy.Generate(counter(10, &calls)), func(x int) bool {
+		return x%3 == 0
+	})
+
+	if got, want := seq.First(), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls after realizing the first item, want 3", calls)
+	}
+
+	var got = slices.Collect(seq.All())
+	if got, want := fmt.Sprint(got), "[3 6 9]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestSeqAllBreak(t *testing.T) {
+	var calls = 0
+	for x := range lazy.Generate(counter(100, &calls)).All() {
+		if x == 3 {
+			break
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+}
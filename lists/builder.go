@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lists
+
+// Builder is used to efficiently build a list by appending items to its end,
+// similarly to strings.Builder. Items are linked together front to back as
+// they are appended, so the list is built in order without collecting the
+// items first or reversing them afterwards. The zero value of Builder is an
+// empty builder ready to use.
+type Builder[T any] struct {
+	head   *List[T] // First cell of the list being built
+	last   *List[T] // Last cell of the list being built
+	end    *List[T] // Empty list at the end of the list being built
+	count  int      // Number of items appended so far
+	shared bool     // Set once the cells have been returned by List
+}
+
+// Append appends item to the end of the list being built.
+func (b *Builder[T]) Append(item T) {
+	if b.shared {
+		b.unshare()
+	}
+	if b.end == nil {
+		b.end = &List[T]{}
+	}
+
+	var cell = &List[T]{first: item, rest: b.end}
+	if b.last == nil {
+		b.head = cell
+	} else {
+		b.last.rest = cell
+	}
+	b.last = cell
+	b.count += 1
+}
+
+// AppendSlice appends each of items to the end of the list being built in
+// order.
+func (b *Builder[T]) AppendSlice(items []T) {
+	for _, item := range items {
+		b.Append(item)
+	}
+}
+
+// Len returns the number of items appended to the builder so far.
+func (b *Builder[T]) Len() int {
+	return b.count
+}
+
+// List returns a persistent list containing every item appended to the
+// builder so far. The builder may continue to be appended to afterwards
+// without affecting the returned list, though the first Append afterwards
+// copies the items appended so far.
+func (b *Builder[T]) List() List[T] {
+	if b.head == nil {
+		return List[T]{}
+	}
+
+	if !b.shared {
+		// The length of the rest of the list is only known now that every
+		// item has been appended, so set it in each cell.
+		var count = b.count
+		for walk := b.head; walk != b.end; walk = walk.rest {
+			walk.count = count
+			count -= 1
+		}
+		b.shared = true
+	}
+
+	return *b.head
+}
+
+// unshare replaces the cells of the list being built with copies, so that
+// appending to it doesn't change any list returned by List.
+func (b *Builder[T]) unshare() {
+	var walk = b.head
+	*b = Builder[T]{}
+	for ; walk.count > 0; walk = walk.rest {
+		b.Append(walk.first)
+	}
+}
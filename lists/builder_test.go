@@ -0,0 +1,41 @@
+package lists_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+)
+
+func TestBuilder(t *testing.T) {
+	var b lists.Builder[int]
+	if got := b.List(); got.Len() != 0 {
+		t.Fatalf("got %v from an empty builder, want ()", got)
+	}
+
+	b.Append(1)
+	b.AppendSlice([]int{2, 3})
+	if got, want := b.Len(), 3; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+
+	var first = b.List()
+	if got := b.List(); !lists.Equal(got, first) {
+		t.Fatalf("got %v from calling List twice, want %v", got, first)
+	}
+	b.AppendSlice([]int{4, 5})
+	var second = b.List()
+
+	if want := lists.New(1, 2, 3); !lists.Equal(first, want) || first.Len() != want.Len() {
+		t.Fatalf("got first %v, want %v", first, want)
+	}
+	if want := lists.New(1, 2, 3, 4, 5); !lists.Equal(second, want) || second.Len() != want.Len() {
+		t.Fatalf("got second %v, want %v", second, want)
+	}
+
+	// Every cell of the built list must know the length of the rest of it.
+	for walk, n := second, 5; n > 0; walk, n = walk.Rest(), n-1 {
+		if got := walk.Len(); got != n {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, n)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lists
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// GobEncode implements the gob.GobEncoder interface. The list is encoded as a
+// gob encoded slice of its items, from the head of the list to the end.
+func (l List[T]) GobEncode() ([]byte, error) {
+	var items = make([]T, 0, l.count)
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		items = append(items, walk.first)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(items); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing the contents of
+// l with the list encoded in data.
+func (l *List[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&items); err != nil {
+		return err
+	}
+	*l = New(items...)
+	return nil
+}
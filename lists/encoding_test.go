@@ -0,0 +1,66 @@
+package lists_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+)
+
+func TestListGob(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int]()},
+		{"SingleElement", lists.New(42)},
+		{"MultipleElements", lists.New(1, 2, 3)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(tc.list); err != nil {
+				t.Fatalf("got encode error %v", err)
+			}
+
+			var got lists.List[int]
+			if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+				t.Fatalf("got decode error %v", err)
+			}
+
+			if !lists.Equal(got, tc.list) {
+				t.Fatalf("got %v, want %v", got, tc.list)
+			}
+			if got, want := got.Len(), tc.list.Len(); got != want {
+				t.Fatalf("got length %d, want %d", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListGobStruct(t *testing.T) {
+	type record struct {
+		Name  string
+		Items lists.List[string]
+	}
+
+	var buf bytes.Buffer
+	var want = record{"fruit", lists.New("apple", "banana")}
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatalf("got encode error %v", err)
+	}
+
+	var got record
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("got decode error %v", err)
+	}
+	if got.Name != want.Name || !lists.Equal(got.Items, want.Items) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lists
+
+// Partition returns a list of lists of n items each, taken from l in order. As
+// in Clojure, a final partition with fewer than n items is left out. Partition
+// panics if n isn't positive.
+func Partition[T any](l List[T], n int) List[List[T]] {
+	if n <= 0 {
+		panic("lists: non-positive Partition size")
+	}
+
+	var partitions Builder[List[T]]
+	var partition Builder[T]
+	for walk := &l; walk.count >= n; {
+		for i := 0; i < n; i++ {
+			partition.Append(walk.first)
+			walk = walk.rest
+		}
+		partitions.Append(partition.List())
+		partition = Builder[T]{}
+	}
+	return partitions.List()
+}
+
+// PartitionBy returns a list of lists of the items of l in order, starting a
+// new list each time f returns a different key for an item than it did for
+// the item before it.
+func PartitionBy[T any, K comparable](l List[T], f func(T) K) List[List[T]] {
+	var partitions Builder[List[T]]
+	var partition Builder[T]
+	var last K
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		var key = f(walk.first)
+		if partition.Len() > 0 && key != last {
+			partitions.Append(partition.List())
+			partition = Builder[T]{}
+		}
+		partition.Append(walk.first)
+		last = key
+	}
+	if partition.Len() > 0 {
+		partitions.Append(partition.List())
+	}
+	return partitions.List()
+}
@@ -0,0 +1,76 @@
+package lists_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+)
+
+func TestPartition(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		n     int
+		want  string
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), 2, "()"},
+		{"Exact", lists.New(1, 2, 3, 4), 2, "((1 2) (3 4))"},
+		{"Incomplete", lists.New(1, 2, 3, 4, 5), 2, "((1 2) (3 4))"},
+		{"TooShort", lists.New(1, 2), 3, "()"},
+		{"Single", lists.New(1, 2, 3), 1, "((1) (2) (3))"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got = lists.Partition(tc.list, tc.n)
+			if got, want := fmt.Sprint(got), tc.want; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			for partition := range got.All() {
+				if got, want := partition.Len(), tc.n; got != want {
+					t.Fatalf("got partition length %d, want %d", got, want)
+				}
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestPartitionNonPositive(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	lists.Partition(lists.New(1, 2, 3), 0)
+}
+
+func TestPartitionBy(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  string
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), "()"},
+		{"SingleElement", lists.New(1), "((1))"},
+		{"Runs", lists.New(1, 3, 2, 4, 6, 5), "((1 3) (2 4 6) (5))"},
+		{"SameKey", lists.New(2, 4, 6), "((2 4 6))"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got = lists.PartitionBy(tc.list, func(x int) bool { return x%2 == 0 })
+			if got, want := fmt.Sprint(got), tc.want; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
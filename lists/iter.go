@@ -0,0 +1,29 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lists
+
+import "iter"
+
+// All returns an iterator over each item of l, from the head of the list to
+// the end.
+func (l List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for walk := &l; walk.count > 0; walk = walk.rest {
+			if !yield(walk.first) {
+				return
+			}
+		}
+	}
+}
+
+// Collect returns a new list containing the items of seq in the order they
+// are yielded.
+func Collect[T any](seq iter.Seq[T]) List[T] {
+	var b Builder[T]
+	for item := range seq {
+		b.Append(item)
+	}
+	return b.List()
+}
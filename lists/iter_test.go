@@ -0,0 +1,77 @@
+package lists_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+)
+
+func TestListAll(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  []int
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), nil},
+		{"SingleElement", lists.New(42), []int{42}},
+		{"MultipleElements", lists.New(1, 2, 3), []int{1, 2, 3}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got []int
+			for item := range tc.list.All() {
+				got = append(got, item)
+			}
+			if got, want := fmt.Sprint(got), fmt.Sprint(tc.want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListAllBreak(t *testing.T) {
+	var count = 0
+	for item := range lists.New(1, 2, 3, 4, 5).All() {
+		if item == 3 {
+			break
+		}
+		count += 1
+	}
+	if count != 2 {
+		t.Fatalf("got %d items before break, want 2", count)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	type testCase struct {
+		title string
+		items []int
+	}
+
+	testCases := []testCase{
+		{"Empty", nil},
+		{"SingleElement", []int{42}},
+		{"MultipleElements", []int{1, 2, 3}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got = lists.Collect(slices.Values(tc.items))
+			if want := lists.New(tc.items...); !lists.Equal(got, want) || got.Len() != want.Len() {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if got := lists.Collect(got.All()); !lists.Equal(got, lists.New(tc.items...)) {
+				t.Fatalf("got %v after collecting All, want %v", got, tc.items)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
@@ -10,6 +10,9 @@ package lists
 
 import (
 	"fmt"
+	"io"
+	"reflect"
+	"strings"
 )
 
 // List is a persistent data structure that can be treated as a value
@@ -48,12 +51,63 @@ func (l List[T]) First() T {
 	return l.first
 }
 
+// Last returns the value contained within the end of the list, and true if the
+// list isn't empty. If the list is empty, the zero value and false are
+// returned.
+func (l List[T]) Last() (T, bool) {
+	if l.count == 0 {
+		var zero T
+		return zero, false
+	}
+
+	var walk = &l
+	for walk.count > 1 {
+		walk = walk.rest
+	}
+	return walk.first, true
+}
+
 // Rest returns a list of items containing all but the first item of the
-// original list.
+// original list. The rest of an empty list is an empty list.
 func (l List[T]) Rest() List[T] {
+	if l.count == 0 {
+		return l
+	}
 	return *l.rest
 }
 
+// RestOK returns a list of items containing all but the first item of the
+// original list, and true if the list isn't empty. If the list is empty, the
+// empty list and false are returned.
+func (l List[T]) RestOK() (List[T], bool) {
+	if l.count == 0 {
+		return l, false
+	}
+	return *l.rest, true
+}
+
+// Peek returns the value contained within the head of the list, and true if
+// the list isn't empty. If the list is empty, the zero value and false are
+// returned.
+func (l List[T]) Peek() (T, bool) {
+	if l.count == 0 {
+		var zero T
+		return zero, false
+	}
+	return l.first, true
+}
+
+// Pop returns a list of all but the first item of the original list along
+// with that first item, and true if the list isn't empty. If the list is empty,
+// the empty list, the zero value and false are returned.
+func (l List[T]) Pop() (List[T], T, bool) {
+	if l.count == 0 {
+		var zero T
+		return l, zero, false
+	}
+	return *l.rest, l.first, true
+}
+
 // Conj returns a new list where val is the new head, and the original list is
 // the rest.
 func (l List[T]) Conj(val T) List[T] {
@@ -64,6 +118,51 @@ func (l List[T]) Conj(val T) List[T] {
 	}
 }
 
+// Insert returns a new list with v inserted before the item at index i, or at
+// the end of the list if i is equal to l.Len(). Only the first i items are
+// copied, while the rest of l is shared with the new list.
+func (l List[T]) Insert(i int, v T) List[T] {
+	if i < 0 || i > l.count {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, l.count))
+	}
+
+	var items = make([]T, 0, i)
+	var walk = l
+	for len(items) < i {
+		items = append(items, walk.first)
+		walk = *walk.rest
+	}
+
+	return prepend(items, walk.Conj(v))
+}
+
+// Remove returns a new list without the item at index i. Only the first i
+// items are copied, while the rest of l is shared with the new list.
+func (l List[T]) Remove(i int) List[T] {
+	if i < 0 || i >= l.count {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, l.count))
+	}
+
+	var items = make([]T, 0, i)
+	var walk = l
+	for len(items) < i {
+		items = append(items, walk.first)
+		walk = *walk.rest
+	}
+
+	return prepend(items, *walk.rest)
+}
+
+// Reverse returns a new list containing the items of l in the opposite order.
+func (l List[T]) Reverse() List[T] {
+	var reversed List[T]
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		reversed = reversed.Conj(walk.first)
+	}
+
+	return reversed
+}
+
 // String returns a representation of a list similar to standard Go types
 // when using the "%v" formatting verb as in the standard fmt package:
 //     With no items: ()
@@ -74,14 +173,57 @@ func (l List[T]) String() string {
 		return "()"
 	}
 
-	s := "("
-	s += fmt.Sprintf("%v", l.first)
+	var sb strings.Builder
+	sb.WriteByte('(')
+	fmt.Fprint(&sb, l.first)
 	for walk := l.rest; walk.count > 0; walk = walk.rest {
-		s += fmt.Sprintf(" %v", walk.first)
+		sb.WriteByte(' ')
+		fmt.Fprint(&sb, walk.first)
 	}
-	s += ")"
+	sb.WriteByte(')')
+
+	return sb.String()
+}
 
-	return s
+// Format implements the fmt.Formatter interface, formatting a list in the same
+// form as String with the verb and flags applied to each item. The "%#v" verb
+// formats the list as a call to New. A precision given to the "%v" verb limits
+// the number of items formatted, with any further items elided:
+//
+//	%v:   (1 2 3)
+//	%#v:  lists.New[int](1, 2, 3)
+//	%.2v: (1 2 ...)
+func (l List[T]) Format(f fmt.State, verb rune) {
+	var format = fmt.FormatString(f, verb)
+
+	var limit, elide = f.Precision()
+	if verb == 'v' && elide {
+		// The precision limits items rather than being applied to them.
+		format = format[:strings.LastIndexByte(format, '.')] + "v"
+	} else {
+		elide = false
+	}
+
+	var open, sep, close = "(", " ", ")"
+	if verb == 'v' && f.Flag('#') {
+		open = fmt.Sprintf("lists.New[%v](", reflect.TypeFor[T]())
+		sep = ", "
+	}
+
+	io.WriteString(f, open)
+	var i = 0
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		if i > 0 {
+			io.WriteString(f, sep)
+		}
+		if elide && i == limit {
+			io.WriteString(f, "...")
+			break
+		}
+		fmt.Fprintf(f, format, walk.first)
+		i += 1
+	}
+	io.WriteString(f, close)
 }
 
 // IsEmpty returns true if the list is empty, false otherwise
@@ -103,3 +245,66 @@ func Equal[T comparable](a, b List[T]) bool {
 	}
 	return true
 }
+
+// Contains returns true if v is an item of l, false otherwise.
+func Contains[T comparable](l List[T], v T) bool {
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		if walk.first == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the first item of l for which pred returns true, and true if
+// such an item exists. If no item matches, the zero value and false are
+// returned.
+func Find[T any](l List[T], pred func(T) bool) (T, bool) {
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		if pred(walk.first) {
+			return walk.first, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// RemoveFirst returns a new list without the first item of l for which pred
+// returns true, or l itself if no item matches. Only the items before the
+// removed item are copied, while the rest of l is shared with the new list.
+func RemoveFirst[T any](l List[T], pred func(T) bool) List[T] {
+	var items []T
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		if pred(walk.first) {
+			return prepend(items, *walk.rest)
+		}
+		items = append(items, walk.first)
+	}
+	return l
+}
+
+// Append returns a new list containing the items of a followed by the items of
+// b. Only the items of a are copied, while b is shared as the end of the new
+// list.
+func Append[T any](a, b List[T]) List[T] {
+	if b.count == 0 {
+		return a
+	}
+
+	var items = make([]T, 0, a.count)
+	for walk := &a; walk.count > 0; walk = walk.rest {
+		items = append(items, walk.first)
+	}
+
+	return prepend(items, b)
+}
+
+// prepend returns a new list containing items followed by the items of l,
+// which is shared as the end of the new list.
+func prepend[T any](items []T, l List[T]) List[T] {
+	for i := len(items) - 1; i >= 0; i-- {
+		l = l.Conj(items[i])
+	}
+	return l
+}
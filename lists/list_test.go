@@ -1,6 +1,7 @@
 package lists_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/toddgaunt/persistent/lists"
@@ -49,6 +50,36 @@ func TestListLen(t *testing.T) {
 	}
 }
 
+func TestListRest(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  lists.List[int]
+		ok    bool
+	}
+
+	testCases := []testCase{
+		{"Zero", lists.List[int]{}, lists.New[int](), false},
+		{"Empty", lists.New[int](), lists.New[int](), false},
+		{"SingleElement", lists.New(42), lists.New[int](), true},
+		{"MultipleElements", lists.New(1, 2, 3), lists.New(2, 3), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			if got, want := tc.list.Rest(), tc.want; !lists.Equal(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			var got, ok = tc.list.RestOK()
+			if !lists.Equal(got, tc.want) || ok != tc.ok {
+				t.Fatalf("got (%v, %v), want (%v, %v)", got, ok, tc.want, tc.ok)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
 func TestListConj(t *testing.T) {
 	type testCase struct {
 		title string
@@ -76,6 +107,211 @@ func TestListConj(t *testing.T) {
 	}
 }
 
+func TestListPeek(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  int
+		ok    bool
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), 0, false},
+		{"SingleElement", lists.New(42), 42, true},
+		{"MultipleElements", lists.New(1, 2, 3), 1, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got, ok = tc.list.Peek()
+			if got != tc.want || ok != tc.ok {
+				t.Fatalf("got (%d, %v), want (%d, %v)", got, ok, tc.want, tc.ok)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListLast(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  int
+		ok    bool
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), 0, false},
+		{"SingleElement", lists.New(42), 42, true},
+		{"MultipleElements", lists.New(1, 2, 3), 3, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got, ok = tc.list.Last()
+			if got != tc.want || ok != tc.ok {
+				t.Fatalf("got (%d, %v), want (%d, %v)", got, ok, tc.want, tc.ok)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListPop(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		rest  lists.List[int]
+		want  int
+		ok    bool
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), lists.New[int](), 0, false},
+		{"SingleElement", lists.New(42), lists.New[int](), 42, true},
+		{"MultipleElements", lists.New(1, 2, 3), lists.New(2, 3), 1, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var rest, got, ok = tc.list.Pop()
+			if got != tc.want || ok != tc.ok {
+				t.Fatalf("got (%d, %v), want (%d, %v)", got, ok, tc.want, tc.ok)
+			}
+			if !lists.Equal(rest, tc.rest) {
+				t.Fatalf("got rest %v, want %v", rest, tc.rest)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListInsert(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		index int
+		want  lists.List[int]
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), 0, lists.New(42)},
+		{"Head", lists.New(1, 2, 3), 0, lists.New(42, 1, 2, 3)},
+		{"Middle", lists.New(1, 2, 3), 2, lists.New(1, 2, 42, 3)},
+		{"End", lists.New(1, 2, 3), 3, lists.New(1, 2, 3, 42)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var original = tc.list.String()
+			var got = tc.list.Insert(tc.index, 42)
+			if !lists.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			if got, want := got.Len(), tc.want.Len(); got != want {
+				t.Fatalf("got length %d, want %d", got, want)
+			}
+			if got, want := tc.list.String(), original; got != want {
+				t.Fatalf("got original %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListInsertOutOfRange(t *testing.T) {
+	for _, i := range []int{-1, 4} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			lists.New(1, 2, 3).Insert(i, 42)
+		}()
+	}
+}
+
+func TestListRemove(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		index int
+		want  lists.List[int]
+	}
+
+	testCases := []testCase{
+		{"SingleElement", lists.New(42), 0, lists.New[int]()},
+		{"Head", lists.New(1, 2, 3), 0, lists.New(2, 3)},
+		{"Middle", lists.New(1, 2, 3), 1, lists.New(1, 3)},
+		{"End", lists.New(1, 2, 3), 2, lists.New(1, 2)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var original = tc.list.String()
+			var got = tc.list.Remove(tc.index)
+			if !lists.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			if got, want := got.Len(), tc.want.Len(); got != want {
+				t.Fatalf("got length %d, want %d", got, want)
+			}
+			if got, want := tc.list.String(), original; got != want {
+				t.Fatalf("got original %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListRemoveOutOfRange(t *testing.T) {
+	for _, i := range []int{-1, 3} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			lists.New(1, 2, 3).Remove(i)
+		}()
+	}
+}
+
+func TestListReverse(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  lists.List[int]
+	}
+
+	var list = lists.New(1, 2, 3)
+	testCases := []testCase{
+		{"Empty", lists.New[int](), lists.New[int]()},
+		{"SingleElement", lists.New(42), lists.New(42)},
+		{"MultipleElements", list, lists.New(3, 2, 1)},
+		{"Twice", list.Reverse(), list},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			if got, want := tc.list.Reverse(), tc.want; !lists.Equal(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if got, want := tc.list.Reverse().Len(), tc.list.Len(); got != want {
+				t.Fatalf("got length %d, want %d", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
 func TestEqual(t *testing.T) {
 	type testCase struct {
 		title string
@@ -102,3 +338,167 @@ func TestEqual(t *testing.T) {
 		t.Run(tc.title, f)
 	}
 }
+
+func TestListString(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  string
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), "()"},
+		{"SingleElement", lists.New(42), "(42)"},
+		{"MultipleElements", lists.New(1, 2, 3), "(1 2 3)"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			if got, want := tc.list.String(), tc.want; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestContains(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		value int
+		want  bool
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), 0, false},
+		{"First", lists.New(1, 2, 3), 1, true},
+		{"Last", lists.New(1, 2, 3), 3, true},
+		{"Missing", lists.New(1, 2, 3), 4, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			if got, want := lists.Contains(tc.list, tc.value), tc.want; got != want {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestFind(t *testing.T) {
+	var calls = 0
+	var even = func(x int) bool {
+		calls += 1
+		return x%2 == 0
+	}
+
+	if got, ok := lists.Find(lists.New(1, 3, 4, 5, 6), even); got != 4 || !ok {
+		t.Fatalf("got (%d, %v), want (4, true)", got, ok)
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls, want 3", calls)
+	}
+	if got, ok := lists.Find(lists.New(1, 3, 5), even); got != 0 || ok {
+		t.Fatalf("got (%d, %v), want (0, false)", got, ok)
+	}
+}
+
+func TestRemoveFirst(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  lists.List[int]
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), lists.New[int]()},
+		{"NoMatch", lists.New(1, 3, 5), lists.New(1, 3, 5)},
+		{"Head", lists.New(2, 3, 4), lists.New(3, 4)},
+		{"OnlyFirstMatch", lists.New(1, 2, 3, 4), lists.New(1, 3, 4)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got = lists.RemoveFirst(tc.list, func(x int) bool { return x%2 == 0 })
+			if !lists.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			if got, want := got.Len(), tc.want.Len(); got != want {
+				t.Fatalf("got length %d, want %d", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	type testCase struct {
+		title string
+		a     lists.List[int]
+		b     lists.List[int]
+		want  lists.List[int]
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), lists.New[int](), lists.New[int]()},
+		{"EmptyFirst", lists.New[int](), lists.New(1, 2), lists.New(1, 2)},
+		{"EmptySecond", lists.New(1, 2), lists.New[int](), lists.New(1, 2)},
+		{"MultipleElements", lists.New(1, 2, 3), lists.New(4, 5), lists.New(1, 2, 3, 4, 5)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var got = lists.Append(tc.a, tc.b)
+			if !lists.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			if got, want := got.Len(), tc.want.Len(); got != want {
+				t.Fatalf("got length %d, want %d", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestListFormat(t *testing.T) {
+	type point struct{ X, Y int }
+
+	type testCase struct {
+		title  string
+		format string
+		list   any
+		want   string
+	}
+
+	testCases := []testCase{
+		{"Empty", "%v", lists.New[int](), "()"},
+		{"Value", "%v", lists.New(1, 2, 3), "(1 2 3)"},
+		{"Verb", "%x", lists.New(10, 11), "(a b)"},
+		{"Width", "%03d", lists.New(1, 2), "(001 002)"},
+		{"PlusValue", "%+v", lists.New(point{1, 2}), "({X:1 Y:2})"},
+		{"GoSyntax", "%#v", lists.New("a", "b"), `lists.New[string]("a", "b")`},
+		{"GoSyntaxEmpty", "%#v", lists.New[int](), "lists.New[int]()"},
+		{"Elided", "%.2v", lists.New(1, 2, 3), "(1 2 ...)"},
+		{"ElidedZero", "%.0v", lists.New(1, 2, 3), "(...)"},
+		{"ElidedShort", "%.5v", lists.New(1, 2, 3), "(1 2 3)"},
+		{"ElidedExact", "%.3v", lists.New(1, 2, 3), "(1 2 3)"},
+		{"ElidedGoSyntax", "%#.1v", lists.New(1, 2, 3), "lists.New[int](1, ...)"},
+		{"Precision", "%.1f", lists.New(1.25, 2.5), "(1.2 2.5)"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			if got, want := fmt.Sprintf(tc.format, tc.list), tc.want; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
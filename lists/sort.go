@@ -0,0 +1,28 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lists
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Sort returns a new list containing the items of l in ascending order.
+func Sort[T cmp.Ordered](l List[T]) List[T] {
+	return SortFunc(l, cmp.Compare[T])
+}
+
+// SortFunc returns a new list containing the items of l in ascending order as
+// determined by the cmp function, which follows the same rules as for
+// slices.SortFunc. The sort is stable, so equal items keep their order from l.
+func SortFunc[T any](l List[T], cmp func(a, b T) int) List[T] {
+	var items = make([]T, 0, l.count)
+	for walk := &l; walk.count > 0; walk = walk.rest {
+		items = append(items, walk.first)
+	}
+	slices.SortStableFunc(items, cmp)
+
+	return New(items...)
+}
@@ -0,0 +1,47 @@
+package lists_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+)
+
+func TestSort(t *testing.T) {
+	type testCase struct {
+		title string
+		list  lists.List[int]
+		want  lists.List[int]
+	}
+
+	testCases := []testCase{
+		{"Empty", lists.New[int](), lists.New[int]()},
+		{"SingleElement", lists.New(42), lists.New(42)},
+		{"Sorted", lists.New(1, 2, 3), lists.New(1, 2, 3)},
+		{"Unsorted", lists.New(3, 1, 2, 1), lists.New(1, 1, 2, 3)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		f := func(t *testing.T) {
+			var original = tc.list.String()
+			if got, want := lists.Sort(tc.list), tc.want; !lists.Equal(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if got, want := tc.list.String(), original; got != want {
+				t.Fatalf("got original %s, want %s", got, want)
+			}
+		}
+		t.Run(tc.title, f)
+	}
+}
+
+func TestSortFuncStable(t *testing.T) {
+	var list = lists.New("b1", "a1", "b2", "a2")
+	var got = lists.SortFunc(list, func(a, b string) int {
+		return strings.Compare(a[:1], b[:1])
+	})
+	if want := lists.New("a1", "a2", "b1", "b2"); !lists.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
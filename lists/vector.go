@@ -0,0 +1,20 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package lists
+
+import "github.com/toddgaunt/persistent/vectors"
+
+// FromVector creates a new persistent list containing the values of v in
+// order, with the first value of v at the head of the list. The values are
+// appended a leaf of the vector at a time.
+func FromVector[T any](v vectors.Vector[T]) List[T] {
+	var b Builder[T]
+	for i := 0; i < v.Len(); {
+		var values, offset = v.LeafAt(i)
+		b.AppendSlice(values[offset:])
+		i += len(values) - offset
+	}
+	return b.List()
+}
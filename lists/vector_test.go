@@ -0,0 +1,26 @@
+package lists_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestFromVector(t *testing.T) {
+	var values = make([]int, 32*32+40)
+	for i := range values {
+		values[i] = i
+	}
+
+	for _, n := range []int{0, 1, 32, 33, len(values)} {
+		var got = lists.FromVector(vectors.New(values[:n]...))
+		if want := lists.New(values[:n]...); !lists.Equal(got, want) || got.Len() != want.Len() {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := fmt.Sprint(vectors.FromList(got)), fmt.Sprint(values[:n]); got != want {
+			t.Fatalf("got %s after converting back, want %s", got, want)
+		}
+	}
+}
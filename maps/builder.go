@@ -0,0 +1,49 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+// Builder is used to efficiently build a map by associating keys to values,
+// similarly to strings.Builder. It uses a transient map internally, but unlike
+// a transient map it may be used freely after any of its methods are called,
+// including after a persistent map is made from it with Map. The zero value of
+// Builder is an empty builder ready to use.
+type Builder[K comparable, V any] struct {
+	t *transient[K, V]
+}
+
+// NewBuilder creates a new empty builder for a map expected to hold about
+// sizeHint entries. Nodes of the map are created with room for the number of
+// entries they are expected to eventually hold, rather than grown one entry
+// at a time.
+func NewBuilder[K comparable, V any](sizeHint int) *Builder[K, V] {
+	return &Builder[K, V]{t: New[K, V]().transient(sizeHint)}
+}
+
+// Assoc associates key to value in the map being built, replacing any value
+// key was already associated to.
+func (b *Builder[K, V]) Assoc(key K, value V) {
+	if b.t == nil {
+		b.t = Map[K, V]{}.transient(0)
+	}
+	b.t.assoc(key, value)
+}
+
+// Len returns the number of entries in the map being built.
+func (b *Builder[K, V]) Len() int {
+	if b.t == nil {
+		return 0
+	}
+	return b.t.count
+}
+
+// Map returns a persistent map containing every entry associated in the
+// builder so far. The builder may continue to be used afterwards without
+// affecting the returned map.
+func (b *Builder[K, V]) Map() Map[K, V] {
+	if b.t == nil {
+		return Map[K, V]{}
+	}
+	return b.t.persistent()
+}
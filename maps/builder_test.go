@@ -0,0 +1,69 @@
+package maps_test
+
+import (
+	"fmt"
+	stdmaps "maps"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestBuilder(t *testing.T) {
+	var b maps.Builder[int, int]
+	if got := b.Map(); got.Len() != 0 {
+		t.Fatalf("got %v from an empty builder, want map[]", got)
+	}
+
+	var want = map[int]int{}
+	for i := 0; i < 1000; i++ {
+		b.Assoc(i, i)
+		want[i] = i
+	}
+	if got, want := b.Len(), 1000; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+
+	var first = b.Map()
+	for i := 0; i < 2000; i++ {
+		b.Assoc(i, -i)
+	}
+	var second = b.Map()
+
+	if got := maps.ToGoMap(first); !stdmaps.Equal(got, want) {
+		t.Fatalf("got %d entries in first, want %d unchanged entries", len(got), len(want))
+	}
+	if got, want := second.Len(), 2000; got != want {
+		t.Fatalf("got second Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := second.Get(500), -500; got != want {
+		t.Fatalf("got second Get(500)=%d, want Get(500)=%d", got, want)
+	}
+}
+
+func TestNewBuilder(t *testing.T) {
+	for _, hint := range []int{0, 1, 100, 100000} {
+		var b = maps.NewBuilder[int, int](hint)
+		var want = maps.New[int, int]()
+		for i := 0; i < 5000; i++ {
+			b.Assoc(i, i)
+			want = want.Assoc(i, i)
+		}
+		if got := b.Map(); !maps.Equal(got, want) {
+			t.Fatalf("got a different map with size hint %d than one built with Assoc", hint)
+		}
+	}
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	for _, hint := range []int{0, 1000000} {
+		b.Run(fmt.Sprintf("Hint%d", hint), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var builder = maps.NewBuilder[int, int](hint)
+				for j := 0; j < 1000000; j++ {
+					builder.Assoc(j, j)
+				}
+				_ = builder.Map()
+			}
+		})
+	}
+}
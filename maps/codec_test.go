@@ -0,0 +1,75 @@
+package maps_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/toddgaunt/persistent/codec"
+	"github.com/toddgaunt/persistent/maps"
+)
+
+// date is encoded by its codec as a string with only the day, rather than as
+// the full timestamp time.Time is encoded as.
+type date struct{ time.Time }
+
+func init() {
+	codec.Register(
+		func(d date) (string, error) { return d.Format(time.DateOnly), nil },
+		func(s string) (date, error) {
+			var t, err = time.Parse(time.DateOnly, s)
+			return date{t}, err
+		},
+	)
+}
+
+func newDate(s string) date {
+	var t, _ = time.Parse(time.DateOnly, s)
+	return date{t}
+}
+
+func TestMapCodecJSON(t *testing.T) {
+	var m = maps.New[date, date]().
+		Assoc(newDate("2024-01-02"), newDate("2024-02-03")).
+		Assoc(newDate("1999-12-31"), newDate("2000-01-01"))
+
+	var data, err = json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(data), `{"1999-12-31":"2000-01-01","2024-01-02":"2024-02-03"}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	var got maps.Map[date, date]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if !maps.Equal(got, m) {
+		t.Fatalf("got %v, want %v", got, m)
+	}
+
+	var mixed = maps.New[string, any]().Assoc("when", newDate("2024-01-02")).Assoc("none", nil)
+	if data, err = json.Marshal(mixed); err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(data), `{"none":null,"when":"2024-01-02"}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMapCodecBinary(t *testing.T) {
+	var m = maps.New[string, date]().Assoc("a", newDate("2024-01-02"))
+	var data, err = m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+
+	var got maps.Map[string, date]
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if !maps.Equal(got, m) {
+		t.Fatalf("got %v, want %v", got, m)
+	}
+}
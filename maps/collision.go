@@ -0,0 +1,70 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import "slices"
+
+// A collision node is found below the last level of the trie, once every bit
+// of a hash has been consumed, and holds entries with keys that all have the
+// same hash. Since their hashes can't tell them apart, the entries are kept in
+// the order they were associated in and searched for linearly, comparing keys
+// with the map's hasher. Collision nodes have neither a datamap nor a nodemap.
+//
+// A collision node always holds at least two entries. It is created by merge
+// when two keys with the same hash meet, and once dissoc leaves it with a
+// single entry, that entry is moved up into the parent node as with any other
+// child node left with a single entry.
+
+// newCollision returns a new collision node belonging to o holding the entries
+// a and b, which have different keys with the same hash.
+func newCollision[K any, V any](o *owner, a, b entry[K, V]) *node[K, V] {
+	return &node[K, V]{entries: []entry[K, V]{a, b}, owner: o}
+}
+
+// findCollision returns the entry for key within the collision node n, or nil
+// if there isn't one.
+func (n *node[K, V]) findCollision(h keyHasher[K], key K) *entry[K, V] {
+	for i := range n.entries {
+		if h.equals(n.entries[i].key, key) {
+			return &n.entries[i]
+		}
+	}
+	return nil
+}
+
+// assocCollision is assoc for the collision node n.
+func (n *node[K, V]) assocCollision(h keyHasher[K], o *owner, hash uint64, key K, f func(V, bool) V) (*node[K, V], bool) {
+	for i := range n.entries {
+		if h.equals(n.entries[i].key, key) {
+			var e = entry[K, V]{hash: hash, key: key, value: f(n.entries[i].value, true)}
+			if n.editable(o) {
+				n.entries[i] = e
+				return n, false
+			}
+			return &node[K, V]{entries: replaceAt(n.entries, i, e), owner: o}, false
+		}
+	}
+
+	var e = entry[K, V]{hash: hash, key: key, value: f(*new(V), false)}
+	if n.editable(o) {
+		n.entries = append(n.entries, e)
+		return n, true
+	}
+	return &node[K, V]{entries: insertAt(n.entries, len(n.entries), e), owner: o}, true
+}
+
+// dissocCollision is dissoc for the collision node n.
+func (n *node[K, V]) dissocCollision(h keyHasher[K], o *owner, key K) (*node[K, V], bool) {
+	for i := range n.entries {
+		if h.equals(n.entries[i].key, key) {
+			if n.editable(o) {
+				n.entries = slices.Delete(n.entries, i, i+1)
+				return n, true
+			}
+			return &node[K, V]{entries: removeAt(n.entries, i), owner: o}, true
+		}
+	}
+	return n, false
+}
@@ -0,0 +1,141 @@
+package maps_test
+
+import (
+	stdmaps "maps"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+// colliding returns an empty map using hash, which is used to force keys to
+// have the same hash as each other.
+func colliding(hash func(int) uint64) maps.Map[int, int] {
+	return maps.NewMapWith[int, int](hash, func(a, b int) bool { return a == b })
+}
+
+func TestCollisionModel(t *testing.T) {
+	var testCases = []struct {
+		name string
+		hash func(int) uint64
+	}{
+		// Every key has the same hash, so every entry is in one collision
+		// node at the bottom of the trie.
+		{"Constant", func(int) uint64 { return 42 }},
+		// Keys have one of a few hashes, which differ only in their last
+		// bits, so collision nodes share long paths through the trie.
+		{"Buckets", func(key int) uint64 { return uint64(key%3) << 62 }},
+		// Pairs of keys share a hash, mixed with entries without collisions.
+		{"Pairs", func(key int) uint64 { return uint64(key/2) * 0x9e3779b97f4a7c15 }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var r = rand.New(rand.NewPCG(9, 10))
+			var model = map[int]int{}
+			var m = colliding(tc.hash)
+			var versions []maps.Map[int, int]
+			var models []map[int]int
+
+			for i := 0; i < 3000; i++ {
+				var key = r.IntN(100)
+				if r.IntN(3) == 0 {
+					delete(model, key)
+					m = m.Dissoc(key)
+				} else {
+					model[key] = i
+					m = m.Assoc(key, i)
+				}
+
+				if i%300 == 0 {
+					versions = append(versions, m)
+					models = append(models, stdmaps.Clone(model))
+				}
+			}
+			versions = append(versions, m)
+			models = append(models, model)
+
+			for i, version := range versions {
+				if got, want := version.Len(), len(models[i]); got != want {
+					t.Fatalf("got version %d Len()=%d, want Len()=%d", i, got, want)
+				}
+				for key := 0; key < 100; key++ {
+					var want, ok = models[i][key]
+					if got, found := version.GetOK(key); got != want || found != ok {
+						t.Fatalf("got version %d GetOK(%d)=%d, %t, want %d, %t", i, key, got, found, want, ok)
+					}
+				}
+				if got := stdmaps.Collect(version.All()); !stdmaps.Equal(got, models[i]) {
+					t.Fatalf("got version %d entries %v, want %v", i, got, models[i])
+				}
+			}
+
+			// Removing every key must leave an empty map.
+			for key := range models[len(models)-1] {
+				m = m.Dissoc(key)
+			}
+			if got, want := m.Len(), 0; got != want {
+				t.Fatalf("got Len()=%d after removing every key, want Len()=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestCollisionFuncs(t *testing.T) {
+	var hash = func(key int) uint64 { return uint64(key % 2) }
+
+	var a, b = colliding(hash), colliding(hash)
+	for i := 0; i < 10; i++ {
+		a = a.Assoc(i, i)
+	}
+	for i := 9; i >= 0; i-- {
+		b = b.Assoc(i, i)
+	}
+
+	// Entries of collision nodes are in the order they were associated, which
+	// must not affect comparisons.
+	if !maps.Equal(a, b) {
+		t.Fatalf("got Equal(a, b)=false, want true")
+	}
+	if maps.Equal(a, b.Assoc(4, -4)) {
+		t.Fatalf("got Equal(a, b)=true after changing b, want false")
+	}
+
+	var added, removed, changed = maps.Diff(a, b.Dissoc(2).Assoc(3, -3).Assoc(10, 10))
+	if got, want := added.String(), "map[10:10]"; got != want {
+		t.Fatalf("got added %s, want %s", got, want)
+	}
+	if got, want := removed.String(), "map[2:2]"; got != want {
+		t.Fatalf("got removed %s, want %s", got, want)
+	}
+	if got, want := changed.String(), "map[3:-3]"; got != want {
+		t.Fatalf("got changed %s, want %s", got, want)
+	}
+
+	var even = maps.Filter(a, func(k, v int) bool { return k%2 == 0 })
+	if got, want := even.Len(), 5; got != want {
+		t.Fatalf("got filtered Len()=%d, want Len()=%d", got, want)
+	}
+	if even.Contains(1) || !even.Contains(8) {
+		t.Fatalf("got filtered %v, want only even keys", even)
+	}
+
+	var single = maps.Filter(a, func(k, v int) bool { return k == 3 })
+	if got, want := single.Dissoc(3).Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+
+	// Decoding builds the map in place, including its collision nodes.
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	var decoded = colliding(hash)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if !maps.Equal(a, decoded) {
+		t.Fatalf("got %v after decoding, want %v", decoded, a)
+	}
+}
@@ -0,0 +1,142 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+// Diff returns the changes made to old to result in new: the entries of new
+// with keys not present in old, the entries of old with keys not present in
+// new, and the entries of new with keys present in old but associated to a
+// different value. Subtries shared between old and new, such as those left
+// untouched by the operations that made one from the other, are skipped, so
+// comparing successive versions of a map is proportional to the changes
+// between them.
+func Diff[K comparable, V comparable](old, new Map[K, V]) (added, removed, changed Map[K, V]) {
+	var d = differ[K, V]{
+		added:   Map[K, V]{hasher: new.hasher},
+		removed: Map[K, V]{hasher: new.hasher},
+		changed: Map[K, V]{hasher: new.hasher},
+	}
+
+	switch {
+	case old.hasher != new.hasher:
+		// The maps hash keys differently, so their tries can't be compared
+		// structurally and each key must be looked up instead.
+		for key, x := range old.All() {
+			if y, ok := new.GetOK(key); !ok {
+				d.removed = d.removed.Assoc(key, x)
+			} else if x != y {
+				d.changed = d.changed.Assoc(key, y)
+			}
+		}
+		for key, y := range new.All() {
+			if !old.Contains(key) {
+				d.added = d.added.Assoc(key, y)
+			}
+		}
+	case old.root != nil && new.root != nil:
+		d.nodes(new.hasher, 0, old.root, new.root)
+	default:
+		d.slots(new.hasher, 0, slot[K, V]{child: old.root}, slot[K, V]{child: new.root})
+	}
+
+	return d.added, d.removed, d.changed
+}
+
+// differ collects the changes found while comparing two maps.
+type differ[K comparable, V comparable] struct {
+	added, removed, changed Map[K, V]
+}
+
+// put returns m with the entry e, which must have been hashed the same way m
+// hashes keys.
+func put[K comparable, V any](m Map[K, V], e *entry[K, V]) Map[K, V] {
+	return m.update(e.hash, e.key, func(V, bool) V { return e.value })
+}
+
+// nodes collects the changes between the nodes a and b at the level of the
+// trie starting at shift.
+func (d *differ[K, V]) nodes(h keyHasher[K], shift uint, a, b *node[K, V]) {
+	if a == b {
+		return
+	}
+	if shift >= hashBits {
+		d.slots(h, shift, slot[K, V]{child: a}, slot[K, V]{child: b})
+		return
+	}
+
+	for i := 0; i < nodeWidth; i++ {
+		var bit = uint32(1) << i
+		var x, y = slotOf(a, bit), slotOf(b, bit)
+		if x.child != nil && y.child != nil {
+			d.nodes(h, shift+nodeBits, x.child, y.child)
+		} else {
+			d.slots(h, shift+nodeBits, x, y)
+		}
+	}
+}
+
+// slots collects the changes between the slots a and b, which hold the
+// entries at the same position of two tries with the same hasher, where shift
+// is the level of the trie their child nodes are at.
+func (d *differ[K, V]) slots(h keyHasher[K], shift uint, a, b slot[K, V]) {
+	a.forEach(func(x *entry[K, V]) bool {
+		var y = b.find(h, shift, x.hash, x.key)
+		switch {
+		case y == nil:
+			d.removed = put(d.removed, x)
+		case x.value != y.value:
+			d.changed = put(d.changed, y)
+		}
+		return true
+	})
+	b.forEach(func(y *entry[K, V]) bool {
+		if a.find(h, shift, y.hash, y.key) == nil {
+			d.added = put(d.added, y)
+		}
+		return true
+	})
+}
+
+// slot is the contents of a single position within a node, which is either
+// empty, an entry, or a child node.
+type slot[K comparable, V any] struct {
+	entry *entry[K, V]
+	child *node[K, V]
+}
+
+func slotOf[K comparable, V any](n *node[K, V], bit uint32) slot[K, V] {
+	switch {
+	case n.datamap&bit != 0:
+		return slot[K, V]{entry: &n.entries[index(n.datamap, bit)]}
+	case n.nodemap&bit != 0:
+		return slot[K, V]{child: n.children[index(n.nodemap, bit)]}
+	default:
+		return slot[K, V]{}
+	}
+}
+
+// find returns the entry for key within s, where shift is the level of the
+// trie s's child node is at, or nil if there isn't one.
+func (s slot[K, V]) find(h keyHasher[K], shift uint, hash uint64, key K) *entry[K, V] {
+	switch {
+	case s.entry != nil:
+		if s.entry.hash == hash && h.equals(s.entry.key, key) {
+			return s.entry
+		}
+		return nil
+	case s.child != nil:
+		return s.child.find(h, hash, shift, key)
+	default:
+		return nil
+	}
+}
+
+func (s slot[K, V]) forEach(yield func(*entry[K, V]) bool) {
+	switch {
+	case s.entry != nil:
+		yield(s.entry)
+	case s.child != nil:
+		s.child.forEach(yield)
+	}
+}
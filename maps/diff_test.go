@@ -0,0 +1,105 @@
+package maps_test
+
+import (
+	"hash/maphash"
+	stdmaps "maps"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestDiff(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		old     map[int]int
+		new     map[int]int
+		added   map[int]int
+		removed map[int]int
+		changed map[int]int
+	}{
+		{"Empty", map[int]int{}, map[int]int{}, map[int]int{}, map[int]int{}, map[int]int{}},
+		{"FromEmpty", map[int]int{}, map[int]int{1: 1}, map[int]int{1: 1}, map[int]int{}, map[int]int{}},
+		{"ToEmpty", map[int]int{1: 1}, map[int]int{}, map[int]int{}, map[int]int{1: 1}, map[int]int{}},
+		{"Same", map[int]int{1: 1, 2: 2}, map[int]int{1: 1, 2: 2}, map[int]int{}, map[int]int{}, map[int]int{}},
+		{
+			"Mixed",
+			map[int]int{1: 1, 2: 2, 3: 3},
+			map[int]int{2: 2, 3: 4, 5: 5},
+			map[int]int{5: 5},
+			map[int]int{1: 1},
+			map[int]int{3: 4},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var added, removed, changed = maps.Diff(fromGoMap(tc.old), fromGoMap(tc.new))
+			if got := stdmaps.Collect(added.All()); !stdmaps.Equal(got, tc.added) {
+				t.Fatalf("got added %v, want %v", got, tc.added)
+			}
+			if got := stdmaps.Collect(removed.All()); !stdmaps.Equal(got, tc.removed) {
+				t.Fatalf("got removed %v, want %v", got, tc.removed)
+			}
+			if got := stdmaps.Collect(changed.All()); !stdmaps.Equal(got, tc.changed) {
+				t.Fatalf("got changed %v, want %v", got, tc.changed)
+			}
+		})
+	}
+}
+
+func TestDiffVersions(t *testing.T) {
+	var old = maps.New[int, int]()
+	for i := 0; i < 10000; i++ {
+		old = old.Assoc(i, i)
+	}
+
+	var new = old
+	var added, removed, changed = map[int]int{}, map[int]int{}, map[int]int{}
+	for i := 0; i < 10000; i += 97 {
+		switch i % 3 {
+		case 0:
+			new = new.Dissoc(i)
+			removed[i] = i
+		case 1:
+			new = new.Assoc(i, -i)
+			changed[i] = -i
+		case 2:
+			new = new.Assoc(i+10000, i)
+			added[i+10000] = i
+		}
+	}
+
+	var gotAdded, gotRemoved, gotChanged = maps.Diff(old, new)
+	if got := stdmaps.Collect(gotAdded.All()); !stdmaps.Equal(got, added) {
+		t.Fatalf("got added %v, want %v", got, added)
+	}
+	if got := stdmaps.Collect(gotRemoved.All()); !stdmaps.Equal(got, removed) {
+		t.Fatalf("got removed %v, want %v", got, removed)
+	}
+	if got := stdmaps.Collect(gotChanged.All()); !stdmaps.Equal(got, changed) {
+		t.Fatalf("got changed %v, want %v", got, changed)
+	}
+	if !gotRemoved.Contains(0) {
+		t.Fatalf("got removed.Contains(0)=false, want true")
+	}
+}
+
+func TestDiffHasher(t *testing.T) {
+	var old = maps.NewWithSeed[string, int](maphash.MakeSeed()).Assoc("a", 1).Assoc("b", 2)
+	var new = maps.New[string, int]().Assoc("b", 3).Assoc("c", 4)
+
+	var added, removed, changed = maps.Diff(old, new)
+	if got, want := added.String(), "map[c:4]"; got != want {
+		t.Fatalf("got added %s, want %s", got, want)
+	}
+	if got, want := removed.String(), "map[a:1]"; got != want {
+		t.Fatalf("got removed %s, want %s", got, want)
+	}
+	if got, want := changed.String(), "map[b:3]"; got != want {
+		t.Fatalf("got changed %s, want %s", got, want)
+	}
+	if !removed.Contains("a") {
+		t.Fatalf("got removed.Contains(a)=false, want true")
+	}
+}
@@ -0,0 +1,121 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/toddgaunt/persistent/codec"
+)
+
+// binaryVersion is the version of the format written by MarshalBinary.
+const binaryVersion = 1
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The format
+// of the encoded map is, in order:
+//
+//	version: a single byte, currently 1
+//	count:   the number of entries as a uvarint
+//	entries: each key followed by its value, both as a uvarint length
+//	         followed by that many bytes
+//
+// The entries are written in an unspecified order, which doesn't affect the map
+// they decode to. Each key and value is encoded by codec.MarshalBinary, so
+// those of a type with a registered codec are converted to their
+// representation first. Any type of key or value which codec.MarshalBinary
+// can't encode results in an error.
+func (m Map[K, V]) MarshalBinary() ([]byte, error) {
+	var data = []byte{binaryVersion}
+	data = binary.AppendUvarint(data, uint64(m.count))
+
+	var err error
+	for key, value := range m.All() {
+		if data, err = appendValue(data, key); err != nil {
+			return nil, err
+		}
+		if data, err = appendValue(data, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// replacing the contents of m with the map encoded in data using the format
+// described by MarshalBinary. Keys are hashed and compared as they are in m.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("maps: missing binary format version")
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("maps: unsupported binary format version %d", data[0])
+	}
+	data = data[1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("maps: invalid entry count")
+	}
+	data = data[n:]
+
+	// Every entry takes at least two bytes to encode the lengths of its key
+	// and value, so this bounds the count by the size of the input.
+	if count > uint64(len(data))/2 {
+		return fmt.Errorf("maps: entry count %d exceeds the encoded data", count)
+	}
+
+	var t = Map[K, V]{hasher: m.hasher}.transient(int(count))
+	for i := uint64(0); i < count; i++ {
+		var key K
+		var value V
+		var err error
+		if data, err = readValue(data, &key); err != nil {
+			return fmt.Errorf("maps: key %d: %w", i, err)
+		}
+		if data, err = readValue(data, &value); err != nil {
+			return fmt.Errorf("maps: value %d: %w", i, err)
+		}
+		t.assoc(key, value)
+	}
+
+	if len(data) > 0 {
+		return fmt.Errorf("maps: %d trailing bytes after entries", len(data))
+	}
+	if uint64(t.count) != count {
+		return fmt.Errorf("maps: %d duplicate keys", count-uint64(t.count))
+	}
+
+	*m = t.persistent()
+	return nil
+}
+
+// appendValue appends value to data as a uvarint length followed by its
+// encoding.
+func appendValue[T any](data []byte, value T) ([]byte, error) {
+	var b, err = codec.MarshalBinary(value)
+	if err != nil {
+		return nil, err
+	}
+	data = binary.AppendUvarint(data, uint64(len(b)))
+	return append(data, b...), nil
+}
+
+// readValue decodes a value written by appendValue from the start of data
+// into value, returning the rest of data.
+func readValue[T any](data []byte, value *T) ([]byte, error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return nil, errors.New("invalid length")
+	}
+	data = data[n:]
+
+	if err := codec.UnmarshalBinary(data[:size], value); err != nil {
+		return nil, err
+	}
+	return data[size:], nil
+}
@@ -0,0 +1,97 @@
+package maps_test
+
+import (
+	"bytes"
+	stdmaps "maps"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestMapBinary(t *testing.T) {
+	t.Run("Ints", func(t *testing.T) {
+		var want = map[int]int{}
+		for i := 0; i < 1000; i++ {
+			want[i] = -i
+		}
+		data, err := maps.From(want).MarshalBinary()
+		if err != nil {
+			t.Fatalf("got marshal error %v", err)
+		}
+		var got maps.Map[int, int]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("got unmarshal error %v", err)
+		}
+		if got := maps.ToGoMap(got); !stdmaps.Equal(got, want) {
+			t.Fatalf("got %d entries, want %d entries", len(got), len(want))
+		}
+		var wrong maps.Map[int, int16]
+		if err := wrong.UnmarshalBinary(data); err == nil {
+			t.Fatalf("got nil error decoding ints as int16s, want error")
+		}
+	})
+
+	t.Run("Strings", func(t *testing.T) {
+		var want = map[string]string{"a": "hello", "": "empty", "b": ""}
+		data, err := maps.From(want).MarshalBinary()
+		if err != nil {
+			t.Fatalf("got marshal error %v", err)
+		}
+		var got maps.Map[string, string]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("got unmarshal error %v", err)
+		}
+		if got := maps.ToGoMap(got); !stdmaps.Equal(got, want) {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		data, err := maps.New[string, int]().MarshalBinary()
+		if err != nil {
+			t.Fatalf("got marshal error %v", err)
+		}
+		if !bytes.Equal(data, []byte{1, 0}) {
+			t.Fatalf("got %v, want [1 0]", data)
+		}
+		var got = maps.New[string, int]().Assoc("a", 1)
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("got unmarshal error %v", err)
+		}
+		if got.Len() != 0 {
+			t.Fatalf("got Len()=%d, want Len()=0", got.Len())
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		if _, err := maps.New[string, []int]().Assoc("a", nil).MarshalBinary(); err == nil {
+			t.Fatalf("got nil error marshaling slices, want error")
+		}
+	})
+}
+
+func TestMapUnmarshalBinaryInvalid(t *testing.T) {
+	var testCases = []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", []byte{}},
+		{"Version", []byte{2, 0}},
+		{"MissingCount", []byte{1}},
+		{"CountTooLarge", []byte{1, 5, 1, 0, 1, 0}},
+		{"ShortKey", []byte{1, 1, 2, 0}},
+		{"MissingValue", []byte{1, 1, 1, 0, 2}},
+		{"Duplicate", []byte{1, 2, 1, 0, 1, 0, 1, 0, 1, 1}},
+		{"Trailing", []byte{1, 0, 0}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var m maps.Map[uint8, uint8]
+			if err := m.UnmarshalBinary(tc.data); err == nil {
+				t.Fatalf("got nil error, want error")
+			}
+		})
+	}
+}
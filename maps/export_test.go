@@ -0,0 +1,11 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+// NewMapWith creates a new empty Map which hashes keys with hash and compares
+// them with equal, so tests can choose the layout of its trie and collide keys.
+func NewMapWith[K comparable, V any](hash func(K) uint64, equal func(a, b K) bool) Map[K, V] {
+	return Map[K, V]{hasher: &hasher[K]{hash: hash, equal: equal}}
+}
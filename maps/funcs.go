@@ -0,0 +1,180 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+// MergeWith returns a new map containing the entries of both a and b. For keys
+// present in both maps, the value is the result of calling f with the value
+// from a as old and the value from b as new. The keys of b are hashed and
+// compared as they are in a.
+func MergeWith[K comparable, V any](a, b Map[K, V], f func(old, new V) V) Map[K, V] {
+	if a.count == 0 && a.hasher == b.hasher {
+		return b
+	}
+	if b.count == 0 {
+		return a
+	}
+
+	// The entries of b are added to a transient map, so each node of the
+	// result is copied from a at most once rather than for every entry.
+	var merged = a.transient(a.count + b.count)
+	b.root.forEach(func(e *entry[K, V]) bool {
+		var hash = e.hash
+		if a.hasher != b.hasher {
+			hash = a.hasher.hashOf(e.key)
+		}
+		merged.update(hash, e.key, func(old V, exists bool) V {
+			if exists {
+				return f(old, e.value)
+			}
+			return e.value
+		})
+		return true
+	})
+	return merged.persistent()
+}
+
+// Equal returns true if a and b contain the same keys associated to equal
+// values. Subtries shared between a and b, such as those left untouched by
+// the operations that made one from the other, are not compared.
+func Equal[K comparable, V comparable](a, b Map[K, V]) bool {
+	return EqualFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// EqualFunc is like Equal, but compares values using eq.
+func EqualFunc[K comparable, V any](a, b Map[K, V], eq func(V, V) bool) bool {
+	if a.count != b.count {
+		return false
+	}
+	if a.root == b.root {
+		return true
+	}
+	if a.hasher == b.hasher {
+		return equalNodes(a.hasher, 0, a.root, b.root, eq)
+	}
+
+	// The maps hash keys differently, so their tries can't be compared
+	// structurally and each key must be looked up instead.
+	return a.root.forEach(func(e *entry[K, V]) bool {
+		var other = b.root.find(b.hasher, b.hasher.hashOf(e.key), 0, e.key)
+		return other != nil && eq(e.value, other.value)
+	})
+}
+
+// equalNodes returns true if the nodes a and b at the level of the trie
+// starting at shift contain the same keys associated to equal values. Since
+// the trie is canonical, nodes with the same keys have the same layout.
+func equalNodes[K comparable, V any](h keyHasher[K], shift uint, a, b *node[K, V], eq func(V, V) bool) bool {
+	if a == b {
+		return true
+	}
+
+	if shift >= hashBits {
+		// Entries of a collision node are in the order they were associated,
+		// so each must be searched for.
+		if len(a.entries) != len(b.entries) {
+			return false
+		}
+		for i := range a.entries {
+			var other = b.find(h, a.entries[i].hash, shift, a.entries[i].key)
+			if other == nil || !eq(a.entries[i].value, other.value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if a.datamap != b.datamap || a.nodemap != b.nodemap {
+		return false
+	}
+	for i := range a.entries {
+		var x, y = &a.entries[i], &b.entries[i]
+		if x.hash != y.hash || !h.equals(x.key, y.key) || !eq(x.value, y.value) {
+			return false
+		}
+	}
+	for i := range a.children {
+		if !equalNodes(h, shift+nodeBits, a.children[i], b.children[i], eq) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns a new map containing only the entries of m for which pred
+// returns true. Subtries where every entry is kept are shared with m rather
+// than copied.
+func Filter[K comparable, V any](m Map[K, V], pred func(K, V) bool) Map[K, V] {
+	if m.root == nil {
+		return m
+	}
+
+	// The entries left out are removed from a transient map, so each node of
+	// the result is copied from m at most once rather than for every entry
+	// removed, and nodes without any removed entries aren't copied at all.
+	var filtered = m.transient(m.count)
+	var removed = false
+	m.root.forEach(func(e *entry[K, V]) bool {
+		if !pred(e.key, e.value) {
+			filtered.dissoc(e.hash, e.key)
+			removed = true
+		}
+		return true
+	})
+	if !removed {
+		return m
+	}
+	return filtered.persistent()
+}
+
+// MapValues returns a new map with the same keys as m, each associated to the
+// result of calling f with the value it is associated to in m. The layout of
+// m's trie is reused, so no keys are rehashed or compared.
+func MapValues[K comparable, V any, U any](m Map[K, V], f func(V) U) Map[K, U] {
+	if m.root == nil {
+		return Map[K, U]{hasher: m.hasher}
+	}
+
+	return Map[K, U]{
+		count:  m.count,
+		root:   mapValues(m.root, f),
+		hasher: m.hasher,
+	}
+}
+
+func mapValues[K comparable, V any, U any](n *node[K, V], f func(V) U) *node[K, U] {
+	var mapped = &node[K, U]{
+		datamap: n.datamap,
+		nodemap: n.nodemap,
+		entries: make([]entry[K, U], len(n.entries)),
+	}
+	for i, e := range n.entries {
+		mapped.entries[i] = entry[K, U]{hash: e.hash, key: e.key, value: f(e.value)}
+	}
+	if len(n.children) > 0 {
+		mapped.children = make([]*node[K, U], len(n.children))
+		for i, child := range n.children {
+			mapped.children[i] = mapValues(child, f)
+		}
+	}
+	return mapped
+}
+
+// From returns a new map with the same entries as the Go map m.
+func From[K comparable, V any](m map[K]V) Map[K, V] {
+	var t = New[K, V]().transient(len(m))
+	for key, value := range m {
+		t.assoc(key, value)
+	}
+	return t.persistent()
+}
+
+// ToGoMap returns a new Go map with the same entries as m.
+func ToGoMap[K comparable, V any](m Map[K, V]) map[K]V {
+	var result = make(map[K]V, m.count)
+	for key, value := range m.All() {
+		result[key] = value
+	}
+	return result
+}
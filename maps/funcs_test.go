@@ -0,0 +1,322 @@
+package maps_test
+
+import (
+	"fmt"
+	"hash/maphash"
+	stdmaps "maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func fromGoMap[K comparable, V any](m map[K]V) maps.Map[K, V] {
+	var result = maps.New[K, V]()
+	for k, v := range m {
+		result = result.Assoc(k, v)
+	}
+	return result
+}
+
+func TestMergeWith(t *testing.T) {
+	var sum = func(old, new int) int { return old + new }
+
+	var testCases = []struct {
+		name string
+		a    map[string]int
+		b    map[string]int
+		want map[string]int
+	}{
+		{"Empty", map[string]int{}, map[string]int{}, map[string]int{}},
+		{"EmptyFirst", map[string]int{}, map[string]int{"a": 1}, map[string]int{"a": 1}},
+		{"EmptySecond", map[string]int{"a": 1}, map[string]int{}, map[string]int{"a": 1}},
+		{"Disjoint", map[string]int{"a": 1}, map[string]int{"b": 2}, map[string]int{"a": 1, "b": 2}},
+		{"Conflict", map[string]int{"a": 1, "b": 2}, map[string]int{"b": 3, "c": 4}, map[string]int{"a": 1, "b": 5, "c": 4}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var a, b = fromGoMap(tc.a), fromGoMap(tc.b)
+			var got = maps.MergeWith(a, b, sum)
+			if got := stdmaps.Collect(got.All()); !stdmaps.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			if got, want := got.Len(), len(tc.want); got != want {
+				t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+			}
+			if got := stdmaps.Collect(a.All()); !stdmaps.Equal(got, tc.a) {
+				t.Fatalf("got a %v after merging, want %v", got, tc.a)
+			}
+		})
+	}
+}
+
+func TestMergeWithShared(t *testing.T) {
+	// Merging maps which share most of their nodes must leave both unchanged.
+	var a = maps.New[int, int]()
+	for i := 0; i < 5000; i++ {
+		a = a.Assoc(i, i)
+	}
+	var b = a
+	for i := 2500; i < 7500; i++ {
+		b = b.Assoc(i, 1)
+	}
+	var wantA, wantB = stdmaps.Collect(a.All()), stdmaps.Collect(b.All())
+
+	var got = maps.MergeWith(a, b, func(old, new int) int { return old + new })
+	for i := 0; i < 7500; i++ {
+		var want = i + i
+		switch {
+		case i >= 5000:
+			want = 1
+		case i >= 2500:
+			want = i + 1
+		}
+		if got.Get(i) != want {
+			t.Fatalf("got Get(%d)=%d, want Get(%d)=%d", i, got.Get(i), i, want)
+		}
+	}
+	if got, want := got.Len(), 7500; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got := stdmaps.Collect(a.All()); !stdmaps.Equal(got, wantA) {
+		t.Fatalf("got a changed by merging")
+	}
+	if got := stdmaps.Collect(b.All()); !stdmaps.Equal(got, wantB) {
+		t.Fatalf("got b changed by merging")
+	}
+}
+
+func TestMergeWithOrder(t *testing.T) {
+	var a = maps.New[string, string]().Assoc("k", "old")
+	var b = maps.New[string, string]().Assoc("k", "new")
+	var got = maps.MergeWith(a, b, func(old, new string) string { return old + "," + new })
+	if got, want := got.Get("k"), "old,new"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMergeWithHasher(t *testing.T) {
+	var seed = maphash.MakeSeed()
+	var hash = func(key string) uint64 {
+		return maphash.String(seed, strings.ToLower(key))
+	}
+
+	var a = maps.NewMapWith[string, int](hash, strings.EqualFold).Assoc("a", 1)
+	var b = maps.New[string, int]().Assoc("A", 2).Assoc("b", 3)
+	var got = maps.MergeWith(a, b, func(old, new int) int { return old + new })
+
+	if got, want := got.Len(), 2; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := got.Get("a"), 3; got != want {
+		t.Fatalf("got Get(a)=%d, want Get(a)=%d", got, want)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	var testCases = []struct {
+		name string
+		a    map[int]int
+		b    map[int]int
+		want bool
+	}{
+		{"Empty", map[int]int{}, map[int]int{}, true},
+		{"Same", map[int]int{1: 1, 2: 2}, map[int]int{2: 2, 1: 1}, true},
+		{"DifferentLen", map[int]int{1: 1}, map[int]int{1: 1, 2: 2}, false},
+		{"DifferentKey", map[int]int{1: 1}, map[int]int{2: 1}, false},
+		{"DifferentValue", map[int]int{1: 1}, map[int]int{1: 2}, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := maps.Equal(fromGoMap(tc.a), fromGoMap(tc.b)); got != tc.want {
+				t.Fatalf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEqualVersions(t *testing.T) {
+	var a = maps.New[int, int]()
+	for i := 0; i < 10000; i++ {
+		a = a.Assoc(i, i)
+	}
+
+	// Making the same change in a different order must result in equal maps.
+	var b = a.Assoc(10000, 0).Dissoc(5000).Assoc(5000, 5000).Dissoc(10000)
+	if !maps.Equal(a, b) {
+		t.Fatalf("got Equal(a, b)=false, want true")
+	}
+
+	var c = a.Assoc(5000, -1)
+	if maps.Equal(a, c) {
+		t.Fatalf("got Equal(a, c)=true, want false")
+	}
+	if !maps.EqualFunc(a, c, func(x, y int) bool { return x == y || y == -1 }) {
+		t.Fatalf("got EqualFunc(a, c)=false, want true")
+	}
+}
+
+func TestEqualHasher(t *testing.T) {
+	var seed = maphash.MakeSeed()
+	var hash = func(key string) uint64 {
+		return maphash.String(seed, key)
+	}
+
+	var a = maps.NewMapWith[string, int](hash, func(x, y string) bool { return x == y }).Assoc("a", 1).Assoc("b", 2)
+	var b = maps.New[string, int]().Assoc("b", 2).Assoc("a", 1)
+	if !maps.Equal(a, b) {
+		t.Fatalf("got Equal(a, b)=false, want true")
+	}
+	if maps.Equal(a, b.Assoc("b", 3)) {
+		t.Fatalf("got Equal(a, b)=true after changing b, want false")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	const n = 10000
+
+	var m = maps.New[int, int]()
+	var want = map[int]int{}
+	for i := 0; i < n; i++ {
+		m = m.Assoc(i, i)
+		if i%3 == 0 {
+			want[i] = i
+		}
+	}
+
+	var got = maps.Filter(m, func(k, v int) bool { return k%3 == 0 })
+	if got := stdmaps.Collect(got.All()); !stdmaps.Equal(got, want) {
+		t.Fatalf("got %d entries, want %d entries", len(got), len(want))
+	}
+	if got, want := got.Len(), len(want); got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := m.Len(), n; got != want {
+		t.Fatalf("got original Len()=%d after filtering, want Len()=%d", got, want)
+	}
+
+	// The filtered map must be laid out the same as one built directly from
+	// the kept entries.
+	if !maps.Equal(got, fromGoMap(want)) {
+		t.Fatalf("got Equal(filtered, built)=false, want true")
+	}
+	var empty maps.Map[int, int]
+	if got, want := got.Stats(empty), fromGoMap(want).Stats(empty); !slices.Equal(got.Nodes, want.Nodes) {
+		t.Fatalf("got nodes %v per level, want %v", got.Nodes, want.Nodes)
+	}
+}
+
+func TestFilterCollisions(t *testing.T) {
+	var m = maps.NewMapWith[int, int](func(int) uint64 { return 1 }, func(a, b int) bool { return a == b })
+	for i := 0; i < 10; i++ {
+		m = m.Assoc(i, i)
+	}
+
+	var got = maps.Filter(m, func(k, v int) bool { return k == 4 || k == 7 })
+	if got, want := stdmaps.Collect(got.All()), map[int]int{4: 4, 7: 7}; !stdmaps.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := m.Len(), 10; got != want {
+		t.Fatalf("got original Len()=%d after filtering, want Len()=%d", got, want)
+	}
+	if got := maps.Filter(got, func(k, v int) bool { return k == 7 }); got.Get(7) != 7 || got.Len() != 1 {
+		t.Fatalf("got %v, want map[7:7]", got)
+	}
+}
+
+func TestFilterAllOrNone(t *testing.T) {
+	var m = fromGoMap(map[string]int{"a": 1, "b": 2, "c": 3})
+
+	if got := maps.Filter(m, func(string, int) bool { return true }); !maps.Equal(got, m) {
+		t.Fatalf("got %v, want %v", got, m)
+	}
+	if got := maps.Filter(m, func(string, int) bool { return false }); got.Len() != 0 {
+		t.Fatalf("got %v, want map[]", got)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	var m = maps.New[int, int]()
+	var want = map[int]string{}
+	for i := 0; i < 1000; i++ {
+		m = m.Assoc(i, i)
+		want[i] = fmt.Sprint(i * 2)
+	}
+
+	var got = maps.MapValues(m, func(v int) string { return fmt.Sprint(v * 2) })
+	if got := stdmaps.Collect(got.All()); !stdmaps.Equal(got, want) {
+		t.Fatalf("got %d entries, want %d entries", len(got), len(want))
+	}
+	if got, want := got.Len(), len(want); got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+
+	// The mapped map must still find keys and accept changes.
+	if got, want := got.Assoc(1000, "x").Dissoc(0).Get(1000), "x"; got != want {
+		t.Fatalf("got Get(1000)=%q, want Get(1000)=%q", got, want)
+	}
+	if got, want := maps.MapValues(maps.New[int, int](), strconv.Itoa).Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	var testCases = []struct {
+		name string
+		m    map[int]int
+	}{
+		{"Empty", map[int]int{}},
+		{"Nil", nil},
+		{"Single", map[int]int{1: 1}},
+		{"Many", func() map[int]int {
+			var m = map[int]int{}
+			for i := 0; i < 10000; i++ {
+				m[i] = i * 2
+			}
+			return m
+		}()},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got = maps.From(tc.m)
+			if got, want := got.Len(), len(tc.m); got != want {
+				t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+			}
+			for k, v := range tc.m {
+				if got := got.Get(k); got != v {
+					t.Fatalf("got Get(%d)=%d, want Get(%d)=%d", k, got, k, v)
+				}
+			}
+			if !maps.Equal(got, fromGoMap(tc.m)) {
+				t.Fatalf("got a different map than one built with Assoc")
+			}
+			if back := maps.ToGoMap(got); !stdmaps.Equal(back, tc.m) {
+				t.Fatalf("got %v from ToGoMap, want %v", back, tc.m)
+			}
+		})
+	}
+}
+
+func TestFromPersistent(t *testing.T) {
+	var want = map[int]int{}
+	for i := 0; i < 1000; i++ {
+		want[i] = i
+	}
+
+	// Changes to a map made by From must not affect it.
+	var m = maps.From(want)
+	for i := 0; i < 1000; i++ {
+		_ = m.Assoc(i, -i).Assoc(i+1000, i)
+	}
+	if got := maps.ToGoMap(m); !stdmaps.Equal(got, want) {
+		t.Fatalf("got %d entries after changes, want %d unchanged entries", len(got), len(want))
+	}
+}
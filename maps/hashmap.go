@@ -0,0 +1,201 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// funcHasher hashes and compares keys with the functions given to NewWith.
+type funcHasher[K any] struct {
+	hash  func(K) uint64
+	equal func(a, b K) bool
+}
+
+func (h *funcHasher[K]) hashOf(key K) uint64 {
+	return h.hash(key)
+}
+
+func (h *funcHasher[K]) equals(a, b K) bool {
+	return h.equal(a, b)
+}
+
+// HashMap is a persistent map like Map, which hashes and compares its keys
+// with the functions given to NewWith rather than as a Go map would. This
+// allows keys of any type to be used, including those which can't be
+// compared with ==, such as slices. Since a HashMap can't hash its keys
+// without those functions, a HashMap must be created with NewWith, and its
+// zero value is only usable as an empty map which can't be added to.
+type HashMap[K any, V any] struct {
+	count  int         // Number of entries in this map
+	root   *node[K, V] // Root of the trie, nil when the map is empty
+	hasher *funcHasher[K]
+}
+
+// NewWith creates a new empty persistent map which hashes keys with hash and
+// compares them with equal, such as to key a map by byte slices, by the
+// numbers big.Int values hold, or by strings without regard to case. Keys
+// which are equal must have the same hash.
+func NewWith[K any, V any](hash func(K) uint64, equal func(a, b K) bool) HashMap[K, V] {
+	return HashMap[K, V]{hasher: &funcHasher[K]{hash: hash, equal: equal}}
+}
+
+// Len returns the number of entries in m.
+func (m HashMap[K, V]) Len() int {
+	return m.count
+}
+
+// find returns the entry for key, or nil if there isn't one.
+func (m HashMap[K, V]) find(key K) *entry[K, V] {
+	if m.root == nil {
+		return nil
+	}
+	return m.root.find(m.hasher, m.hasher.hashOf(key), 0, key)
+}
+
+// Get returns the value associated with key, or the zero value if key isn't
+// present in m.
+func (m HashMap[K, V]) Get(key K) V {
+	if e := m.find(key); e != nil {
+		return e.value
+	}
+
+	var zero V
+	return zero
+}
+
+// GetOK returns the value associated with key and true, or the zero value and
+// false if key isn't present in m.
+func (m HashMap[K, V]) GetOK(key K) (V, bool) {
+	if e := m.find(key); e != nil {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// GetOr returns the value associated with key, or def if key isn't present in
+// m.
+func (m HashMap[K, V]) GetOr(key K, def V) V {
+	if e := m.find(key); e != nil {
+		return e.value
+	}
+	return def
+}
+
+// Contains returns true if key is present in m.
+func (m HashMap[K, V]) Contains(key K) bool {
+	return m.find(key) != nil
+}
+
+// Assoc returns a new map with key associated to value, replacing any value
+// key was already associated to.
+func (m HashMap[K, V]) Assoc(key K, value V) HashMap[K, V] {
+	return m.Update(key, func(V, bool) V { return value })
+}
+
+// Update returns a new map with key associated to the result of calling f with
+// the value key is currently associated to and true, or with the zero value
+// and false if key isn't present in m. The trie is only traversed once.
+func (m HashMap[K, V]) Update(key K, f func(value V, exists bool) V) HashMap[K, V] {
+	var hash = m.hasher.hashOf(key)
+	if m.root == nil {
+		var e = entry[K, V]{hash: hash, key: key, value: f(*new(V), false)}
+		return HashMap[K, V]{
+			count:  1,
+			root:   &node[K, V]{datamap: bitpos(hash, 0), entries: []entry[K, V]{e}},
+			hasher: m.hasher,
+		}
+	}
+
+	var root, added = m.root.assoc(m.hasher, nil, 0, hash, key, f)
+	var count = m.count
+	if added {
+		count += 1
+	}
+
+	return HashMap[K, V]{
+		count:  count,
+		root:   root,
+		hasher: m.hasher,
+	}
+}
+
+// Dissoc returns a new map without key. If key isn't present in m, m itself is
+// returned.
+func (m HashMap[K, V]) Dissoc(key K) HashMap[K, V] {
+	if m.root == nil {
+		return m
+	}
+
+	var root, removed = m.root.dissoc(m.hasher, nil, m.hasher.hashOf(key), 0, key)
+	if !removed {
+		return m
+	}
+	if m.count == 1 {
+		return HashMap[K, V]{hasher: m.hasher}
+	}
+
+	return HashMap[K, V]{
+		count:  m.count - 1,
+		root:   root,
+		hasher: m.hasher,
+	}
+}
+
+// All returns an iterator over each key and value of m. The order of
+// iteration is unspecified, but is the same each time m is iterated over.
+func (m HashMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.root == nil {
+			return
+		}
+		m.root.forEach(func(e *entry[K, V]) bool {
+			return yield(e.key, e.value)
+		})
+	}
+}
+
+// Keys returns an iterator over each key of m, in the same order as All.
+func (m HashMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over each value of m, in the same order as All.
+func (m HashMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String returns a representation of a map in the same form as Map.String.
+func (m HashMap[K, V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("map[")
+	var first = true
+	for key, value := range m.All() {
+		if !first {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%v:%v", key, value)
+		first = false
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,175 @@
+package maps_test
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestNewWith(t *testing.T) {
+	var seed = maphash.MakeSeed()
+
+	t.Run("Bytes", func(t *testing.T) {
+		var hash = func(key []byte) uint64 {
+			return maphash.Bytes(seed, key)
+		}
+
+		var m = maps.NewWith[[]byte, int](hash, bytes.Equal)
+		for i := 0; i < 1000; i++ {
+			m = m.Assoc(fmt.Appendf(nil, "key%d", i), i)
+		}
+		m = m.Assoc([]byte("key0"), -1).Dissoc([]byte("key1"))
+
+		if got, want := m.Len(), 999; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		if got, want := m.Get([]byte("key0")), -1; got != want {
+			t.Fatalf("got Get(key0)=%d, want Get(key0)=%d", got, want)
+		}
+		if m.Contains([]byte("key1")) {
+			t.Fatalf("got Contains(key1)=true after Dissoc, want false")
+		}
+		if got, ok := m.GetOK([]byte("key500")); !ok || got != 500 {
+			t.Fatalf("got GetOK(key500)=%d, %t, want GetOK(key500)=500, true", got, ok)
+		}
+		if got, want := m.GetOr([]byte("missing"), -2), -2; got != want {
+			t.Fatalf("got GetOr(missing)=%d, want GetOr(missing)=%d", got, want)
+		}
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		var hash = func(key []int) uint64 {
+			var h maphash.Hash
+			h.SetSeed(seed)
+			for _, x := range key {
+				maphash.WriteComparable(&h, x)
+			}
+			return h.Sum64()
+		}
+
+		var m = maps.NewWith[[]int, string](hash, slices.Equal[[]int])
+		m = m.Assoc([]int{1, 2}, "a").Assoc([]int{2, 1}, "b").Assoc([]int{1, 2}, "c")
+
+		if got, want := m.Len(), 2; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		if got, want := m.Get([]int{1, 2}), "c"; got != want {
+			t.Fatalf("got Get([1 2])=%q, want Get([1 2])=%q", got, want)
+		}
+	})
+
+	t.Run("BigInt", func(t *testing.T) {
+		var hash = func(key *big.Int) uint64 {
+			return maphash.Bytes(seed, key.Bytes()) ^ uint64(key.Sign()+1)
+		}
+		var equal = func(a, b *big.Int) bool {
+			return a.Cmp(b) == 0
+		}
+
+		var m = maps.NewWith[*big.Int, string](hash, equal)
+		for i := 0; i < 1000; i++ {
+			m = m.Assoc(big.NewInt(int64(i)), fmt.Sprint(i))
+		}
+		m = m.Assoc(big.NewInt(0), "replaced").Dissoc(big.NewInt(1))
+
+		if got, want := m.Len(), 999; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		if got, want := m.Get(big.NewInt(0)), "replaced"; got != want {
+			t.Fatalf("got Get(0)=%q, want Get(0)=%q", got, want)
+		}
+		if m.Contains(big.NewInt(1)) {
+			t.Fatalf("got Contains(1)=true after Dissoc, want false")
+		}
+		if got, want := m.Get(big.NewInt(500)), "500"; got != want {
+			t.Fatalf("got Get(500)=%q, want Get(500)=%q", got, want)
+		}
+	})
+
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		var hash = func(key string) uint64 {
+			return maphash.String(seed, strings.ToLower(key))
+		}
+
+		var m = maps.NewWith[string, int](hash, strings.EqualFold)
+		m = m.Assoc("Hello", 1).Assoc("HELLO", 2)
+
+		if got, want := m.Len(), 1; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		if got, want := m.Get("hello"), 2; got != want {
+			t.Fatalf("got Get(hello)=%d, want Get(hello)=%d", got, want)
+		}
+		if got, want := m.Dissoc("hElLo").Len(), 0; got != want {
+			t.Fatalf("got Len()=%d after Dissoc, want Len()=%d", got, want)
+		}
+	})
+}
+
+func TestHashMapCollisions(t *testing.T) {
+	var m = maps.NewWith[[]byte, int](func([]byte) uint64 { return 7 }, bytes.Equal)
+	for i := 0; i < 10; i++ {
+		m = m.Assoc([]byte{byte(i)}, i)
+	}
+	m = m.Update([]byte{3}, func(value int, exists bool) int {
+		if !exists {
+			t.Fatalf("got exists=false for a present key, want true")
+		}
+		return value * 10
+	})
+
+	for i := 0; i < 10; i++ {
+		var want = i
+		if i == 3 {
+			want = 30
+		}
+		if got := m.Get([]byte{byte(i)}); got != want {
+			t.Fatalf("got Get(%d)=%d, want Get(%d)=%d", i, got, i, want)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		m = m.Dissoc([]byte{byte(i)})
+		if got, want := m.Len(), 9-i; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+	}
+}
+
+func TestHashMapIter(t *testing.T) {
+	var m = maps.NewWith[[]byte, int](func(key []byte) uint64 { return uint64(len(key)) }, bytes.Equal)
+	m = m.Assoc([]byte("a"), 1).Assoc([]byte("bb"), 2)
+
+	var got []string
+	for key, value := range m.All() {
+		got = append(got, fmt.Sprintf("%s:%d", key, value))
+	}
+	slices.Sort(got)
+	if want := []string{"a:1", "bb:2"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(m.Values()), []int{1, 2}; !slices.Equal(got, want) {
+		t.Fatalf("got Values()=%v, want %v", got, want)
+	}
+	if got, want := len(slices.Collect(m.Keys())), 2; got != want {
+		t.Fatalf("got %d keys, want %d", got, want)
+	}
+	if got, want := m.String(), "map[[97]:1 [98 98]:2]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestHashMapEmpty(t *testing.T) {
+	var m maps.HashMap[[]byte, int]
+	if got, ok := m.GetOK([]byte("a")); ok || got != 0 {
+		t.Fatalf("got GetOK(a)=%d, %t on the zero value, want 0, false", got, ok)
+	}
+	if got, want := m.Dissoc([]byte("a")).Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+}
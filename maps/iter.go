@@ -0,0 +1,42 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import "iter"
+
+// All returns an iterator over each key and value of m. The order of
+// iteration is unspecified, but is the same each time m is iterated over.
+func (m Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.root == nil {
+			return
+		}
+		m.root.forEach(func(e *entry[K, V]) bool {
+			return yield(e.key, e.value)
+		})
+	}
+}
+
+// Keys returns an iterator over each key of m, in the same order as All.
+func (m Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over each value of m, in the same order as All.
+func (m Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
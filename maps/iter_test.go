@@ -0,0 +1,68 @@
+package maps_test
+
+import (
+	stdmaps "maps"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestMapAll(t *testing.T) {
+	var want = map[int]int{}
+	var m = maps.New[int, int]()
+	for i := 0; i < 1000; i++ {
+		want[i] = i * 2
+		m = m.Assoc(i, i*2)
+	}
+
+	var got = stdmaps.Collect(m.All())
+	if !stdmaps.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := stdmaps.Collect(maps.New[int, int]().All()); len(got) != 0 {
+		t.Fatalf("got %v from an empty map, want map[]", got)
+	}
+}
+
+func TestMapKeysValues(t *testing.T) {
+	var m = maps.New[string, int]().Assoc("a", 1).Assoc("b", 2).Assoc("c", 3)
+
+	var keys = slices.Sorted(m.Keys())
+	if got, want := keys, []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("got keys %v, want %v", got, want)
+	}
+	var values = slices.Sorted(m.Values())
+	if got, want := values, []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("got values %v, want %v", got, want)
+	}
+
+	// Keys and values must be yielded in the same order as All.
+	var i = 0
+	var allKeys = slices.Collect(m.Keys())
+	var allValues = slices.Collect(m.Values())
+	for key, value := range m.All() {
+		if allKeys[i] != key || allValues[i] != value {
+			t.Fatalf("got %s:%d at %d, want %s:%d", allKeys[i], allValues[i], i, key, value)
+		}
+		i += 1
+	}
+}
+
+func TestMapAllBreak(t *testing.T) {
+	var m = maps.New[int, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Assoc(i, i)
+	}
+
+	var count = 0
+	for range m.All() {
+		count += 1
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Fatalf("got %d entries before break, want 10", count)
+	}
+}
@@ -0,0 +1,167 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/toddgaunt/persistent/codec"
+)
+
+// MarshalJSON implements the json.Marshaler interface. The map is encoded as a
+// JSON object in the same way as a Go map with the same entries, so its keys
+// must be strings, integers or implement encoding.TextMarshaler, and are
+// written in sorted order. Keys and values of a type with a codec registered
+// in the codec package are encoded as their representation.
+func (m Map[K, V]) MarshalJSON() ([]byte, error) {
+	var keyCodec = codec.For[K]()
+	var keyType, valueType = reflect.TypeFor[K](), reflect.TypeFor[V]()
+	if keyCodec != nil {
+		keyType = keyCodec.Repr()
+	}
+	var encodeValues = codec.Applies[V]()
+	if encodeValues {
+		valueType = reflect.TypeFor[any]()
+	}
+	if !keyType.Comparable() {
+		return nil, fmt.Errorf("maps: cannot encode a map with keys of type %s as JSON", keyType)
+	}
+
+	var result = reflect.MakeMapWithSize(reflect.MapOf(keyType, valueType), m.count)
+	for key, value := range m.All() {
+		var k, v = reflect.ValueOf(&key).Elem(), reflect.ValueOf(&value).Elem()
+		if keyCodec != nil {
+			var repr, err = keyCodec.Encode(key)
+			if err != nil {
+				return nil, fmt.Errorf("maps: key %v: %w", key, err)
+			}
+			k = reprValue(repr, keyType)
+		}
+		if encodeValues {
+			var repr, err = codec.Encode(value)
+			if err != nil {
+				return nil, fmt.Errorf("maps: value for key %v: %w", key, err)
+			}
+			v = reprValue(repr, valueType)
+		}
+		result.SetMapIndex(k, v)
+	}
+	return json.Marshal(result.Interface())
+}
+
+// reprValue returns repr as a value which can be stored in a Go map with
+// elements of type t.
+func reprValue(repr any, t reflect.Type) reflect.Value {
+	if repr == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(repr)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, replacing the
+// contents of m with the entries of a JSON object, decoded as DecodeJSON
+// decodes them. JSON null decodes to an empty map. Keys are hashed and
+// compared as they are in m.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	var decoded, err = decodeJSON(json.NewDecoder(bytes.NewReader(data)), Map[K, V]{hasher: m.hasher})
+	if err != nil {
+		return err
+	}
+	*m = decoded
+	return nil
+}
+
+// DecodeJSON reads the next JSON object from dec and returns a map of its
+// entries. Each value is decoded with dec straight into a transient map as it
+// is read, so a large object is never held in full as a Go map or as raw
+// JSON. Keys are converted as they are for a Go map, so K must be a string or
+// integer type or implement encoding.TextUnmarshaler, and a key appearing
+// more than once is associated to its last value. Keys and values are decoded
+// from their representation if a codec is registered for their type in the
+// codec package. JSON null decodes to an empty map.
+func DecodeJSON[K comparable, V any](dec *json.Decoder) (Map[K, V], error) {
+	return decodeJSON(dec, New[K, V]())
+}
+
+// decodeJSON decodes the next JSON object from dec as DecodeJSON does, into
+// the empty map m.
+func decodeJSON[K comparable, V any](dec *json.Decoder, m Map[K, V]) (Map[K, V], error) {
+	var tok, err = dec.Token()
+	if err != nil {
+		return m, err
+	}
+	if tok == nil {
+		return m, nil
+	}
+	if tok != json.Delim('{') {
+		return m, fmt.Errorf("maps: got JSON %v, want an object", tok)
+	}
+
+	var t = m.transient(0)
+	for dec.More() {
+		if tok, err = dec.Token(); err != nil {
+			return m, err
+		}
+		// Object keys are always strings, which dec has already checked.
+		var name = tok.(string)
+
+		var key K
+		var value V
+		err = codec.DecodeWith(&key, func(ptr any) error {
+			return decodeKey(name, ptr)
+		})
+		if err != nil {
+			return m, err
+		}
+		if err := codec.DecodeWith(&value, dec.Decode); err != nil {
+			return m, fmt.Errorf("maps: value for key %q: %w", name, err)
+		}
+		t.assoc(key, value)
+	}
+
+	// The closing brace is all that's left, since dec checks that the object
+	// is well formed.
+	if _, err := dec.Token(); err != nil {
+		return m, err
+	}
+	return t.persistent(), nil
+}
+
+// decodeKey sets the key ptr points to from the JSON object key name,
+// converted as encoding/json converts the keys of a Go map.
+func decodeKey(name string, ptr any) error {
+	if u, ok := ptr.(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(name)); err != nil {
+			return fmt.Errorf("maps: key %q: %w", name, err)
+		}
+		return nil
+	}
+
+	var v = reflect.ValueOf(ptr).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n, err = strconv.ParseInt(name, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return fmt.Errorf("maps: key %q is not a valid %s", name, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n, err = strconv.ParseUint(name, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return fmt.Errorf("maps: key %q is not a valid %s", name, v.Type())
+		}
+		v.SetUint(n)
+	default:
+		return fmt.Errorf("maps: cannot decode JSON object keys into keys of type %s", v.Type())
+	}
+	return nil
+}
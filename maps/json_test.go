@@ -0,0 +1,129 @@
+package maps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	stdmaps "maps"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestMapJSON(t *testing.T) {
+	var m = maps.New[string, vectors.Vector[int]]().Assoc("b", vectors.New(1, 2)).Assoc("a", vectors.New[int]())
+
+	var data, err = json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(data), `{"a":[],"b":[1,2]}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	var got maps.Map[string, vectors.Vector[int]]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if got.Len() != 2 || got.Get("b").String() != "[1 2]" || got.Get("a").Len() != 0 {
+		t.Fatalf("got %v, want %v", got, m)
+	}
+}
+
+func TestMapJSONKeys(t *testing.T) {
+	var want = map[int]any{}
+	for i := 0; i < 1000; i++ {
+		want[i-500] = nil
+	}
+	want[7] = "seven"
+
+	var data, err = json.Marshal(maps.From(want))
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	var got maps.Map[int, any]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if got := maps.ToGoMap(got); !stdmaps.Equal(got, want) {
+		t.Fatalf("got %d entries, want %d entries", len(got), len(want))
+	}
+}
+
+func TestMapJSONErrors(t *testing.T) {
+	for _, data := range []string{`[1]`, `{"a":"x"}`, `{"a":1`} {
+		var m maps.Map[string, int]
+		if err := json.Unmarshal([]byte(data), &m); err == nil {
+			t.Fatalf("got %v and nil error for %s, want an error", m, data)
+		}
+	}
+
+	var arrays = maps.New[[2]int, int]().Assoc([2]int{1, 2}, 1)
+	if data, err := json.Marshal(arrays); err == nil {
+		t.Fatalf("got %s and nil error marshaling array keys, want an error", data)
+	}
+}
+
+type upperKey string
+
+func (k *upperKey) UnmarshalText(text []byte) error {
+	*k = upperKey(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var sb strings.Builder
+	var want = map[int]string{}
+	sb.WriteString(`[{`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `"%d":"%d"`, i-500, i)
+		want[i-500] = fmt.Sprint(i)
+	}
+	sb.WriteString(`}, null]`)
+
+	var dec = json.NewDecoder(strings.NewReader(sb.String()))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		t.Fatalf("got token %v, %v, want [, nil", tok, err)
+	}
+	var got, err = maps.DecodeJSON[int, string](dec)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got := maps.ToGoMap(got); !stdmaps.Equal(got, want) {
+		t.Fatalf("got %d entries, want %d entries", len(got), len(want))
+	}
+
+	empty, err := maps.DecodeJSON[int, string](dec)
+	if err != nil || empty.Len() != 0 {
+		t.Fatalf("got %v, %v, want map[], nil", empty, err)
+	}
+}
+
+func TestDecodeJSONKeys(t *testing.T) {
+	var dec = json.NewDecoder(strings.NewReader(`{"a": [1], "b": [], "a": [2, 3]}`))
+	var got, err = maps.DecodeJSON[upperKey, vectors.Vector[int]](dec)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got.Len() != 2 || got.Get("A").String() != "[2 3]" || got.Get("B").Len() != 0 {
+		t.Fatalf("got %v, want map[A:[2 3] B:[]]", got)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	for _, data := range []string{``, `[1]`, `{"a":1}`, `{"300":1}`, `{"1":"x"}`, `{"1":1`} {
+		var dec = json.NewDecoder(strings.NewReader(data))
+		if m, err := maps.DecodeJSON[int8, int](dec); err == nil {
+			t.Fatalf("got %v and nil error for %s, want an error", m, data)
+		}
+	}
+
+	var dec = json.NewDecoder(strings.NewReader(`{"a":1}`))
+	if m, err := maps.DecodeJSON[float64, int](dec); err == nil {
+		t.Fatalf("got %v and nil error for float keys, want an error", m)
+	}
+}
@@ -1 +1,538 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package maps provides a persistent Map datastructure similar to the one
+// found in the Clojure programming language. The implementation is a hash
+// array mapped trie, where each node of the trie uses a bitmap to compactly
+// store only the entries and children it actually has.
 package maps
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// These constants determine the width of map nodes, and so how many bits of
+// a key's hash are consumed at each level of the trie.
+const (
+	nodeBits  = 5
+	nodeWidth = 1 << nodeBits
+	nodeMask  = nodeWidth - 1
+	hashBits  = 64
+)
+
+// seed is the seed used to hash keys for maps created with New. It is chosen
+// randomly for each process, so keys can't be picked ahead of time to all
+// share the same position in the trie and degrade lookups to a linear scan.
+var seed = maphash.MakeSeed()
+
+// keyHasher hashes and compares the keys of a trie. Keys which are equal must
+// have the same hash.
+type keyHasher[K any] interface {
+	hashOf(key K) uint64
+	equals(a, b K) bool
+}
+
+// hasher holds the functions a map uses to hash and compare its keys. A nil
+// hasher hashes and compares keys as a Go map would.
+type hasher[K comparable] struct {
+	hash  func(K) uint64
+	equal func(a, b K) bool
+}
+
+func (h *hasher[K]) hashOf(key K) uint64 {
+	if h == nil {
+		return maphash.Comparable(seed, key)
+	}
+	return h.hash(key)
+}
+
+func (h *hasher[K]) equals(a, b K) bool {
+	if h == nil {
+		return a == b
+	}
+	return h.equal(a, b)
+}
+
+type entry[K any, V any] struct {
+	hash  uint64 // Hash of key, kept to avoid rehashing as the trie changes
+	key   K
+	value V
+}
+
+// node is a node within the trie. A node holds entries and children at the
+// positions set in its datamap and nodemap respectively, in the order of those
+// positions. Once every bit of the hash has been consumed, a node instead
+// holds only entries with keys that all share the same hash, and is known as
+// a collision node. Collision nodes are handled separately, in collision.go.
+//
+// Other than the root, every node holds either more than one entry or at
+// least one child. This keeps the trie canonical, so the same set of keys
+// always results in the same layout regardless of the order they were
+// associated and dissociated in.
+//
+// A node may only be modified in place by the transient map that owns it,
+// which is the one that created it. Every other node is immutable.
+type node[K any, V any] struct {
+	datamap  uint32
+	nodemap  uint32
+	entries  []entry[K, V]
+	children []*node[K, V]
+	owner    *owner
+}
+
+// owner identifies a transient map, and is never shared between two of them.
+// Persistent maps have no owner, so the nodes they create can't be modified.
+type owner struct {
+	hint int // Number of entries the transient map is expected to hold
+}
+
+// capacity returns the number of items a node created by o at the level of
+// the trie starting at shift is expected to eventually hold, so that room for
+// them can be made up front.
+func (o *owner) capacity(shift uint) int {
+	if o == nil || shift >= hashBits {
+		return 0
+	}
+	return min(o.hint>>shift, nodeWidth)
+}
+
+// grow returns s with room for at least one more item, making room for up to
+// capacity items in total if it must be reallocated.
+func grow[T any](s []T, capacity int) []T {
+	if len(s) < cap(s) {
+		return s
+	}
+	return slices.Grow(s, max(capacity-len(s), 1))
+}
+
+// editable returns true if n may be modified in place by the transient map o.
+func (n *node[K, V]) editable(o *owner) bool {
+	return o != nil && n.owner == o
+}
+
+// share returns s to be used by a new node belonging to o. Nodes belonging to
+// a transient map may have their slices modified in place, so they can't
+// share them with other nodes.
+func share[T any](s []T, o *owner) []T {
+	if o == nil {
+		return s
+	}
+	return slices.Clone(s)
+}
+
+// bitpos returns the bit of a node's bitmaps for hash at the level of the trie
+// which consumes the bits of hash starting at shift.
+func bitpos(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & nodeMask)
+}
+
+// index returns the index of the item for bit among the items of bitmap.
+func index(bitmap, bit uint32) int {
+	return bits.OnesCount32(bitmap & (bit - 1))
+}
+
+func insertAt[T any](s []T, i int, value T) []T {
+	var inserted = make([]T, len(s)+1)
+	copy(inserted, s[:i])
+	inserted[i] = value
+	copy(inserted[i+1:], s[i:])
+	return inserted
+}
+
+func removeAt[T any](s []T, i int) []T {
+	var removed = make([]T, len(s)-1)
+	copy(removed, s[:i])
+	copy(removed[i:], s[i+1:])
+	return removed
+}
+
+func replaceAt[T any](s []T, i int, value T) []T {
+	var replaced = make([]T, len(s))
+	copy(replaced, s)
+	replaced[i] = value
+	return replaced
+}
+
+// find returns the entry for key, or nil if there isn't one.
+func (n *node[K, V]) find(h keyHasher[K], hash uint64, shift uint, key K) *entry[K, V] {
+	for n != nil {
+		if shift >= hashBits {
+			return n.findCollision(h, key)
+		}
+
+		var bit = bitpos(hash, shift)
+		if n.datamap&bit != 0 {
+			var e = &n.entries[index(n.datamap, bit)]
+			if e.hash == hash && h.equals(e.key, key) {
+				return e
+			}
+			return nil
+		}
+		if n.nodemap&bit == 0 {
+			return nil
+		}
+		n = n.children[index(n.nodemap, bit)]
+		shift += nodeBits
+	}
+	return nil
+}
+
+// assoc returns a node with key associated to the value returned by f, and
+// true if key wasn't already present. The function f is called with the value
+// key is already associated to and true, or the zero value and false. Nodes
+// which the transient map o may edit are modified in place, while the rest are
+// copied into new nodes belonging to o.
+func (n *node[K, V]) assoc(h keyHasher[K], o *owner, shift uint, hash uint64, key K, f func(V, bool) V) (*node[K, V], bool) {
+	if shift >= hashBits {
+		return n.assocCollision(h, o, hash, key, f)
+	}
+
+	var bit = bitpos(hash, shift)
+	switch {
+	case n.datamap&bit != 0:
+		var i = index(n.datamap, bit)
+		var existing = n.entries[i]
+		if existing.hash == hash && h.equals(existing.key, key) {
+			var e = entry[K, V]{hash: hash, key: key, value: f(existing.value, true)}
+			if n.editable(o) {
+				n.entries[i] = e
+				return n, false
+			}
+			return &node[K, V]{
+				datamap:  n.datamap,
+				nodemap:  n.nodemap,
+				entries:  replaceAt(n.entries, i, e),
+				children: share(n.children, o),
+				owner:    o,
+			}, false
+		}
+
+		// The position is taken by another key, so both move down into a new
+		// child node.
+		var e = entry[K, V]{hash: hash, key: key, value: f(*new(V), false)}
+		var child = merge(o, shift+nodeBits, existing, e)
+		var nodemap = n.nodemap | bit
+		if n.editable(o) {
+			n.datamap &^= bit
+			n.nodemap = nodemap
+			n.entries = slices.Delete(n.entries, i, i+1)
+			n.children = slices.Insert(grow(n.children, o.capacity(shift)), index(nodemap, bit), child)
+			return n, true
+		}
+		return &node[K, V]{
+			datamap:  n.datamap &^ bit,
+			nodemap:  nodemap,
+			entries:  removeAt(n.entries, i),
+			children: insertAt(n.children, index(nodemap, bit), child),
+			owner:    o,
+		}, true
+	case n.nodemap&bit != 0:
+		var i = index(n.nodemap, bit)
+		var child, added = n.children[i].assoc(h, o, shift+nodeBits, hash, key, f)
+		if n.editable(o) {
+			n.children[i] = child
+			return n, added
+		}
+		return &node[K, V]{
+			datamap:  n.datamap,
+			nodemap:  n.nodemap,
+			entries:  share(n.entries, o),
+			children: replaceAt(n.children, i, child),
+			owner:    o,
+		}, added
+	default:
+		var e = entry[K, V]{hash: hash, key: key, value: f(*new(V), false)}
+		var datamap = n.datamap | bit
+		if n.editable(o) {
+			n.datamap = datamap
+			n.entries = slices.Insert(grow(n.entries, o.capacity(shift)), index(datamap, bit), e)
+			return n, true
+		}
+		return &node[K, V]{
+			datamap:  datamap,
+			nodemap:  n.nodemap,
+			entries:  insertAt(n.entries, index(datamap, bit), e),
+			children: share(n.children, o),
+			owner:    o,
+		}, true
+	}
+}
+
+// merge returns a new node belonging to o holding the entries a and b, which
+// have different keys, at the level of the trie starting at shift.
+func merge[K any, V any](o *owner, shift uint, a, b entry[K, V]) *node[K, V] {
+	if shift >= hashBits {
+		return newCollision(o, a, b)
+	}
+
+	var abit, bbit = bitpos(a.hash, shift), bitpos(b.hash, shift)
+	if abit == bbit {
+		return &node[K, V]{
+			nodemap:  abit,
+			children: []*node[K, V]{merge(o, shift+nodeBits, a, b)},
+			owner:    o,
+		}
+	}
+
+	if abit > bbit {
+		a, b = b, a
+	}
+	var entries = make([]entry[K, V], 2, max(o.capacity(shift), 2))
+	entries[0], entries[1] = a, b
+	return &node[K, V]{
+		datamap: abit | bbit,
+		entries: entries,
+		owner:   o,
+	}
+}
+
+// dissoc returns a node without the entry for key, and true if there was an
+// entry for key to remove. If there wasn't, n itself is returned. Nodes which
+// the transient map o may edit are modified in place, while the rest are
+// copied into new nodes belonging to o.
+func (n *node[K, V]) dissoc(h keyHasher[K], o *owner, hash uint64, shift uint, key K) (*node[K, V], bool) {
+	if shift >= hashBits {
+		return n.dissocCollision(h, o, key)
+	}
+
+	var bit = bitpos(hash, shift)
+	switch {
+	case n.datamap&bit != 0:
+		var i = index(n.datamap, bit)
+		if n.entries[i].hash != hash || !h.equals(n.entries[i].key, key) {
+			return n, false
+		}
+		if n.editable(o) {
+			n.datamap &^= bit
+			n.entries = slices.Delete(n.entries, i, i+1)
+			return n, true
+		}
+		return &node[K, V]{
+			datamap:  n.datamap &^ bit,
+			nodemap:  n.nodemap,
+			entries:  removeAt(n.entries, i),
+			children: share(n.children, o),
+			owner:    o,
+		}, true
+	case n.nodemap&bit != 0:
+		var i = index(n.nodemap, bit)
+		var child, removed = n.children[i].dissoc(h, o, hash, shift+nodeBits, key)
+		if !removed {
+			return n, false
+		}
+
+		if child.nodemap == 0 && len(child.entries) == 1 {
+			// The child is left with a single entry, so the entry is moved up
+			// into this node to keep the trie canonical.
+			var datamap = n.datamap | bit
+			if n.editable(o) {
+				n.datamap = datamap
+				n.nodemap &^= bit
+				n.entries = slices.Insert(n.entries, index(datamap, bit), child.entries[0])
+				n.children = slices.Delete(n.children, i, i+1)
+				return n, true
+			}
+			return &node[K, V]{
+				datamap:  datamap,
+				nodemap:  n.nodemap &^ bit,
+				entries:  insertAt(n.entries, index(datamap, bit), child.entries[0]),
+				children: removeAt(n.children, i),
+				owner:    o,
+			}, true
+		}
+
+		if n.editable(o) {
+			n.children[i] = child
+			return n, true
+		}
+		return &node[K, V]{
+			datamap:  n.datamap,
+			nodemap:  n.nodemap,
+			entries:  share(n.entries, o),
+			children: replaceAt(n.children, i, child),
+			owner:    o,
+		}, true
+	default:
+		return n, false
+	}
+}
+
+// forEach calls yield with each entry within n and its children. Iteration
+// stops early if yield returns false, in which case forEach also returns
+// false.
+func (n *node[K, V]) forEach(yield func(*entry[K, V]) bool) bool {
+	for i := range n.entries {
+		if !yield(&n.entries[i]) {
+			return false
+		}
+	}
+	for _, child := range n.children {
+		if !child.forEach(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Map is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Assoc'ing a key to a Map, the previous version of that
+// Map can be used in more operations and referenced without having been
+// mutated from any operations it was used as input for. Only the nodes along
+// the path to the key are copied by each operation, while the rest are shared
+// between both versions. The zero value of Map is an empty map ready to use,
+// which hashes and compares keys as a Go map would. HashMap is used instead
+// for keys which must be hashed and compared some other way.
+type Map[K comparable, V any] struct {
+	count  int         // Number of entries in this map
+	root   *node[K, V] // Root of the trie, nil when the map is empty
+	hasher *hasher[K]  // Hashes and compares keys, nil to do so as a Go map would
+}
+
+// New creates a new empty persistent map.
+func New[K comparable, V any]() Map[K, V] {
+	return Map[K, V]{}
+}
+
+// NewWithSeed creates a new empty persistent map which hashes keys with seed
+// rather than the seed chosen for the process. Maps created with the same seed
+// and the same entries have the same layout and iteration order. Since a
+// maphash.Seed can't be chosen ahead of time, a HashMap created with NewWith
+// must be used instead for a layout that is the same between processes.
+func NewWithSeed[K comparable, V any](seed maphash.Seed) Map[K, V] {
+	return Map[K, V]{hasher: &hasher[K]{
+		hash:  func(key K) uint64 { return maphash.Comparable(seed, key) },
+		equal: func(a, b K) bool { return a == b },
+	}}
+}
+
+// Len returns the number of entries in m.
+func (m Map[K, V]) Len() int {
+	return m.count
+}
+
+// Get returns the value associated with key, or the zero value if key isn't
+// present in m.
+func (m Map[K, V]) Get(key K) V {
+	if e := m.root.find(m.hasher, m.hasher.hashOf(key), 0, key); e != nil {
+		return e.value
+	}
+
+	var zero V
+	return zero
+}
+
+// GetOK returns the value associated with key and true, or the zero value and
+// false if key isn't present in m.
+func (m Map[K, V]) GetOK(key K) (V, bool) {
+	if e := m.root.find(m.hasher, m.hasher.hashOf(key), 0, key); e != nil {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// GetOr returns the value associated with key, or def if key isn't present in
+// m.
+func (m Map[K, V]) GetOr(key K, def V) V {
+	if e := m.root.find(m.hasher, m.hasher.hashOf(key), 0, key); e != nil {
+		return e.value
+	}
+	return def
+}
+
+// Contains returns true if key is present in m.
+func (m Map[K, V]) Contains(key K) bool {
+	return m.root.find(m.hasher, m.hasher.hashOf(key), 0, key) != nil
+}
+
+// Assoc returns a new map with key associated to value, replacing any value
+// key was already associated to.
+func (m Map[K, V]) Assoc(key K, value V) Map[K, V] {
+	return m.update(m.hasher.hashOf(key), key, func(V, bool) V { return value })
+}
+
+// Update returns a new map with key associated to the result of calling f with
+// the value key is currently associated to and true, or with the zero value
+// and false if key isn't present in m. The trie is only traversed once.
+func (m Map[K, V]) Update(key K, f func(value V, exists bool) V) Map[K, V] {
+	return m.update(m.hasher.hashOf(key), key, f)
+}
+
+// update is Update for a key which has already been hashed.
+func (m Map[K, V]) update(hash uint64, key K, f func(V, bool) V) Map[K, V] {
+	if m.root == nil {
+		var e = entry[K, V]{hash: hash, key: key, value: f(*new(V), false)}
+		return Map[K, V]{
+			count:  1,
+			root:   &node[K, V]{datamap: bitpos(hash, 0), entries: []entry[K, V]{e}},
+			hasher: m.hasher,
+		}
+	}
+
+	var root, added = m.root.assoc(m.hasher, nil, 0, hash, key, f)
+	var count = m.count
+	if added {
+		count += 1
+	}
+
+	return Map[K, V]{
+		count:  count,
+		root:   root,
+		hasher: m.hasher,
+	}
+}
+
+// Dissoc returns a new map without key. If key isn't present in m, m itself is
+// returned.
+func (m Map[K, V]) Dissoc(key K) Map[K, V] {
+	if m.root == nil {
+		return m
+	}
+
+	var root, removed = m.root.dissoc(m.hasher, nil, m.hasher.hashOf(key), 0, key)
+	if !removed {
+		return m
+	}
+	if m.count == 1 {
+		return Map[K, V]{hasher: m.hasher}
+	}
+
+	return Map[K, V]{
+		count:  m.count - 1,
+		root:   root,
+		hasher: m.hasher,
+	}
+}
+
+// String returns a representation of a map in the same form as a Go map when
+// using the "%v" formatting verb as in the standard fmt package, with the
+// entries in an unspecified order:
+//
+//	With no entries: map[]
+//	With one entry: map[a:1]
+//	With more than one entry: map[a:1 b:2]
+func (m Map[K, V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("map[")
+	if m.root != nil {
+		var first = true
+		m.root.forEach(func(e *entry[K, V]) bool {
+			if !first {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprintf(&sb, "%v:%v", e.key, e.value)
+			first = false
+			return true
+		})
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,317 @@
+package maps_test
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestMapEmpty(t *testing.T) {
+	var m = maps.New[string, int]()
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := m.Get("a"), 0; got != want {
+		t.Fatalf("got Get(a)=%d, want Get(a)=%d", got, want)
+	}
+	if got, want := m.Dissoc("a").Len(), 0; got != want {
+		t.Fatalf("got Len()=%d after Dissoc, want Len()=%d", got, want)
+	}
+}
+
+func TestMapAssoc(t *testing.T) {
+	var testCases = []struct {
+		name string
+		n    int
+	}{
+		{"Single", 1},
+		{"Node", 20},
+		{"Trie", 1000},
+		{"DeepTrie", 100000},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var m = maps.New[int, string]()
+			for i := 0; i < tc.n; i++ {
+				m = m.Assoc(i, fmt.Sprint(i))
+			}
+			if got, want := m.Len(), tc.n; got != want {
+				t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+			}
+			for i := 0; i < tc.n; i++ {
+				if got, want := m.Get(i), fmt.Sprint(i); got != want {
+					t.Fatalf("got Get(%d)=%q, want Get(%d)=%q", i, got, i, want)
+				}
+			}
+			if got := m.Get(tc.n); got != "" {
+				t.Fatalf("got Get(%d)=%q for a missing key, want \"\"", tc.n, got)
+			}
+		})
+	}
+}
+
+func TestMapAssocReplace(t *testing.T) {
+	var m1 = maps.New[string, int]().Assoc("a", 1).Assoc("b", 2)
+	var m2 = m1.Assoc("a", 3)
+
+	if got, want := m2.Len(), 2; got != want {
+		t.Fatalf("got m2.Len()=%d, want m2.Len()=%d", got, want)
+	}
+	if got, want := m2.Get("a"), 3; got != want {
+		t.Fatalf("got m2.Get(a)=%d, want m2.Get(a)=%d", got, want)
+	}
+	if got, want := m1.Get("a"), 1; got != want {
+		t.Fatalf("got m1.Get(a)=%d, want m1.Get(a)=%d", got, want)
+	}
+}
+
+func TestMapContains(t *testing.T) {
+	var m = maps.New[string, int]().Assoc("a", 0)
+
+	if !m.Contains("a") {
+		t.Fatalf("got Contains(a)=false, want Contains(a)=true")
+	}
+	if m.Contains("b") {
+		t.Fatalf("got Contains(b)=true, want Contains(b)=false")
+	}
+	if m.Dissoc("a").Contains("a") {
+		t.Fatalf("got Contains(a)=true after Dissoc, want Contains(a)=false")
+	}
+}
+
+func TestMapUpdate(t *testing.T) {
+	var increment = func(v int, exists bool) int {
+		if !exists {
+			return 1
+		}
+		return v + 1
+	}
+
+	var m1 = maps.New[string, int]().Assoc("a", 1)
+	var m2 = m1.Update("a", increment).Update("b", increment)
+
+	if got, want := m2.Len(), 2; got != want {
+		t.Fatalf("got m2.Len()=%d, want m2.Len()=%d", got, want)
+	}
+	if got, want := m2.Get("a"), 2; got != want {
+		t.Fatalf("got m2.Get(a)=%d, want m2.Get(a)=%d", got, want)
+	}
+	if got, want := m2.Get("b"), 1; got != want {
+		t.Fatalf("got m2.Get(b)=%d, want m2.Get(b)=%d", got, want)
+	}
+	if got, want := m1.Get("a"), 1; got != want {
+		t.Fatalf("got m1.Get(a)=%d, want m1.Get(a)=%d", got, want)
+	}
+	if got, want := m1.Len(), 1; got != want {
+		t.Fatalf("got m1.Len()=%d, want m1.Len()=%d", got, want)
+	}
+}
+
+func TestMapGetOK(t *testing.T) {
+	var m = maps.New[string, int]().Assoc("a", 0)
+
+	if got, ok := m.GetOK("a"); got != 0 || !ok {
+		t.Fatalf("got GetOK(a)=%d, %t, want GetOK(a)=0, true", got, ok)
+	}
+	if got, ok := m.GetOK("b"); got != 0 || ok {
+		t.Fatalf("got GetOK(b)=%d, %t, want GetOK(b)=0, false", got, ok)
+	}
+	if got, ok := maps.New[string, int]().GetOK("a"); got != 0 || ok {
+		t.Fatalf("got GetOK(a)=%d, %t on an empty map, want GetOK(a)=0, false", got, ok)
+	}
+}
+
+func TestMapGetOr(t *testing.T) {
+	var m = maps.New[string, int]().Assoc("a", 0)
+
+	if got, want := m.GetOr("a", 5), 0; got != want {
+		t.Fatalf("got GetOr(a, 5)=%d, want GetOr(a, 5)=%d", got, want)
+	}
+	if got, want := m.GetOr("b", 5), 5; got != want {
+		t.Fatalf("got GetOr(b, 5)=%d, want GetOr(b, 5)=%d", got, want)
+	}
+}
+
+func TestMapDissoc(t *testing.T) {
+	const n = 10000
+
+	var full = maps.New[int, int]()
+	for i := 0; i < n; i++ {
+		full = full.Assoc(i, i)
+	}
+
+	var m = full
+	for i, key := range rand.New(rand.NewPCG(1, 2)).Perm(n) {
+		m = m.Dissoc(key)
+		if got, want := m.Len(), n-i-1; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		if got := m.Get(key); got != 0 {
+			t.Fatalf("got Get(%d)=%d after Dissoc, want 0", key, got)
+		}
+	}
+
+	// The original map must be unchanged by the removals.
+	if got, want := full.Len(), n; got != want {
+		t.Fatalf("got original Len()=%d, want Len()=%d", got, want)
+	}
+	for i := 0; i < n; i++ {
+		if got := full.Get(i); got != i {
+			t.Fatalf("got original Get(%d)=%d, want Get(%d)=%d", i, got, i, i)
+		}
+	}
+}
+
+func TestMapDissocMissing(t *testing.T) {
+	var m = maps.New[string, int]().Assoc("a", 1)
+	if got, want := m.Dissoc("b").String(), m.String(); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMapNewWithSeed(t *testing.T) {
+	var seed = maphash.MakeSeed()
+	var a, b = maps.NewWithSeed[int, int](seed), maps.NewWithSeed[int, int](seed)
+	for i, key := range rand.New(rand.NewPCG(7, 8)).Perm(1000) {
+		a = a.Assoc(i, i)
+		b = b.Assoc(key, key)
+	}
+
+	// Maps with the same seed and entries must iterate in the same order,
+	// regardless of the order the entries were associated in.
+	if got, want := slices.Collect(b.Keys()), slices.Collect(a.Keys()); !slices.Equal(got, want) {
+		t.Fatalf("got keys %v, want %v", got, want)
+	}
+	if got, want := a.Get(500), 500; got != want {
+		t.Fatalf("got Get(500)=%d, want Get(500)=%d", got, want)
+	}
+	if got, want := a.Dissoc(500).Len(), 999; got != want {
+		t.Fatalf("got Len()=%d after Dissoc, want Len()=%d", got, want)
+	}
+}
+
+func TestMapModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(3, 4))
+	var model = map[int]int{}
+	var m = maps.New[int, int]()
+	var versions []maps.Map[int, int]
+	var models []map[int]int
+
+	for i := 0; i < 20000; i++ {
+		var key = r.IntN(2000)
+		if r.IntN(3) == 0 {
+			delete(model, key)
+			m = m.Dissoc(key)
+		} else {
+			model[key] = i
+			m = m.Assoc(key, i)
+		}
+
+		if i%1000 == 0 {
+			var snapshot = make(map[int]int, len(model))
+			for k, v := range model {
+				snapshot[k] = v
+			}
+			versions = append(versions, m)
+			models = append(models, snapshot)
+		}
+	}
+	versions = append(versions, m)
+	models = append(models, model)
+
+	// Every version must still match the model at the time it was made.
+	for i, version := range versions {
+		if got, want := version.Len(), len(models[i]); got != want {
+			t.Fatalf("got version %d Len()=%d, want Len()=%d", i, got, want)
+		}
+		for key := 0; key < 2000; key++ {
+			if got, want := version.Get(key), models[i][key]; got != want {
+				t.Fatalf("got version %d Get(%d)=%d, want Get(%d)=%d", i, key, got, key, want)
+			}
+		}
+	}
+}
+
+func TestMapString(t *testing.T) {
+	var testCases = []struct {
+		name string
+		m    maps.Map[string, int]
+		want string
+	}{
+		{"Empty", maps.New[string, int](), "map[]"},
+		{"Single", maps.New[string, int]().Assoc("a", 1), "map[a:1]"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.m.String(); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	var m = maps.New[string, int]().Assoc("a", 1).Assoc("b", 2)
+	if got := m.String(); got != "map[a:1 b:2]" && got != "map[b:2 a:1]" {
+		t.Fatalf("got %s, want map[a:1 b:2] in any order", got)
+	}
+}
+
+func BenchmarkMapAssoc(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			var m = maps.New[int, int]()
+			for i := 0; i < n; i++ {
+				m = m.Assoc(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m = m.Assoc(i%n, i)
+			}
+		})
+	}
+}
+
+func BenchmarkMapGet(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			var m = maps.New[int, int]()
+			for i := 0; i < n; i++ {
+				m = m.Assoc(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = m.Get(i % n)
+			}
+		})
+	}
+}
+
+func TestMapGetAllocs(t *testing.T) {
+	type point struct {
+		x, y int
+		name string
+	}
+
+	var strs = maps.New[string, int]()
+	var points = maps.New[point, int]()
+	for i := 0; i < 1000; i++ {
+		strs = strs.Assoc(fmt.Sprint(i), i)
+		points = points.Assoc(point{i, -i, fmt.Sprint(i)}, i)
+	}
+
+	var key = "500"
+	if got := testing.AllocsPerRun(100, func() { strs.Get(key) }); got != 0 {
+		t.Fatalf("got %v allocations per Get with string keys, want 0", got)
+	}
+	var p = point{500, -500, "500"}
+	if got := testing.AllocsPerRun(100, func() { points.Get(p) }); got != 0 {
+		t.Fatalf("got %v allocations per Get with struct keys, want 0", got)
+	}
+}
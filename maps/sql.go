@@ -0,0 +1,39 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements the driver.Valuer interface, so a map can be stored in a
+// JSON or JSONB column. The map is encoded as by MarshalJSON and returned as a
+// string, which drivers pass to the database as text rather than as binary
+// data.
+func (m Map[K, V]) Value() (driver.Value, error) {
+	var data, err = m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface, replacing the contents of m with
+// the map encoded as JSON in src, which may be a string or a byte slice. A
+// NULL column scans to an empty map.
+func (m *Map[K, V]) Scan(src any) error {
+	switch x := src.(type) {
+	case nil:
+		*m = Map[K, V]{hasher: m.hasher}
+		return nil
+	case string:
+		return m.UnmarshalJSON([]byte(x))
+	case []byte:
+		return m.UnmarshalJSON(x)
+	default:
+		return fmt.Errorf("maps: cannot scan %T into a map", src)
+	}
+}
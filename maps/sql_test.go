@@ -0,0 +1,51 @@
+package maps_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestMapValue(t *testing.T) {
+	var got, err = maps.New[string, int]().Assoc("b", 2).Assoc("a", 1).Value()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if want := `{"a":1,"b":2}`; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMapScan(t *testing.T) {
+	var testCases = []struct {
+		name string
+		src  any
+		want string
+	}{
+		{"Null", nil, "map[]"},
+		{"String", `{"a":1}`, "map[a:1]"},
+		{"Bytes", []byte(`{"b":2}`), "map[b:2]"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var m = maps.New[string, int]().Assoc("old", 0)
+			if err := m.Scan(tc.src); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got := m.String(); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapScanErrors(t *testing.T) {
+	for _, src := range []any{1, `[1]`, []byte(`{"a":"x"}`)} {
+		var m maps.Map[string, int]
+		if err := m.Scan(src); err == nil {
+			t.Fatalf("got %v and nil error scanning %v, want an error", m, src)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import (
+	"math/bits"
+	"unsafe"
+)
+
+// Stats describes the shape of the trie backing a map, which is useful for
+// debugging and for understanding the memory used by a map.
+type Stats struct {
+	Len            int     // Number of entries in the map
+	Nodes          []int   // Number of nodes at each level, with the root at level 0
+	CollisionNodes int     // Number of collision nodes, which aren't counted in Nodes
+	Occupancy      float64 // Average fraction of the positions of a node in use
+	Bytes          int     // Estimated bytes used by the nodes of the map
+	SharedBytes    int     // Estimated bytes used by nodes shared with another map
+}
+
+// Stats returns statistics describing the trie backing m, with SharedBytes
+// counting the nodes m shares with other. An empty map may be given as other
+// when sharing isn't of interest. The estimated bytes include the nodes and
+// the slices of entries and children they hold, but not any memory referenced
+// by keys and values themselves.
+func (m Map[K, V]) Stats(other Map[K, V]) Stats {
+	var s = Stats{Len: m.count}
+	if m.root == nil {
+		return s
+	}
+
+	var used, nodes int
+	m.root.stats(other.root, 0, false, func(n *node[K, V], shift uint, shared bool) {
+		var size = n.bytes()
+		s.Bytes += size
+		if shared {
+			s.SharedBytes += size
+		}
+
+		if shift >= hashBits {
+			s.CollisionNodes += 1
+			return
+		}
+		var level = int(shift / nodeBits)
+		for len(s.Nodes) <= level {
+			s.Nodes = append(s.Nodes, 0)
+		}
+		s.Nodes[level] += 1
+		used += bits.OnesCount32(n.datamap | n.nodemap)
+		nodes += 1
+	})
+	s.Occupancy = float64(used) / float64(nodes*nodeWidth)
+
+	return s
+}
+
+// stats calls yield with n and each of its descendants, along with the level
+// of the trie they are at and whether they are shared with the trie of other.
+// Since a node can only be shared at the same position in both tries, other is
+// the node at the same position as n in the other trie, or nil if there isn't
+// one, and the two tries are walked together. Once n is other, every node
+// beneath it is shared without needing to be compared.
+func (n *node[K, V]) stats(other *node[K, V], shift uint, shared bool, yield func(*node[K, V], uint, bool)) {
+	shared = shared || n == other
+	yield(n, shift, shared)
+
+	for i, child := range n.children {
+		var counterpart *node[K, V]
+		if !shared && other != nil && shift < hashBits {
+			var bit = nthBit(n.nodemap, i)
+			if other.nodemap&bit != 0 {
+				counterpart = other.children[index(other.nodemap, bit)]
+			}
+		}
+		child.stats(counterpart, shift+nodeBits, shared, yield)
+	}
+}
+
+// nthBit returns the i-th lowest bit set in bitmap.
+func nthBit(bitmap uint32, i int) uint32 {
+	for ; i > 0; i -= 1 {
+		bitmap &= bitmap - 1
+	}
+	return bitmap & -bitmap
+}
+
+// bytes returns the estimated number of bytes used by n, not including its
+// children.
+func (n *node[K, V]) bytes() int {
+	return int(unsafe.Sizeof(*n)) +
+		cap(n.entries)*int(unsafe.Sizeof(entry[K, V]{})) +
+		cap(n.children)*int(unsafe.Sizeof(n))
+}
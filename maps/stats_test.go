@@ -0,0 +1,102 @@
+package maps_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestMapStats(t *testing.T) {
+	var identity = func(key int) uint64 { return uint64(key) }
+	var build = func(n int) maps.Map[int, int] {
+		var m = maps.NewMapWith[int, int](identity, func(a, b int) bool { return a == b })
+		for i := 0; i < n; i++ {
+			m = m.Assoc(i, i)
+		}
+		return m
+	}
+
+	var testCases = []struct {
+		name           string
+		len            int
+		nodes          []int
+		collisionNodes int
+		occupancy      float64
+	}{
+		{"Empty", 0, nil, 0, 0},
+		{"Root", 16, []int{1}, 0, 0.5},
+		{"FullRoot", 32, []int{1}, 0, 1},
+		// Keys i and i+32 share the position of the root, so each position
+		// holds a child node with two entries.
+		{"Children", 64, []int{1, 32}, 0, 3.0 / 33},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var s = build(tc.len).Stats(maps.Map[int, int]{})
+			if got, want := s.Len, tc.len; got != want {
+				t.Fatalf("got Len=%d, want Len=%d", got, want)
+			}
+			if got, want := s.Nodes, tc.nodes; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got Nodes=%v, want Nodes=%v", got, want)
+			}
+			if got, want := s.CollisionNodes, tc.collisionNodes; got != want {
+				t.Fatalf("got CollisionNodes=%d, want CollisionNodes=%d", got, want)
+			}
+			if got, want := s.Occupancy, tc.occupancy; got != want {
+				t.Fatalf("got Occupancy=%v, want Occupancy=%v", got, want)
+			}
+			if got := s.SharedBytes; got != 0 {
+				t.Fatalf("got SharedBytes=%d, want SharedBytes=0", got)
+			}
+			if tc.len > 0 && s.Bytes <= 0 {
+				t.Fatalf("got Bytes=%d, want Bytes>0", s.Bytes)
+			}
+		})
+	}
+}
+
+func TestMapStatsCollisions(t *testing.T) {
+	var m = maps.NewMapWith[int, int](func(int) uint64 { return 0 }, func(a, b int) bool { return a == b })
+	m = m.Assoc(1, 1).Assoc(2, 2)
+
+	var s = m.Stats(maps.Map[int, int]{})
+	if got, want := s.CollisionNodes, 1; got != want {
+		t.Fatalf("got CollisionNodes=%d, want CollisionNodes=%d", got, want)
+	}
+	if got, want := len(s.Nodes), 13; got != want {
+		t.Fatalf("got %d levels of nodes, want %d", got, want)
+	}
+}
+
+func TestMapStatsShared(t *testing.T) {
+	var m = maps.New[int, int]()
+	for i := 0; i < 10000; i++ {
+		m = m.Assoc(i, i)
+	}
+
+	var self = m.Stats(m)
+	if got, want := self.SharedBytes, self.Bytes; got != want {
+		t.Fatalf("got SharedBytes=%d with itself, want SharedBytes=%d", got, want)
+	}
+
+	var changed = m.Assoc(0, -1).Stats(m)
+	if changed.SharedBytes <= 0 || changed.SharedBytes >= changed.Bytes {
+		t.Fatalf("got SharedBytes=%d with Bytes=%d, want some but not all bytes shared", changed.SharedBytes, changed.Bytes)
+	}
+
+	var unrelated = maps.From(maps.ToGoMap(m)).Stats(m)
+	if got := unrelated.SharedBytes; got != 0 {
+		t.Fatalf("got SharedBytes=%d with an unrelated map, want SharedBytes=0", got)
+	}
+
+	// Only the nodes of m are walked, so comparing against a much larger map
+	// costs no more than comparing against an empty one.
+	var small = maps.New[int, int]().Assoc(1, 1)
+	var alone = testing.AllocsPerRun(10, func() { small.Stats(maps.Map[int, int]{}) })
+	if got := testing.AllocsPerRun(10, func() { small.Stats(m) }); got != alone {
+		t.Fatalf("got %v allocations comparing with a large map, want %v", got, alone)
+	}
+}
@@ -0,0 +1,133 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+// IsSubset returns true if every key of a is present in b. Values are not
+// compared. Subtries shared between a and b are skipped, and the check stops
+// at the first key of a found missing from b.
+func IsSubset[K comparable, V any, W any](a Map[K, V], b Map[K, W]) bool {
+	switch {
+	case a.count == 0:
+		return true
+	case a.count > b.count:
+		return false
+	case a.hasher != b.hasher:
+		return a.root.forEach(func(e *entry[K, V]) bool {
+			return b.Contains(e.key)
+		})
+	default:
+		return subset(a.hasher, 0, a.root, b.root)
+	}
+}
+
+// IsSuperset returns true if every key of b is present in a. Values are not
+// compared.
+func IsSuperset[K comparable, V any, W any](a Map[K, V], b Map[K, W]) bool {
+	return IsSubset(b, a)
+}
+
+// Disjoint returns true if no key is present in both a and b. Values are not
+// compared. Subtries which can't hold any of the same keys are skipped, and
+// the check stops at the first key found in both.
+func Disjoint[K comparable, V any, W any](a Map[K, V], b Map[K, W]) bool {
+	switch {
+	case a.count == 0 || b.count == 0:
+		return true
+	case a.hasher != b.hasher:
+		return a.root.forEach(func(e *entry[K, V]) bool {
+			return !b.Contains(e.key)
+		})
+	default:
+		return disjoint(a.hasher, 0, a.root, b.root)
+	}
+}
+
+// subset returns true if every key within the node a is present within the
+// node b, both at the level of the trie starting at shift.
+func subset[K comparable, V any, W any](h keyHasher[K], shift uint, a *node[K, V], b *node[K, W]) bool {
+	if sameNode(a, b) {
+		return true
+	}
+	if shift >= hashBits {
+		for i := range a.entries {
+			if b.findCollision(h, a.entries[i].key) == nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	if (a.datamap|a.nodemap)&^(b.datamap|b.nodemap) != 0 {
+		// a has an entry or child at a position where b has neither.
+		return false
+	}
+	for i := 0; i < nodeWidth; i++ {
+		var bit = uint32(1) << i
+		var x, y = slotOf(a, bit), slotOf(b, bit)
+		switch {
+		case x.child != nil && y.child != nil:
+			if !subset(h, shift+nodeBits, x.child, y.child) {
+				return false
+			}
+		case x.child != nil:
+			// A child holds at least two entries, which can't all be in the
+			// entry or empty position of b.
+			return false
+		case x.entry != nil:
+			if y.find(h, shift+nodeBits, x.entry.hash, x.entry.key) == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// disjoint returns true if no key within the node a is present within the
+// node b, both at the level of the trie starting at shift.
+func disjoint[K comparable, V any, W any](h keyHasher[K], shift uint, a *node[K, V], b *node[K, W]) bool {
+	if sameNode(a, b) {
+		return false
+	}
+	if shift >= hashBits {
+		for i := range a.entries {
+			if b.findCollision(h, a.entries[i].key) != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Only positions used by both nodes can hold the same keys.
+	var common = (a.datamap | a.nodemap) & (b.datamap | b.nodemap)
+	for i := 0; i < nodeWidth; i++ {
+		var bit = uint32(1) << i
+		if common&bit == 0 {
+			continue
+		}
+		var x, y = slotOf(a, bit), slotOf(b, bit)
+		if x.child != nil && y.child != nil {
+			if !disjoint(h, shift+nodeBits, x.child, y.child) {
+				return false
+			}
+			continue
+		}
+		var found = false
+		x.forEach(func(e *entry[K, V]) bool {
+			found = y.find(h, shift+nodeBits, e.hash, e.key) != nil
+			return !found
+		})
+		if found {
+			return false
+		}
+	}
+	return true
+}
+
+// sameNode returns true if a and b are the same node, which is only possible
+// when the maps they belong to have the same type of values.
+func sameNode[K comparable, V any, W any](a *node[K, V], b *node[K, W]) bool {
+	var other, ok = any(b).(*node[K, V])
+	return ok && a == other
+}
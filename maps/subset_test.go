@@ -0,0 +1,110 @@
+package maps_test
+
+import (
+	"hash/maphash"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+)
+
+func TestSubset(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		a        map[int]int
+		b        map[int]int
+		subset   bool
+		superset bool
+		disjoint bool
+	}{
+		{"Empty", map[int]int{}, map[int]int{}, true, true, true},
+		{"EmptyFirst", map[int]int{}, map[int]int{1: 1}, true, false, true},
+		{"EmptySecond", map[int]int{1: 1}, map[int]int{}, false, true, true},
+		{"Same", map[int]int{1: 1, 2: 2}, map[int]int{1: 1, 2: 2}, true, true, false},
+		{"DifferentValues", map[int]int{1: 1}, map[int]int{1: 2, 2: 2}, true, false, false},
+		{"Subset", map[int]int{1: 1}, map[int]int{1: 1, 2: 2}, true, false, false},
+		{"Superset", map[int]int{1: 1, 2: 2, 3: 3}, map[int]int{3: 3}, false, true, false},
+		{"Overlap", map[int]int{1: 1, 2: 2}, map[int]int{2: 2, 3: 3}, false, false, false},
+		{"Disjoint", map[int]int{1: 1, 2: 2}, map[int]int{3: 3, 4: 4}, false, false, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var a, b = fromGoMap(tc.a), fromGoMap(tc.b)
+			if got := maps.IsSubset(a, b); got != tc.subset {
+				t.Fatalf("got IsSubset=%t, want %t", got, tc.subset)
+			}
+			if got := maps.IsSuperset(a, b); got != tc.superset {
+				t.Fatalf("got IsSuperset=%t, want %t", got, tc.superset)
+			}
+			if got := maps.Disjoint(a, b); got != tc.disjoint {
+				t.Fatalf("got Disjoint=%t, want %t", got, tc.disjoint)
+			}
+		})
+	}
+}
+
+func TestSubsetLarge(t *testing.T) {
+	var all, evens, odds = maps.New[int, int](), maps.New[int, bool](), maps.New[int, bool]()
+	for i := 0; i < 10000; i++ {
+		all = all.Assoc(i, i)
+		if i%2 == 0 {
+			evens = evens.Assoc(i, true)
+		} else {
+			odds = odds.Assoc(i, true)
+		}
+	}
+
+	if !maps.IsSubset(evens, all) || !maps.IsSuperset(all, odds) {
+		t.Fatalf("got evens or odds not a subset of all, want both subsets")
+	}
+	if maps.IsSubset(all, evens) {
+		t.Fatalf("got IsSubset(all, evens)=true, want false")
+	}
+	if !maps.Disjoint(evens, odds) {
+		t.Fatalf("got Disjoint(evens, odds)=false, want true")
+	}
+	if maps.Disjoint(evens.Assoc(1, true), odds) {
+		t.Fatalf("got Disjoint=true with a shared key, want false")
+	}
+
+	// Versions of the same map share subtries, which must still be compared
+	// correctly.
+	var fewer = all.Dissoc(5000)
+	if !maps.IsSubset(fewer, all) || maps.IsSubset(all, fewer) {
+		t.Fatalf("got wrong subsets between versions of a map")
+	}
+	if maps.Disjoint(fewer, all) {
+		t.Fatalf("got Disjoint=true between versions of a map, want false")
+	}
+}
+
+func TestSubsetHasher(t *testing.T) {
+	var a = maps.NewWithSeed[string, int](maphash.MakeSeed()).Assoc("a", 1)
+	var b = maps.New[string, int]().Assoc("a", 1).Assoc("b", 2)
+
+	if !maps.IsSubset(a, b) || maps.IsSubset(b, a) {
+		t.Fatalf("got wrong subsets between maps hashing keys differently")
+	}
+	if maps.Disjoint(a, b) || !maps.Disjoint(a, b.Dissoc("a")) {
+		t.Fatalf("got wrong Disjoint between maps hashing keys differently")
+	}
+}
+
+func TestSubsetCollisions(t *testing.T) {
+	var hash = func(key int) uint64 { return uint64(key % 2) }
+	var a, b = colliding(hash), colliding(hash)
+	for i := 0; i < 10; i++ {
+		a = a.Assoc(i, i)
+		if i%4 == 0 {
+			b = b.Assoc(i, i)
+		}
+	}
+
+	if !maps.IsSubset(b, a) || maps.IsSubset(a, b) {
+		t.Fatalf("got wrong subsets between colliding maps")
+	}
+	if maps.Disjoint(a, b) || !maps.Disjoint(b, colliding(hash).Assoc(1, 1).Assoc(3, 3)) {
+		t.Fatalf("got wrong Disjoint between colliding maps")
+	}
+}
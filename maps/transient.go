@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+// transient is a map which modifies the nodes it creates in place, rather than
+// copying them for every change, to build a map from many entries quickly.
+// Once a persistent map is made from it, the nodes it created so far are
+// shared with that map and so are no longer modified.
+type transient[K comparable, V any] struct {
+	owner  *owner
+	count  int
+	root   *node[K, V]
+	hasher *hasher[K]
+}
+
+// transient returns a transient map with the same entries as m, which is
+// expected to hold about hint entries once built. The nodes of m are never
+// modified by the transient map.
+func (m Map[K, V]) transient(hint int) *transient[K, V] {
+	return &transient[K, V]{
+		owner:  &owner{hint: hint},
+		count:  m.count,
+		root:   m.root,
+		hasher: m.hasher,
+	}
+}
+
+// update associates key, which has already been hashed, to the result of
+// calling f as Map.Update does.
+func (t *transient[K, V]) update(hash uint64, key K, f func(V, bool) V) {
+	if t.root == nil {
+		var e = entry[K, V]{hash: hash, key: key, value: f(*new(V), false)}
+		var entries = append(make([]entry[K, V], 0, max(t.owner.capacity(0), 1)), e)
+		t.root = &node[K, V]{datamap: bitpos(hash, 0), entries: entries, owner: t.owner}
+		t.count = 1
+		return
+	}
+
+	var root, added = t.root.assoc(t.hasher, t.owner, 0, hash, key, f)
+	t.root = root
+	if added {
+		t.count += 1
+	}
+}
+
+// assoc associates key to value.
+func (t *transient[K, V]) assoc(key K, value V) {
+	t.update(t.hasher.hashOf(key), key, func(V, bool) V { return value })
+}
+
+// dissoc removes key, which has already been hashed, if it is present.
+func (t *transient[K, V]) dissoc(hash uint64, key K) {
+	if t.root == nil {
+		return
+	}
+
+	var root, removed = t.root.dissoc(t.hasher, t.owner, hash, 0, key)
+	if !removed {
+		return
+	}
+	t.count -= 1
+	if t.count == 0 {
+		root = nil
+	}
+	t.root = root
+}
+
+// persistent returns a persistent map with the entries of t. The transient
+// map may continue to be used afterwards, though it will copy any node it
+// shares with the returned map before modifying it.
+func (t *transient[K, V]) persistent() Map[K, V] {
+	t.owner = &owner{hint: t.owner.hint}
+	return Map[K, V]{
+		count:  t.count,
+		root:   t.root,
+		hasher: t.hasher,
+	}
+}
@@ -0,0 +1,56 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import "github.com/toddgaunt/persistent/vectors"
+
+// Zip returns a new map with each key of keys associated to the value at the
+// same index of values, similarly to Clojure's zipmap. Keys or values beyond
+// the length of the shorter vector are ignored, and a key which appears more
+// than once is associated to the value of its last appearance.
+func Zip[K comparable, V any](keys vectors.Vector[K], values vectors.Vector[V]) Map[K, V] {
+	var n = min(keys.Len(), values.Len())
+	var t = New[K, V]().transient(n)
+	for i, key := range keys.All() {
+		if i >= n {
+			break
+		}
+		t.assoc(key, values.Nth(i))
+	}
+	return t.persistent()
+}
+
+// ZipSlices is like Zip, but for keys and values in slices.
+func ZipSlices[K comparable, V any](keys []K, values []V) Map[K, V] {
+	var n = min(len(keys), len(values))
+	var t = New[K, V]().transient(n)
+	for i := range n {
+		t.assoc(keys[i], values[i])
+	}
+	return t.persistent()
+}
+
+// GroupBy returns a new map with each value of v associated to the result of
+// calling key with it, where each key is associated to a vector of every value
+// it was the result for, in the same order as v.
+func GroupBy[T any, K comparable](v vectors.Vector[T], key func(T) K) Map[K, vectors.Vector[T]] {
+	var groups = map[K]*vectors.TransientVector[T]{}
+	for _, value := range v.All() {
+		var k = key(value)
+		var group = groups[k]
+		if group == nil {
+			var tv = vectors.Vector[T]{}.Transient()
+			group = &tv
+			groups[k] = group
+		}
+		group.Conj(value)
+	}
+
+	var t = New[K, vectors.Vector[T]]().transient(len(groups))
+	for k, group := range groups {
+		t.assoc(k, group.Persistent())
+	}
+	return t.persistent()
+}
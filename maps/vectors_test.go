@@ -0,0 +1,64 @@
+package maps_test
+
+import (
+	stdmaps "maps"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestZip(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		keys   []string
+		values []int
+		want   map[string]int
+	}{
+		{"Empty", nil, nil, map[string]int{}},
+		{"Same", []string{"a", "b", "c"}, []int{1, 2, 3}, map[string]int{"a": 1, "b": 2, "c": 3}},
+		{"FewerKeys", []string{"a", "b"}, []int{1, 2, 3}, map[string]int{"a": 1, "b": 2}},
+		{"FewerValues", []string{"a", "b", "c"}, []int{1}, map[string]int{"a": 1}},
+		{"Duplicate", []string{"a", "b", "a"}, []int{1, 2, 3}, map[string]int{"a": 3, "b": 2}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var vectored = maps.Zip(vectors.New(tc.keys...), vectors.New(tc.values...))
+			if got := maps.ToGoMap(vectored); !stdmaps.Equal(got, tc.want) {
+				t.Fatalf("got Zip %v, want %v", got, tc.want)
+			}
+			if got, want := vectored.Len(), len(tc.want); got != want {
+				t.Fatalf("got Zip Len()=%d, want Len()=%d", got, want)
+			}
+
+			var sliced = maps.ZipSlices(tc.keys, tc.values)
+			if got := maps.ToGoMap(sliced); !stdmaps.Equal(got, tc.want) {
+				t.Fatalf("got ZipSlices %v, want %v", got, tc.want)
+			}
+			if got, want := sliced.Len(), len(tc.want); got != want {
+				t.Fatalf("got ZipSlices Len()=%d, want Len()=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	var v = vectors.New(1, 2, 3, 4, 5, 6, 7)
+	var got = maps.GroupBy(v, func(x int) int { return x % 3 })
+
+	var want = map[int]string{0: "[3 6]", 1: "[1 4 7]", 2: "[2 5]"}
+	if got, want := got.Len(), len(want); got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	for k, want := range want {
+		if got := got.Get(k).String(); got != want {
+			t.Fatalf("got Get(%d)=%s, want Get(%d)=%s", k, got, k, want)
+		}
+	}
+
+	if got := maps.GroupBy(vectors.New[int](), func(x int) int { return x }); got.Len() != 0 {
+		t.Fatalf("got %v, want map[]", got)
+	}
+}
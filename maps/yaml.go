@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package maps
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML implements the yaml.Marshaler interface. The map is encoded as a
+// mapping node, with its entries sorted by key so that equal maps always
+// encode the same way: scalar keys which are numbers are ordered numerically
+// and come before other scalar keys, which are ordered as strings. Keys which
+// aren't scalars, such as sequences, come last in an unspecified order.
+func (m Map[K, V]) MarshalYAML() (any, error) {
+	var node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	var pairs = make([][2]*yaml.Node, 0, m.count)
+	for key, value := range m.All() {
+		var k, v = &yaml.Node{}, &yaml.Node{}
+		if err := k.Encode(key); err != nil {
+			return nil, fmt.Errorf("maps: key %v: %w", key, err)
+		}
+		if err := v.Encode(value); err != nil {
+			return nil, fmt.Errorf("maps: value for key %v: %w", key, err)
+		}
+		pairs = append(pairs, [2]*yaml.Node{k, v})
+	}
+
+	slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+		return compareKeys(a[0], b[0])
+	})
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+	return node, nil
+}
+
+// compareKeys orders the nodes of keys for MarshalYAML.
+func compareKeys(a, b *yaml.Node) int {
+	var aScalar, bScalar = a.Kind == yaml.ScalarNode, b.Kind == yaml.ScalarNode
+	if !aScalar || !bScalar {
+		switch {
+		case aScalar:
+			return -1
+		case bScalar:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	var x, xErr = strconv.ParseFloat(a.Value, 64)
+	var y, yErr = strconv.ParseFloat(b.Value, 64)
+	switch {
+	case xErr == nil && yErr == nil:
+		return cmp.Or(cmp.Compare(x, y), cmp.Compare(a.Value, b.Value))
+	case xErr == nil:
+		return -1
+	case yErr == nil:
+		return 1
+	default:
+		return cmp.Compare(a.Value, b.Value)
+	}
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, replacing the
+// contents of m with the entries of a mapping node. A null node decodes to an
+// empty map, and a key appearing more than once results in an error. Keys are
+// hashed and compared as they are in m.
+func (m *Map[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		*m = Map[K, V]{hasher: m.hasher}
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("maps: cannot unmarshal YAML node at line %d into a map", node.Line)
+	}
+
+	var t = Map[K, V]{hasher: m.hasher}.transient(len(node.Content) / 2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var key K
+		var value V
+		if err := node.Content[i].Decode(&key); err != nil {
+			return fmt.Errorf("maps: key at line %d: %w", node.Content[i].Line, err)
+		}
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return fmt.Errorf("maps: value at line %d: %w", node.Content[i+1].Line, err)
+		}
+
+		var count = t.count
+		t.assoc(key, value)
+		if t.count == count {
+			return fmt.Errorf("maps: key %q at line %d is already defined", node.Content[i].Value, node.Content[i].Line)
+		}
+	}
+
+	*m = t.persistent()
+	return nil
+}
@@ -0,0 +1,94 @@
+package maps_test
+
+import (
+	stdmaps "maps"
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMapYAML(t *testing.T) {
+	var want = map[string]int{}
+	for i := 0; i < 1000; i++ {
+		want[string(rune('a'+i%26))+string(rune('a'+i/26))] = i
+	}
+
+	var data, err = yaml.Marshal(maps.From(want))
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	var got maps.Map[string, int]
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if got := maps.ToGoMap(got); !stdmaps.Equal(got, want) {
+		t.Fatalf("got %d entries, want %d entries", len(got), len(want))
+	}
+}
+
+func TestMapYAMLOrder(t *testing.T) {
+	var m = maps.New[any, int]().Assoc("b", 1).Assoc(10, 2).Assoc("a", 3).Assoc(9, 4).Assoc(1.5, 5)
+
+	var data, err = yaml.Marshal(m)
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(data), "1.5: 5\n9: 4\n10: 2\na: 3\nb: 1\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestMapYAMLConfig(t *testing.T) {
+	type config struct {
+		Limits maps.Map[string, int]                    `yaml:"limits"`
+		Groups maps.Map[string, vectors.Vector[string]] `yaml:"groups"`
+		Empty  maps.Map[string, int]                    `yaml:"empty"`
+	}
+
+	var data = []byte("limits: {cpu: 2, memory: 512}\ngroups:\n  admins: [alice]\n  users: [bob, carol]\nempty: ~\n")
+	var c config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if got, want := c.Limits.Get("memory"), 512; got != want {
+		t.Fatalf("got memory=%d, want memory=%d", got, want)
+	}
+	if got, want := c.Groups.Get("users").String(), "[bob carol]"; got != want {
+		t.Fatalf("got users=%s, want users=%s", got, want)
+	}
+	if got, want := c.Empty.Len(), 0; got != want {
+		t.Fatalf("got empty Len()=%d, want Len()=%d", got, want)
+	}
+
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(out), "limits:\n    cpu: 2\n    memory: 512\ngroups:\n    admins:\n        - alice\n    users:\n        - bob\n        - carol\nempty: {}\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestMapYAMLErrors(t *testing.T) {
+	var testCases = []struct {
+		name string
+		data string
+	}{
+		{"Sequence", "[1, 2]"},
+		{"Scalar", "1"},
+		{"WrongType", "a: x"},
+		{"Duplicate", "a: 1\na: 2"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var m maps.Map[string, int]
+			if err := yaml.Unmarshal([]byte(tc.data), &m); err == nil {
+				t.Fatalf("got %v and nil error, want an error", m)
+			}
+		})
+	}
+}
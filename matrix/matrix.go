@@ -0,0 +1,192 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package matrix provides a persistent two dimensional Matrix layered on a
+// persistent vector. The cells of a matrix are stored in row-major order in a
+// single flat vector, so updating a cell copies only the path to that cell
+// within the vector while every other cell is shared with the previous
+// version. This makes it cheap to keep every version of a grid around, for
+// example to undo or rewind the steps of a simulation.
+package matrix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+// Matrix is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Set'ing a cell of a Matrix, the previous version of
+// that Matrix can be used in more operations and referenced without having
+// been mutated from any operations it was used as input for. The zero value
+// of Matrix is a matrix with no rows or columns.
+type Matrix[T any] struct {
+	rows  int
+	cols  int
+	cells vectors.Vector[T] // Cells in row-major order, of length rows*cols
+}
+
+// New creates a new matrix of the given dimensions with every cell set to the
+// zero value. Zero value cells share a single leaf of the underlying vector,
+// so even a large matrix takes little memory until its cells are set.
+func New[T any](rows, cols int) Matrix[T] {
+	if rows < 0 || cols < 0 {
+		panic(fmt.Sprintf("matrix: negative dimensions %dx%d", rows, cols))
+	}
+
+	return Matrix[T]{rows: rows, cols: cols, cells: vectors.New[T]().Grow(rows * cols)}
+}
+
+// FromRows creates a new matrix with the cells of each of rows, which must
+// all be of the same length.
+func FromRows[T any](rows ...[]T) Matrix[T] {
+	if len(rows) == 0 {
+		return Matrix[T]{}
+	}
+
+	var cols = len(rows[0])
+	var b vectors.Builder[T]
+	for i, row := range rows {
+		if len(row) != cols {
+			panic(fmt.Sprintf("matrix: row %d has length %d, want %d", i, len(row), cols))
+		}
+		b.AppendSlice(row)
+	}
+
+	return Matrix[T]{rows: len(rows), cols: cols, cells: b.Vector()}
+}
+
+// Rows returns the number of rows in m.
+func (m Matrix[T]) Rows() int {
+	return m.rows
+}
+
+// Cols returns the number of columns in m.
+func (m Matrix[T]) Cols() int {
+	return m.cols
+}
+
+// index returns the index of the cell at row and col within m.cells, panicking
+// if it is out of range.
+func (m Matrix[T]) index(row, col int) int {
+	if row < 0 || row >= m.rows || col < 0 || col >= m.cols {
+		panic(fmt.Sprintf("matrix: index out of range [%d][%d] with dimensions %dx%d", row, col, m.rows, m.cols))
+	}
+	return row*m.cols + col
+}
+
+// Get returns the value of the cell at row and col.
+func (m Matrix[T]) Get(row, col int) T {
+	return m.cells.Nth(m.index(row, col))
+}
+
+// Set returns a new matrix with the cell at row and col set to value.
+func (m Matrix[T]) Set(row, col int, value T) Matrix[T] {
+	m.cells = m.cells.Assoc(m.index(row, col), value)
+	return m
+}
+
+// Update returns a new matrix with the cell at row and col set to the result
+// of calling f with its current value.
+func (m Matrix[T]) Update(row, col int, f func(T) T) Matrix[T] {
+	var i = m.index(row, col)
+	m.cells = m.cells.Assoc(i, f(m.cells.Nth(i)))
+	return m
+}
+
+// Row returns a new slice containing the values of the cells of the given row.
+func (m Matrix[T]) Row(row int) []T {
+	if row < 0 || row >= m.rows {
+		panic(fmt.Sprintf("matrix: row out of range [%d] with %d rows", row, m.rows))
+	}
+
+	var values = make([]T, m.cols)
+	for col := range values {
+		values[col] = m.cells.Nth(row*m.cols + col)
+	}
+	return values
+}
+
+// Col returns a new slice containing the values of the cells of the given
+// column.
+func (m Matrix[T]) Col(col int) []T {
+	if col < 0 || col >= m.cols {
+		panic(fmt.Sprintf("matrix: column out of range [%d] with %d columns", col, m.cols))
+	}
+
+	var values = make([]T, m.rows)
+	for row := range values {
+		values[row] = m.cells.Nth(row*m.cols + col)
+	}
+	return values
+}
+
+// SetRow returns a new matrix with the cells of the given row set to values,
+// which must have one value for each column.
+func (m Matrix[T]) SetRow(row int, values []T) Matrix[T] {
+	if row < 0 || row >= m.rows {
+		panic(fmt.Sprintf("matrix: row out of range [%d] with %d rows", row, m.rows))
+	}
+	if len(values) != m.cols {
+		panic(fmt.Sprintf("matrix: row has length %d, want %d", len(values), m.cols))
+	}
+
+	var tv = m.cells.Transient()
+	for col, value := range values {
+		tv.Assoc(row*m.cols+col, value)
+	}
+	m.cells = tv.Persistent()
+	return m
+}
+
+// SetCol returns a new matrix with the cells of the given column set to
+// values, which must have one value for each row.
+func (m Matrix[T]) SetCol(col int, values []T) Matrix[T] {
+	if col < 0 || col >= m.cols {
+		panic(fmt.Sprintf("matrix: column out of range [%d] with %d columns", col, m.cols))
+	}
+	if len(values) != m.rows {
+		panic(fmt.Sprintf("matrix: column has length %d, want %d", len(values), m.rows))
+	}
+
+	var tv = m.cells.Transient()
+	for row, value := range values {
+		tv.Assoc(row*m.cols+col, value)
+	}
+	m.cells = tv.Persistent()
+	return m
+}
+
+// Vector returns the cells of m in row-major order.
+func (m Matrix[T]) Vector() vectors.Vector[T] {
+	return m.cells
+}
+
+// String returns a representation of a matrix as a vector of its rows:
+//
+//	With no rows: []
+//	With one row: [[1 2]]
+//	With more than one row: [[1 2] [3 4]]
+func (m Matrix[T]) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for row := 0; row < m.rows; row += 1 {
+		if row > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte('[')
+		for col := 0; col < m.cols; col += 1 {
+			if col > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprint(&sb, m.cells.Nth(row*m.cols+col))
+		}
+		sb.WriteByte(']')
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,121 @@
+package matrix_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/matrix"
+)
+
+func TestMatrixNew(t *testing.T) {
+	var m = matrix.New[int](2, 3)
+	if got, want := m.Rows(), 2; got != want {
+		t.Fatalf("got Rows()=%d, want Rows()=%d", got, want)
+	}
+	if got, want := m.Cols(), 3; got != want {
+		t.Fatalf("got Cols()=%d, want Cols()=%d", got, want)
+	}
+	if got, want := m.String(), "[[0 0 0] [0 0 0]]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := (matrix.Matrix[int]{}).String(), "[]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMatrixSet(t *testing.T) {
+	var m1 = matrix.FromRows([]int{1, 2}, []int{3, 4})
+	var m2 = m1.Set(0, 1, 5).Update(1, 0, func(v int) int { return v * 10 })
+
+	if got, want := m2.String(), "[[1 5] [30 4]]"; got != want {
+		t.Fatalf("got m2 %s, want %s", got, want)
+	}
+	if got, want := m1.String(), "[[1 2] [3 4]]"; got != want {
+		t.Fatalf("got m1 %s, want %s", got, want)
+	}
+}
+
+func TestMatrixRowsAndCols(t *testing.T) {
+	var m1 = matrix.FromRows([]int{1, 2, 3}, []int{4, 5, 6})
+	var m2 = m1.SetRow(1, []int{7, 8, 9}).SetCol(0, []int{0, 0})
+
+	var testCases = []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Row", m1.Row(1), []int{4, 5, 6}},
+		{"Col", m1.Col(2), []int{3, 6}},
+		{"SetRow", m2.Row(1), []int{0, 8, 9}},
+		{"SetCol", m2.Col(0), []int{0, 0}},
+		{"Unchanged", m1.Col(0), []int{1, 4}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if !slices.Equal(tc.got, tc.want) {
+				t.Fatalf("got %v, want %v", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatrixPanics(t *testing.T) {
+	var m = matrix.New[int](2, 2)
+	var testCases = []struct {
+		name string
+		f    func()
+	}{
+		{"Get", func() { m.Get(2, 0) }},
+		{"GetCol", func() { m.Get(0, -1) }},
+		{"Set", func() { m.Set(0, 2, 1) }},
+		{"Row", func() { m.Row(2) }},
+		{"Col", func() { m.Col(2) }},
+		{"SetRow", func() { m.SetRow(0, []int{1}) }},
+		{"FromRows", func() { matrix.FromRows([]int{1}, []int{1, 2}) }},
+		{"New", func() { matrix.New[int](-1, 1) }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestMatrixModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(9, 10))
+	const rows, cols = 40, 50
+	var model [rows][cols]int
+	var m = matrix.New[int](rows, cols)
+	var versions []matrix.Matrix[int]
+	var models [][rows][cols]int
+
+	for i := 0; i < 10000; i++ {
+		var row, col = r.IntN(rows), r.IntN(cols)
+		model[row][col] = i
+		m = m.Set(row, col, i)
+		if i%1000 == 0 {
+			versions = append(versions, m)
+			models = append(models, model)
+		}
+	}
+
+	for i, version := range versions {
+		for row := 0; row < rows; row++ {
+			for col := 0; col < cols; col++ {
+				if got, want := version.Get(row, col), models[i][row][col]; got != want {
+					t.Fatalf("version %d: got Get(%d, %d)=%d, want %d", i, row, col, got, want)
+				}
+			}
+		}
+	}
+}
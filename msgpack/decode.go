@@ -0,0 +1,248 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+var errShort = errors.New("msgpack: unexpected end of data")
+
+// Unmarshal parses the single MessagePack value in data, returning it as a
+// value of one of the types described in the package documentation.
+func Unmarshal(data []byte) (any, error) {
+	var v, rest, err = Read(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("msgpack: %d bytes of data after value", len(rest))
+	}
+	return v, nil
+}
+
+// Read parses the first MessagePack value in data, returning it along with the
+// rest of data after it. This allows reading a stream of values one at a
+// time.
+func Read(data []byte) (any, []byte, error) {
+	var d = decoder{data: data}
+	var v, err = d.value()
+	if err != nil {
+		return nil, data, err
+	}
+	return v, d.data, nil
+}
+
+type decoder struct {
+	data []byte
+}
+
+// next returns the next n bytes of data.
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data) {
+		return nil, errShort
+	}
+	var b = d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (d *decoder) uint(size int) (uint64, error) {
+	var b, err = d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+// length reads a length of size bytes.
+func (d *decoder) length(size int) (int, error) {
+	var n, err = d.uint(size)
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)) {
+		// Every element takes at least one byte, so a longer length is
+		// invalid and mustn't be used to allocate anything.
+		return 0, errShort
+	}
+	return int(n), nil
+}
+
+func (d *decoder) value() (any, error) {
+	var b, err = d.next(1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := b[0]; {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c >= 0x80 && c <= 0x8f:
+		return d.mapOf(int(c & 0x0f))
+	case c >= 0x90 && c <= 0x9f:
+		return d.array(int(c & 0x0f))
+	case c >= 0xa0 && c <= 0xbf:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c := b[0]; c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		var n, err = d.length(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		var b []byte
+		if b, err = d.next(n); err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case 0xc7, 0xc8, 0xc9:
+		var n, err = d.length(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(n)
+	case 0xca:
+		var u, err = d.uint(4)
+		return math.Float32frombits(uint32(u)), err
+	case 0xcb:
+		var u, err = d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		var u, err = d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		var size = 1 << (c - 0xd0)
+		var u, err = d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign extend from the size of the integer.
+		var shift = 64 - 8*size
+		return int64(u<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		var n, err = d.length(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xdc, 0xdd:
+		var n, err = d.length(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n)
+	case 0xde, 0xdf:
+		var n, err = d.length(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapOf(n)
+	default:
+		return nil, fmt.Errorf("msgpack: invalid format byte 0x%02x", c)
+	}
+}
+
+func (d *decoder) str(n int) (any, error) {
+	var b, err = d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *decoder) array(n int) (any, error) {
+	var b vectors.Builder[any]
+	for i := 0; i < n; i += 1 {
+		var v, err = d.value()
+		if err != nil {
+			return nil, err
+		}
+		b.Append(v)
+	}
+	return b.Vector(), nil
+}
+
+func (d *decoder) mapOf(n int) (any, error) {
+	var m = maps.New[any, any]()
+	for i := 0; i < n; i += 1 {
+		var key, err = d.value()
+		if err != nil {
+			return nil, err
+		}
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return nil, fmt.Errorf("msgpack: map key of type %T is not comparable", key)
+		}
+		var value any
+		if value, err = d.value(); err != nil {
+			return nil, err
+		}
+		m = m.Assoc(key, value)
+	}
+	return m, nil
+}
+
+// ext reads the type and n bytes of data of an extension.
+func (d *decoder) ext(n int) (any, error) {
+	var typ, err = d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	if b, err = d.next(n); err != nil {
+		return nil, err
+	}
+	if int8(typ[0]) != timestampType {
+		return Ext{Type: int8(typ[0]), Data: append([]byte(nil), b...)}, nil
+	}
+
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(b)), 0).UTC(), nil
+	case 8:
+		var u = binary.BigEndian.Uint64(b)
+		return time.Unix(int64(u&(1<<34-1)), int64(u>>34)).UTC(), nil
+	case 12:
+		var nsec = binary.BigEndian.Uint32(b)
+		return time.Unix(int64(binary.BigEndian.Uint64(b[4:])), int64(nsec)).UTC(), nil
+	default:
+		return nil, fmt.Errorf("msgpack: timestamp of %d bytes", n)
+	}
+}
@@ -0,0 +1,250 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Marshal returns the MessagePack encoding of v. An error is returned if v or
+// any value within it is of a type which can't be encoded.
+func Marshal(v any) ([]byte, error) {
+	return Append(nil, v)
+}
+
+// Append appends the MessagePack encoding of v to dst, returning the extended
+// slice, similarly to the append functions of the standard strconv package.
+// An error is returned if v or any value within it is of a type which can't
+// be encoded.
+func Append(dst []byte, v any) ([]byte, error) {
+	return appendValue(dst, reflect.ValueOf(v))
+}
+
+func appendValue(data []byte, v reflect.Value) ([]byte, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return append(data, 0xc0), nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return append(data, 0xc0), nil
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return appendTime(data, x), nil
+	case Ext:
+		return appendExt(data, x.Type, x.Data), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(data, 0xc3), nil
+		}
+		return append(data, 0xc2), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendInt(data, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendUint(data, v.Uint()), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(append(data, 0xca), math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(data, 0xcb), math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return appendString(data, v.String()), nil
+	}
+
+	if has(v, "Len", "GetOK", "Assoc", "All") {
+		var n = int(v.MethodByName("Len").Call(nil)[0].Int())
+		if v.MethodByName("GetOK").Type().Out(0) == reflect.TypeFor[struct{}]() {
+			return appendEach(appendHeader(data, n, 0x90, 0xdc), v, 1)
+		}
+		return appendEach(appendHeader(data, n, 0x80, 0xde), v, 2)
+	}
+	if has(v, "Len", "All") {
+		var n = int(v.MethodByName("Len").Call(nil)[0].Int())
+		var all = v.MethodByName("All").Type().Out(0)
+		// Vectors yield their indices before each item, which are skipped.
+		var skip = all.In(0).NumIn() - 1
+		return appendEach(appendHeader(data, n, 0x90, 0xdc), v, -skip)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return appendBytes(data, v.Bytes()), nil
+		}
+		data = appendHeader(data, v.Len(), 0x90, 0xdc)
+		for i := 0; i < v.Len(); i += 1 {
+			var err error
+			if data, err = appendValue(data, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	case reflect.Map:
+		data = appendHeader(data, v.Len(), 0x80, 0xde)
+		for it := v.MapRange(); it.Next(); {
+			var err error
+			if data, err = appendValue(data, it.Key()); err != nil {
+				return nil, err
+			}
+			if data, err = appendValue(data, it.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("msgpack: cannot encode a value of type %s", v.Type())
+}
+
+// appendEach appends the values yielded by the iterator returned by calling
+// the All method of v. If n is positive, the first n values of each step are
+// appended, otherwise the first -n values are skipped and the rest appended.
+func appendEach(data []byte, v reflect.Value, n int) ([]byte, error) {
+	var err error
+	var all = v.MethodByName("All").Call(nil)[0]
+	var yield = reflect.MakeFunc(all.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		if n > 0 {
+			args = args[:n]
+		} else {
+			args = args[-n:]
+		}
+		for _, arg := range args {
+			if data, err = appendValue(data, arg); err != nil {
+				return []reflect.Value{reflect.ValueOf(false)}
+			}
+		}
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+	all.Call([]reflect.Value{yield})
+	return data, err
+}
+
+// has returns true if v has every method in names.
+func has(v reflect.Value, names ...string) bool {
+	for _, name := range names {
+		if !v.MethodByName(name).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// appendHeader appends the header of an array or map of n elements, where fix
+// is the format for up to 15 elements and wide the format for up to 65535,
+// with the next format after wide used for more.
+func appendHeader(data []byte, n int, fix, wide byte) []byte {
+	switch {
+	case n < 16:
+		return append(data, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, wide), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(data, wide+1), uint32(n))
+	}
+}
+
+func appendInt(data []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendUint(data, uint64(i))
+	case i >= -32:
+		return append(data, byte(i))
+	case i >= math.MinInt8:
+		return append(data, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(data, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(data, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(data, 0xd3), uint64(i))
+	}
+}
+
+func appendUint(data []byte, u uint64) []byte {
+	switch {
+	case u < 128:
+		return append(data, byte(u))
+	case u <= math.MaxUint8:
+		return append(data, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(data, 0xce), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(data, 0xcf), u)
+	}
+}
+
+func appendString(data []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		data = append(data, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		data = append(data, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		data = binary.BigEndian.AppendUint16(append(data, 0xda), uint16(n))
+	default:
+		data = binary.BigEndian.AppendUint32(append(data, 0xdb), uint32(n))
+	}
+	return append(data, s...)
+}
+
+func appendBytes(data []byte, b []byte) []byte {
+	switch n := len(b); {
+	case n <= math.MaxUint8:
+		data = append(data, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		data = binary.BigEndian.AppendUint16(append(data, 0xc5), uint16(n))
+	default:
+		data = binary.BigEndian.AppendUint32(append(data, 0xc6), uint32(n))
+	}
+	return append(data, b...)
+}
+
+func appendExt(data []byte, typ int8, b []byte) []byte {
+	switch n := len(b); {
+	case n == 1:
+		data = append(data, 0xd4)
+	case n == 2:
+		data = append(data, 0xd5)
+	case n == 4:
+		data = append(data, 0xd6)
+	case n == 8:
+		data = append(data, 0xd7)
+	case n == 16:
+		data = append(data, 0xd8)
+	case n <= math.MaxUint8:
+		data = append(data, 0xc7, byte(n))
+	case n <= math.MaxUint16:
+		data = binary.BigEndian.AppendUint16(append(data, 0xc8), uint16(n))
+	default:
+		data = binary.BigEndian.AppendUint32(append(data, 0xc9), uint32(n))
+	}
+	return append(append(data, byte(typ)), b...)
+}
+
+// appendTime appends t as a timestamp, using the smallest of the three forms
+// which holds it.
+func appendTime(data []byte, t time.Time) []byte {
+	var sec, nsec = t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec >= 0 && sec <= math.MaxUint32 && nsec == 0:
+		return appendExt(data, timestampType, binary.BigEndian.AppendUint32(nil, uint32(sec)))
+	case sec >= 0 && sec < 1<<34:
+		return appendExt(data, timestampType, binary.BigEndian.AppendUint64(nil, nsec<<34|uint64(sec)))
+	default:
+		var b = binary.BigEndian.AppendUint32(nil, uint32(nsec))
+		return appendExt(data, timestampType, binary.BigEndian.AppendUint64(b, uint64(sec)))
+	}
+}
@@ -0,0 +1,39 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package msgpack encodes persistent data structures in the MessagePack binary
+// format, and decodes MessagePack back into persistent data structures. The
+// elements of a collection are written directly from the collection as it is
+// iterated over, without first being copied into a slice or Go map.
+//
+// Values are encoded according to their type:
+//
+//   - nil, booleans, integers, floating point numbers and strings as their
+//     MessagePack counterparts, using the smallest form which holds them.
+//   - Byte slices as binary data, time.Time values as timestamps and Ext
+//     values as extension types.
+//   - Maps with struct{} values as arrays of their keys, other maps as maps,
+//     and vectors and lists as arrays. As with the paths package, maps are any
+//     values with Len(), GetOK(key), Assoc(key, value) and All() methods, such
+//     as those of the maps, sortedmap and btree packages. Vectors and lists
+//     are any values with Len() and All() methods, such as those of the
+//     vectors and lists packages.
+//   - Go slices, arrays and maps as arrays and maps, and pointers as the value
+//     they point to.
+//
+// Decoding produces values of dynamic types: nil, bool, int64, uint64 for
+// integers too large for an int64, float32, float64, string, []byte,
+// time.Time, Ext, vectors.Vector[any] for arrays and maps.Map[any, any] for
+// maps. Since MessagePack has no lists or sets, those are decoded as vectors.
+package msgpack
+
+// Ext is a MessagePack extension type other than a timestamp, holding the
+// type of the extension and its data.
+type Ext struct {
+	Type int8
+	Data []byte
+}
+
+// The type of the timestamp extension.
+const timestampType = -1
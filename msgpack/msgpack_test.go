@@ -0,0 +1,163 @@
+package msgpack_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/msgpack"
+	"github.com/toddgaunt/persistent/sortedmap"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestMarshal(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		value any
+		want  []byte
+	}{
+		{"Nil", nil, []byte{0xc0}},
+		{"Bool", true, []byte{0xc3}},
+		{"FixInt", 7, []byte{0x07}},
+		{"NegativeFixInt", -3, []byte{0xfd}},
+		{"Uint8", 200, []byte{0xcc, 0xc8}},
+		{"Int16", -300, []byte{0xd1, 0xfe, 0xd4}},
+		{"Uint64", uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"Float32", float32(1.5), []byte{0xca, 0x3f, 0xc0, 0x00, 0x00}},
+		{"Float64", 1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"String", "hi", []byte{0xa2, 'h', 'i'}},
+		{"Bytes", []byte{1, 2}, []byte{0xc4, 0x02, 1, 2}},
+		{"Vector", vectors.New(1, 2), []byte{0x92, 0x01, 0x02}},
+		{"List", lists.New("a"), []byte{0x91, 0xa1, 'a'}},
+		{"Map", sortedmap.New[string, int]().Assoc("a", 1).Assoc("b", 2), []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+		{"Set", maps.New[int, struct{}]().Assoc(5, struct{}{}), []byte{0x91, 0x05}},
+		{"Time", time.Unix(1, 0), []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{"Ext", msgpack.Ext{Type: 5, Data: []byte{9}}, []byte{0xd4, 0x05, 0x09}},
+		{"Nested", vectors.New[any](vectors.New[any](), nil), []byte{0x92, 0x90, 0xc0}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = msgpack.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("got % x, want % x", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var big = vectors.New[int]()
+	for i := 0; i < 70000; i++ {
+		big = big.Conj(i - 35000)
+	}
+	var m = maps.New[any, any]()
+	for i := 0; i < 100; i++ {
+		m = m.Assoc(int64(i), strings.Repeat("x", i*10))
+	}
+
+	var testCases = []struct {
+		name  string
+		value any
+		check func(t *testing.T, got any)
+	}{
+		{"BigVector", big, func(t *testing.T, got any) {
+			var v = got.(vectors.Vector[any])
+			if v.Len() != big.Len() || v.Nth(0) != int64(-35000) || v.Nth(69999) != int64(34999) {
+				t.Fatalf("got vector of length %d, want %d", v.Len(), big.Len())
+			}
+		}},
+		{"Map", m, func(t *testing.T, got any) {
+			if !maps.Equal(got.(maps.Map[any, any]), m) {
+				t.Fatalf("got %v, want %v", got, m)
+			}
+		}},
+		{"Times", vectors.New(time.Unix(1<<33, 5), time.Unix(-1, 0), time.Unix(1<<40, 1)), func(t *testing.T, got any) {
+			var v = got.(vectors.Vector[any])
+			for i, want := range []time.Time{time.Unix(1<<33, 5), time.Unix(-1, 0), time.Unix(1<<40, 1)} {
+				if !v.Nth(i).(time.Time).Equal(want) {
+					t.Fatalf("got %v, want %v", v.Nth(i), want)
+				}
+			}
+		}},
+		{"Integers", vectors.New[int64](math.MinInt64, math.MinInt32, -129, 127, math.MaxInt64), func(t *testing.T, got any) {
+			var v = got.(vectors.Vector[any])
+			for i, want := range []int64{math.MinInt64, math.MinInt32, -129, 127, math.MaxInt64} {
+				if v.Nth(i) != want {
+					t.Fatalf("got %v, want %v", v.Nth(i), want)
+				}
+			}
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var data, err = msgpack.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			var got any
+			if got, err = msgpack.Unmarshal(data); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			tc.check(t, got)
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	var data, _ = msgpack.Append(nil, 1)
+	data, _ = msgpack.Append(data, "two")
+
+	var first, rest, err = msgpack.Read(data)
+	if err != nil || first != int64(1) {
+		t.Fatalf("got %v, %v, want 1, nil", first, err)
+	}
+	second, rest, err := msgpack.Read(rest)
+	if err != nil || second != "two" || len(rest) != 0 {
+		t.Fatalf("got %v, %d bytes left, %v, want two, 0 bytes left, nil", second, len(rest), err)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var testCases = []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", nil},
+		{"Trailing", []byte{0x01, 0x02}},
+		{"ShortString", []byte{0xa3, 'a'}},
+		{"ShortArray", []byte{0x92, 0x01}},
+		{"HugeLength", []byte{0xdd, 0xff, 0xff, 0xff, 0xff}},
+		{"Invalid", []byte{0xc1}},
+		{"BytesKey", []byte{0x81, 0xc4, 0x00, 0x01}},
+		{"Timestamp", []byte{0xd5, 0xff, 0x00, 0x00}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if v, err := msgpack.Unmarshal(tc.data); err == nil {
+				t.Fatalf("got %v and nil error, want an error", v)
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalVector(b *testing.B) {
+	var v = vectors.New(make([]int, 10000)...)
+	var data []byte
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ = msgpack.Append(data[:0], v)
+	}
+}
@@ -0,0 +1,192 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package paths provides functions to get and change values nested within
+// persistent maps and vectors, similar to get-in, assoc-in and update-in found
+// in the Clojure programming language. A path is a list of keys, where each
+// key is used to look up a value in the value found by the key before it,
+// starting from a root value.
+//
+// Maps are any values with GetOK(key) and Assoc(key, value) methods, such as
+// those of the maps and sortedmap packages. Vectors are any values with Len(),
+// Nth(index), Assoc(index, value) and Conj(value) methods, such as those of
+// the vectors package. Since nested maps and vectors may be of many different
+// types, paths and values are given as the empty interface and checked against
+// the types of the maps and vectors they are used with as the path is walked.
+package paths
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GetIn returns the value found by following path from root, and true if
+// there was a value at every step of the path. If path is empty, root itself
+// is returned.
+func GetIn(root any, path ...any) (any, bool) {
+	var v = reflect.ValueOf(root)
+	for _, key := range path {
+		var found bool
+		var err error
+		if v, found, err = lookup(v, key); err != nil || !found {
+			return nil, false
+		}
+	}
+	if !v.IsValid() {
+		return nil, true
+	}
+	return v.Interface(), true
+}
+
+// AssocIn returns a new version of root with the value found by following path
+// replaced by value, along with new versions of every map and vector along
+// the path. Keys missing from a map along the path are added, associated to
+// the zero value of the map's value type if they are not the last key. A
+// vector index may be one past the end of the vector to append to it. An error
+// is returned if a key or value is of the wrong type, or a value along the
+// path is neither a map nor a vector.
+func AssocIn[T any](root T, path []any, value any) (T, error) {
+	return UpdateIn(root, path, func(any, bool) any { return value })
+}
+
+// UpdateIn is like AssocIn, except the value is replaced by the result of
+// calling f with the value found by following path and true, or nil and false
+// if the path leads to a missing value.
+func UpdateIn[T any](root T, path []any, f func(value any, exists bool) any) (T, error) {
+	var rootType = reflect.TypeFor[T]()
+	updated, err := update(reflect.ValueOf(&root).Elem(), true, path, f)
+	if err != nil {
+		return root, err
+	}
+	if updated, err = convert(updated, rootType); err != nil {
+		return root, err
+	}
+	return updated.Interface().(T), nil
+}
+
+// update returns the result of replacing the value found by following path
+// from v, which exists only if exists is true.
+func update(v reflect.Value, exists bool, path []any, f func(any, bool) any) (reflect.Value, error) {
+	if len(path) == 0 {
+		var current any
+		if exists && v.IsValid() {
+			current = v.Interface()
+		}
+		return reflect.ValueOf(f(current, exists)), nil
+	}
+
+	v = unwrap(v)
+	child, found, err := lookup(v, path[0])
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	updated, err := update(child, found, path[1:], f)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return assoc(v, path[0], updated)
+}
+
+// unwrap returns the value held by v if it is an interface.
+func unwrap(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		return v.Elem()
+	}
+	return v
+}
+
+// lookup returns the value associated with key in the map or vector v, and
+// true if there is one. If there isn't, the zero value of v's values is
+// returned.
+func lookup(v reflect.Value, key any) (reflect.Value, bool, error) {
+	v = unwrap(v)
+	if !v.IsValid() {
+		return reflect.Value{}, false, fmt.Errorf("paths: cannot look up key %v in nil", key)
+	}
+
+	if get := v.MethodByName("GetOK"); get.IsValid() {
+		k, err := convert(reflect.ValueOf(key), get.Type().In(0))
+		if err != nil {
+			return reflect.Value{}, false, err
+		}
+		var out = get.Call([]reflect.Value{k})
+		return out[0], out[1].Bool(), nil
+	}
+
+	if nth := v.MethodByName("Nth"); nth.IsValid() {
+		i, n, err := indexOf(v, key)
+		if err != nil {
+			return reflect.Value{}, false, err
+		}
+		if i == n {
+			return reflect.Zero(nth.Type().Out(0)), false, nil
+		}
+		return nth.Call([]reflect.Value{reflect.ValueOf(i)})[0], true, nil
+	}
+
+	return reflect.Value{}, false, fmt.Errorf("paths: cannot look up key %v in a value of type %s", key, v.Type())
+}
+
+// assoc returns a new version of the map or vector v with key associated to
+// value.
+func assoc(v reflect.Value, key any, value reflect.Value) (reflect.Value, error) {
+	if get := v.MethodByName("GetOK"); get.IsValid() {
+		var set = v.MethodByName("Assoc")
+		k, err := convert(reflect.ValueOf(key), set.Type().In(0))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if value, err = convert(value, set.Type().In(1)); err != nil {
+			return reflect.Value{}, err
+		}
+		return set.Call([]reflect.Value{k, value})[0], nil
+	}
+
+	i, n, err := indexOf(v, key)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	var set = v.MethodByName("Assoc")
+	if value, err = convert(value, set.Type().In(1)); err != nil {
+		return reflect.Value{}, err
+	}
+	if i == n {
+		return v.MethodByName("Conj").Call([]reflect.Value{value})[0], nil
+	}
+	return set.Call([]reflect.Value{reflect.ValueOf(i), value})[0], nil
+}
+
+// indexOf returns key as an index into the vector v, which may be one past the
+// end of v, along with the length of v.
+func indexOf(v reflect.Value, key any) (int, int, error) {
+	var length = v.MethodByName("Len")
+	if !length.IsValid() {
+		return 0, 0, fmt.Errorf("paths: cannot use a value of type %s as a vector", v.Type())
+	}
+	var i, ok = key.(int)
+	if !ok {
+		return 0, 0, fmt.Errorf("paths: vector index %v of type %T is not an int", key, key)
+	}
+	var n = int(length.Call(nil)[0].Int())
+	if i < 0 || i > n {
+		return 0, 0, fmt.Errorf("paths: index out of range [%d] with length %d", i, n)
+	}
+	return i, n, nil
+}
+
+// convert returns v as a value of type t, where a missing value is converted
+// to the zero value of t if t can be nil.
+func convert(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !v.IsValid() {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("paths: cannot use nil as a value of type %s", t)
+	}
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("paths: cannot use a value of type %s as a value of type %s", v.Type(), t)
+	}
+	return v, nil
+}
@@ -0,0 +1,140 @@
+package paths_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/paths"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+type state = maps.Map[string, vectors.Vector[maps.Map[string, int]]]
+
+func newState() state {
+	var users = vectors.New(
+		maps.New[string, int]().Assoc("age", 30),
+		maps.New[string, int]().Assoc("age", 40),
+	)
+	return maps.New[string, vectors.Vector[maps.Map[string, int]]]().Assoc("users", users)
+}
+
+func TestGetIn(t *testing.T) {
+	var root = newState()
+
+	var testCases = []struct {
+		name  string
+		path  []any
+		want  any
+		found bool
+	}{
+		{"Value", []any{"users", 1, "age"}, 40, true},
+		{"MissingKey", []any{"users", 1, "height"}, nil, false},
+		{"MissingIndex", []any{"users", 2, "age"}, nil, false},
+		{"WrongKeyType", []any{"users", "1", "age"}, nil, false},
+		{"TooDeep", []any{"users", 0, "age", "x"}, nil, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := paths.GetIn(root, tc.path...)
+			if got != tc.want || found != tc.found {
+				t.Fatalf("got %v, %t, want %v, %t", got, found, tc.want, tc.found)
+			}
+		})
+	}
+
+	if got, found := paths.GetIn(root); found != true || got.(state).Len() != 1 {
+		t.Fatalf("got %v, %t for an empty path, want the root and true", got, found)
+	}
+}
+
+func TestAssocIn(t *testing.T) {
+	var root = newState()
+
+	updated, err := paths.AssocIn(root, []any{"users", 0, "age"}, 31)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if got, _ := paths.GetIn(updated, "users", 0, "age"); got != 31 {
+		t.Fatalf("got %v, want 31", got)
+	}
+	if got, _ := paths.GetIn(root, "users", 0, "age"); got != 30 {
+		t.Fatalf("got %v in the original, want 30", got)
+	}
+
+	// Missing keys are added, and indices one past the end append.
+	updated, err = paths.AssocIn(root, []any{"admins", 0, "age"}, 50)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if got, _ := paths.GetIn(updated, "admins", 0, "age"); got != 50 {
+		t.Fatalf("got %v, want 50", got)
+	}
+	if got, want := updated.Len(), 2; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+}
+
+func TestAssocInErrors(t *testing.T) {
+	var root = newState()
+
+	var testCases = []struct {
+		name  string
+		path  []any
+		value any
+	}{
+		{"WrongValueType", []any{"users", 0, "age"}, "old"},
+		{"WrongKeyType", []any{1}, nil},
+		{"IndexOutOfRange", []any{"users", 3, "age"}, 1},
+		{"NegativeIndex", []any{"users", -1, "age"}, 1},
+		{"NotAMapOrVector", []any{"users", 0, "age", "x"}, 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := paths.AssocIn(root, tc.path, tc.value)
+			if err == nil {
+				t.Fatalf("got nil error, want error")
+			}
+			if v, _ := paths.GetIn(got, "users", 0, "age"); v != 30 || got.Len() != root.Len() {
+				t.Fatalf("got %v, want the original root", got)
+			}
+		})
+	}
+}
+
+func TestUpdateIn(t *testing.T) {
+	var increment = func(v any, exists bool) any {
+		if !exists {
+			return 1
+		}
+		return v.(int) + 1
+	}
+
+	var root any = maps.New[string, any]()
+	var err error
+	for i := 0; i < 3; i++ {
+		if root, err = paths.UpdateIn(root, []any{"count"}, increment); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+	}
+	if got, _ := paths.GetIn(root, "count"); got != 3 {
+		t.Fatalf("got %v, want 3", got)
+	}
+
+	// Values of an interface type may themselves be maps or vectors.
+	root = root.(maps.Map[string, any]).Assoc("nested", vectors.New[any](1, 2))
+	if root, err = paths.UpdateIn(root, []any{"nested", 1}, increment); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if got, _ := paths.GetIn(root, "nested", 1); got != 3 {
+		t.Fatalf("got %v, want 3", got)
+	}
+
+	// A missing value of an interface type can't be walked into.
+	if _, err = paths.UpdateIn(root, []any{"missing", "key"}, increment); err == nil {
+		t.Fatalf("got nil error walking into a missing value, want error")
+	}
+}
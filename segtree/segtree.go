@@ -0,0 +1,186 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package segtree provides a persistent segment tree, which holds a sequence
+// of values and combines any range of them with a merge function in O(log n)
+// time. The merge function may be anything associative, such as finding the
+// minimum, maximum or sum of two values. Each node of the tree holds the
+// result of merging the values below it, and updating a value copies only the
+// nodes along the path to it, so every version of a tree remains usable.
+package segtree
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// node is a node within the tree, holding the result of merging every value
+// within its subtree. Leaves hold a single value and have no children, while
+// every other node has both children.
+type node[T any] struct {
+	value T
+	left  *node[T]
+	right *node[T]
+}
+
+// build returns the root of a subtree holding values, which must not be
+// empty.
+func build[T any](merge func(a, b T) T, values []T) *node[T] {
+	if len(values) == 1 {
+		return &node[T]{value: values[0]}
+	}
+
+	var mid = len(values) / 2
+	var left, right = build(merge, values[:mid]), build(merge, values[mid:])
+	return &node[T]{value: merge(left.value, right.value), left: left, right: right}
+}
+
+// set returns a new node with the value at index replaced by value within the
+// subtree rooted at n, which holds size values.
+func (n *node[T]) set(merge func(a, b T) T, size, index int, value T) *node[T] {
+	if n.left == nil {
+		return &node[T]{value: value}
+	}
+
+	var mid = size / 2
+	var left, right = n.left, n.right
+	if index < mid {
+		left = left.set(merge, mid, index, value)
+	} else {
+		right = right.set(merge, size-mid, index-mid, value)
+	}
+	return &node[T]{value: merge(left.value, right.value), left: left, right: right}
+}
+
+// query returns the result of merging the values from index lo (inclusive) to
+// hi (exclusive) within the subtree rooted at n, which holds size values. The
+// range must not be empty.
+func (n *node[T]) query(merge func(a, b T) T, size, lo, hi int) T {
+	if lo == 0 && hi == size {
+		return n.value
+	}
+
+	var mid = size / 2
+	switch {
+	case hi <= mid:
+		return n.left.query(merge, mid, lo, hi)
+	case lo >= mid:
+		return n.right.query(merge, size-mid, lo-mid, hi-mid)
+	default:
+		return merge(
+			n.left.query(merge, mid, lo, mid),
+			n.right.query(merge, size-mid, 0, hi-mid))
+	}
+}
+
+// forEach calls yield with each value within the subtree rooted at n in
+// order. Iteration stops early if yield returns false, in which case forEach
+// also returns false.
+func (n *node[T]) forEach(yield func(T) bool) bool {
+	if n.left == nil {
+		return yield(n.value)
+	}
+	return n.left.forEach(yield) && n.right.forEach(yield)
+}
+
+// Tree is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Set'ing a value of a Tree, the previous version of that
+// Tree can be used in more operations and referenced without having been
+// mutated from any operations it was used as input for.
+//
+// A Tree must be created with New before it is used, as its zero value has no
+// way to merge values.
+type Tree[T any] struct {
+	count int
+	root  *node[T]
+	merge func(a, b T) T
+}
+
+// New creates a new tree holding values, which are combined by merge. The
+// function merge must be associative, so that merge(merge(a, b), c) is equal
+// to merge(a, merge(b, c)), but needn't be commutative as values are always
+// merged in order.
+func New[T any](merge func(a, b T) T, values ...T) Tree[T] {
+	var t = Tree[T]{count: len(values), merge: merge}
+	if len(values) > 0 {
+		t.root = build(merge, values)
+	}
+	return t
+}
+
+// Min returns the lesser of a and b, for use as the merge function of a tree
+// of range minimums.
+func Min[T cmp.Ordered](a, b T) T {
+	return min(a, b)
+}
+
+// Max returns the greater of a and b, for use as the merge function of a tree
+// of range maximums.
+func Max[T cmp.Ordered](a, b T) T {
+	return max(a, b)
+}
+
+// Len returns the number of values in t.
+func (t Tree[T]) Len() int {
+	return t.count
+}
+
+// Get returns the value at index.
+func (t Tree[T]) Get(index int) T {
+	if index < 0 || index >= t.count {
+		panic(fmt.Sprintf("segtree: index out of range [%d] with length %d", index, t.count))
+	}
+	return t.root.query(t.merge, t.count, index, index+1)
+}
+
+// Set returns a new tree with the value at index replaced by value.
+func (t Tree[T]) Set(index int, value T) Tree[T] {
+	if index < 0 || index >= t.count {
+		panic(fmt.Sprintf("segtree: index out of range [%d] with length %d", index, t.count))
+	}
+	t.root = t.root.set(t.merge, t.count, index, value)
+	return t
+}
+
+// Query returns the result of merging the values from index lo (inclusive) to
+// hi (exclusive) in order, and true if the range isn't empty. If it is empty,
+// the zero value and false are returned. The range must satisfy
+// 0 <= lo <= hi <= t.Len().
+func (t Tree[T]) Query(lo, hi int) (T, bool) {
+	if lo < 0 || hi < lo || hi > t.count {
+		panic(fmt.Sprintf("segtree: slice bounds out of range [%d:%d] with length %d", lo, hi, t.count))
+	}
+	if lo == hi {
+		var zero T
+		return zero, false
+	}
+	return t.root.query(t.merge, t.count, lo, hi), true
+}
+
+// String returns a representation of the values of a tree in the same form as
+// a vector:
+//
+//	With no values: []
+//	With one value: [1]
+//	With more than one value: [1 2 3]
+func (t Tree[T]) String() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	if t.root != nil {
+		var first = true
+		t.root.forEach(func(value T) bool {
+			if !first {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprint(&sb, value)
+			first = false
+			return true
+		})
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,140 @@
+package segtree_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/segtree"
+)
+
+func sum(a, b int) int {
+	return a + b
+}
+
+func TestTreeQuery(t *testing.T) {
+	var values = []int{3, 1, 4, 1, 5, 9, 2, 6}
+	var mins = segtree.New(segtree.Min[int], values...)
+	var maxes = segtree.New(segtree.Max[int], values...)
+	var sums = segtree.New(sum, values...)
+
+	var testCases = []struct {
+		name   string
+		tree   segtree.Tree[int]
+		lo, hi int
+		want   int
+	}{
+		{"Min", mins, 2, 6, 1},
+		{"MinAll", mins, 0, 8, 1},
+		{"MinSingle", mins, 5, 6, 9},
+		{"Max", maxes, 0, 5, 5},
+		{"MaxSuffix", maxes, 6, 8, 6},
+		{"Sum", sums, 1, 7, 22},
+		{"SumAll", sums, 0, 8, 31},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got, ok := tc.tree.Query(tc.lo, tc.hi); !ok || got != tc.want {
+				t.Fatalf("got Query(%d, %d)=%d, %t, want %d, true", tc.lo, tc.hi, got, ok, tc.want)
+			}
+		})
+	}
+
+	if got, ok := sums.Query(3, 3); ok {
+		t.Fatalf("got Query(3, 3)=%d, true, want 0, false", got)
+	}
+}
+
+func TestTreeOrder(t *testing.T) {
+	// Concatenation isn't commutative, so this checks values are merged in
+	// order.
+	var concat = func(a, b string) string { return a + b }
+	var t1 = segtree.New(concat, "a", "b", "c", "d", "e")
+	var t2 = t1.Set(2, "C")
+
+	if got, _ := t2.Query(1, 5); got != "bCde" {
+		t.Fatalf("got %q, want %q", got, "bCde")
+	}
+	if got, _ := t1.Query(0, 5); got != "abcde" {
+		t.Fatalf("got original %q, want %q", got, "abcde")
+	}
+	if got, want := t2.String(), "[a b C d e]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := t2.Get(2), "C"; got != want {
+		t.Fatalf("got Get(2)=%s, want %s", got, want)
+	}
+}
+
+func TestTreeModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(19, 20))
+	var model = make([]int, 777)
+	var tree = segtree.New(segtree.Min[int], model...)
+	var versions []segtree.Tree[int]
+	var models [][]int
+
+	for i := 0; i < 5000; i++ {
+		var index, value = r.IntN(len(model)), r.IntN(10000)
+		model[index] = value
+		tree = tree.Set(index, value)
+		if i%500 == 0 {
+			versions = append(versions, tree)
+			models = append(models, slices.Clone(model))
+		}
+	}
+
+	for i, version := range versions {
+		var want = models[i]
+		for j := 0; j < 100; j++ {
+			var lo = r.IntN(len(want))
+			var hi = lo + 1 + r.IntN(len(want)-lo)
+			if got, _ := version.Query(lo, hi); got != slices.Min(want[lo:hi]) {
+				t.Fatalf("version %d: got Query(%d, %d)=%d, want %d", i, lo, hi, got, slices.Min(want[lo:hi]))
+			}
+		}
+	}
+}
+
+func TestTreePanics(t *testing.T) {
+	var tree = segtree.New(sum, 1, 2, 3)
+	var testCases = []struct {
+		name string
+		f    func()
+	}{
+		{"Get", func() { tree.Get(3) }},
+		{"Set", func() { tree.Set(-1, 1) }},
+		{"Query", func() { tree.Query(2, 1) }},
+		{"QueryEnd", func() { tree.Query(0, 4) }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func BenchmarkTreeSet(b *testing.B) {
+	var tree = segtree.New(sum, make([]int, 1000000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree = tree.Set(i%1000000, i)
+	}
+}
+
+func BenchmarkTreeQuery(b *testing.B) {
+	var tree = segtree.New(sum, make([]int, 1000000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var lo = i % 500000
+		_, _ = tree.Query(lo, lo+500000)
+	}
+}
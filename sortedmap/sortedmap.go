@@ -0,0 +1,340 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package sortedmap provides a persistent Map datastructure which keeps its
+// keys in sorted order, similar to the sorted map found in the Clojure
+// programming language. The implementation is a weight-balanced binary tree,
+// where each node records the size of its subtree and the tree is rebalanced
+// whenever one side of a node grows too large compared to the other.
+package sortedmap
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// These constants determine how unbalanced the tree may become before it is
+// rebalanced. A node is rebalanced once one of its subtrees is more than delta
+// times the size of the other, and ratio decides whether a single or double
+// rotation is used to do so.
+const (
+	delta = 3
+	ratio = 2
+)
+
+type node[K any, V any] struct {
+	key   K
+	value V
+	size  int // Number of nodes in the subtree rooted at this node
+	left  *node[K, V]
+	right *node[K, V]
+}
+
+func size[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func newNode[K any, V any](key K, value V, left, right *node[K, V]) *node[K, V] {
+	return &node[K, V]{
+		key:   key,
+		value: value,
+		size:  size(left) + size(right) + 1,
+		left:  left,
+		right: right,
+	}
+}
+
+// balance returns a new node for key and value with the subtrees left and
+// right, rotating them if one has grown too large compared to the other. Only
+// a single entry may have been added to or removed from either subtree since
+// they were last balanced.
+func balance[K any, V any](key K, value V, left, right *node[K, V]) *node[K, V] {
+	var sl, sr = size(left), size(right)
+	switch {
+	case sl+sr <= 1:
+		return newNode(key, value, left, right)
+	case sr > delta*sl:
+		if size(right.left) < ratio*size(right.right) {
+			// Single left rotation.
+			return newNode(right.key, right.value,
+				newNode(key, value, left, right.left),
+				right.right)
+		}
+		// Double left rotation.
+		var rl = right.left
+		return newNode(rl.key, rl.value,
+			newNode(key, value, left, rl.left),
+			newNode(right.key, right.value, rl.right, right.right))
+	case sl > delta*sr:
+		if size(left.right) < ratio*size(left.left) {
+			// Single right rotation.
+			return newNode(left.key, left.value,
+				left.left,
+				newNode(key, value, left.right, right))
+		}
+		// Double right rotation.
+		var lr = left.right
+		return newNode(lr.key, lr.value,
+			newNode(left.key, left.value, left.left, lr.left),
+			newNode(key, value, lr.right, right))
+	default:
+		return newNode(key, value, left, right)
+	}
+}
+
+// assoc returns a new node with key associated to value within the subtree
+// rooted at n.
+func (n *node[K, V]) assoc(compare func(a, b K) int, key K, value V) *node[K, V] {
+	if n == nil {
+		return newNode[K, V](key, value, nil, nil)
+	}
+
+	var c = compare(key, n.key)
+	switch {
+	case c < 0:
+		return balance(n.key, n.value, n.left.assoc(compare, key, value), n.right)
+	case c > 0:
+		return balance(n.key, n.value, n.left, n.right.assoc(compare, key, value))
+	default:
+		return &node[K, V]{
+			key:   key,
+			value: value,
+			size:  n.size,
+			left:  n.left,
+			right: n.right,
+		}
+	}
+}
+
+// dissoc returns a new node without key within the subtree rooted at n, and
+// true if there was an entry for key to remove. If there wasn't, n itself is
+// returned.
+func (n *node[K, V]) dissoc(compare func(a, b K) int, key K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var c = compare(key, n.key)
+	switch {
+	case c < 0:
+		var left, removed = n.left.dissoc(compare, key)
+		if !removed {
+			return n, false
+		}
+		return balance(n.key, n.value, left, n.right), true
+	case c > 0:
+		var right, removed = n.right.dissoc(compare, key)
+		if !removed {
+			return n, false
+		}
+		return balance(n.key, n.value, n.left, right), true
+	default:
+		return glue(n.left, n.right), true
+	}
+}
+
+// glue returns a balanced node containing the entries of left and right, where
+// every key in left is less than every key in right.
+func glue[K any, V any](left, right *node[K, V]) *node[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.size > right.size:
+		var max, rest = left.popMax()
+		return balance(max.key, max.value, rest, right)
+	default:
+		var min, rest = right.popMin()
+		return balance(min.key, min.value, left, rest)
+	}
+}
+
+// popMin returns the node with the least key within the subtree rooted at n,
+// and the subtree without it.
+func (n *node[K, V]) popMin() (*node[K, V], *node[K, V]) {
+	if n.left == nil {
+		return n, n.right
+	}
+	var min, left = n.left.popMin()
+	return min, balance(n.key, n.value, left, n.right)
+}
+
+// popMax returns the node with the greatest key within the subtree rooted at
+// n, and the subtree without it.
+func (n *node[K, V]) popMax() (*node[K, V], *node[K, V]) {
+	if n.right == nil {
+		return n, n.left
+	}
+	var max, right = n.right.popMax()
+	return max, balance(n.key, n.value, n.left, right)
+}
+
+// find returns the node for key within the subtree rooted at n, or nil if
+// there isn't one.
+func (n *node[K, V]) find(compare func(a, b K) int, key K) *node[K, V] {
+	for n != nil {
+		var c = compare(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// forEach calls yield with each node within the subtree rooted at n in
+// order, starting from the first node with a key not less than lo, if there
+// is one, and stopping before the first node with a key not less than hi, if
+// there is one. Iteration stops early if yield returns false, in which case
+// forEach also returns false.
+func (n *node[K, V]) forEach(compare func(a, b K) int, lo, hi *K, yield func(*node[K, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	var afterLo = lo == nil || compare(n.key, *lo) >= 0
+	var beforeHi = hi == nil || compare(n.key, *hi) < 0
+	if afterLo && !n.left.forEach(compare, lo, hi, yield) {
+		return false
+	}
+	if afterLo && beforeHi && !yield(n) {
+		return false
+	}
+	if beforeHi {
+		return n.right.forEach(compare, lo, hi, yield)
+	}
+	return true
+}
+
+// Map is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Assoc'ing a key to a Map, the previous version of that
+// Map can be used in more operations and referenced without having been
+// mutated from any operations it was used as input for. Only the nodes along
+// the path to the key are copied by each operation, while the rest are shared
+// between both versions.
+//
+// Unlike the maps in the standard library and the maps package, a Map must be
+// created with New or NewFunc before it is used, as its zero value has no way
+// to compare keys.
+type Map[K any, V any] struct {
+	root    *node[K, V]
+	compare func(a, b K) int
+}
+
+// New creates a new empty persistent map with keys sorted in ascending order.
+func New[K cmp.Ordered, V any]() Map[K, V] {
+	return NewFunc[K, V](cmp.Compare[K])
+}
+
+// NewFunc creates a new empty persistent map with keys sorted in ascending
+// order according to compare, which returns a negative number when a < b, a
+// positive number when a > b and zero when a == b.
+func NewFunc[K any, V any](compare func(a, b K) int) Map[K, V] {
+	return Map[K, V]{compare: compare}
+}
+
+// Len returns the number of entries in m.
+func (m Map[K, V]) Len() int {
+	return size(m.root)
+}
+
+// Get returns the value associated with key, or the zero value if key isn't
+// present in m.
+func (m Map[K, V]) Get(key K) V {
+	var value, _ = m.GetOK(key)
+	return value
+}
+
+// GetOK returns the value associated with key and true, or the zero value and
+// false if key isn't present in m.
+func (m Map[K, V]) GetOK(key K) (V, bool) {
+	if n := m.root.find(m.compare, key); n != nil {
+		return n.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Contains returns true if key is present in m.
+func (m Map[K, V]) Contains(key K) bool {
+	return m.root.find(m.compare, key) != nil
+}
+
+// Assoc returns a new map with key associated to value, replacing any value
+// key was already associated to.
+func (m Map[K, V]) Assoc(key K, value V) Map[K, V] {
+	return Map[K, V]{
+		root:    m.root.assoc(m.compare, key, value),
+		compare: m.compare,
+	}
+}
+
+// Dissoc returns a new map without key. If key isn't present in m, m itself is
+// returned.
+func (m Map[K, V]) Dissoc(key K) Map[K, V] {
+	var root, removed = m.root.dissoc(m.compare, key)
+	if !removed {
+		return m
+	}
+
+	return Map[K, V]{
+		root:    root,
+		compare: m.compare,
+	}
+}
+
+// All returns an iterator over each key and value of m in ascending order of
+// key.
+func (m Map[K, V]) All() iter.Seq2[K, V] {
+	return m.iterate(nil, nil)
+}
+
+// Range returns an iterator over each key and value of m with a key from lo
+// (inclusive) to hi (exclusive), in ascending order of key.
+func (m Map[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return m.iterate(&lo, &hi)
+}
+
+func (m Map[K, V]) iterate(lo, hi *K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.forEach(m.compare, lo, hi, func(n *node[K, V]) bool {
+			return yield(n.key, n.value)
+		})
+	}
+}
+
+// String returns a representation of a map in the same form as a Go map when
+// using the "%v" formatting verb as in the standard fmt package, with the
+// entries in ascending order of key:
+//
+//	With no entries: map[]
+//	With one entry: map[a:1]
+//	With more than one entry: map[a:1 b:2]
+func (m Map[K, V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("map[")
+	var first = true
+	for key, value := range m.All() {
+		if !first {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%v:%v", key, value)
+		first = false
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
@@ -0,0 +1,200 @@
+package sortedmap_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/sortedmap"
+)
+
+func keysOf[K any, V any](m sortedmap.Map[K, V]) []K {
+	var keys []K
+	for key := range m.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestMapEmpty(t *testing.T) {
+	var m = sortedmap.New[string, int]()
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, ok := m.GetOK("a"); got != 0 || ok {
+		t.Fatalf("got GetOK(a)=%d, %t, want GetOK(a)=0, false", got, ok)
+	}
+	if got, want := m.Dissoc("a").Len(), 0; got != want {
+		t.Fatalf("got Len()=%d after Dissoc, want Len()=%d", got, want)
+	}
+	if got, want := m.String(), "map[]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMapAssoc(t *testing.T) {
+	var m1 = sortedmap.New[string, int]().Assoc("b", 2).Assoc("a", 1).Assoc("c", 3)
+	var m2 = m1.Assoc("a", 4)
+
+	if got, want := m2.String(), "map[a:4 b:2 c:3]"; got != want {
+		t.Fatalf("got m2 %s, want %s", got, want)
+	}
+	if got, want := m1.String(), "map[a:1 b:2 c:3]"; got != want {
+		t.Fatalf("got m1 %s, want %s", got, want)
+	}
+	if got, want := m2.Len(), 3; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+}
+
+func TestMapModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(5, 6))
+	var model = map[int]int{}
+	var m = sortedmap.New[int, int]()
+	var versions []sortedmap.Map[int, int]
+	var models []map[int]int
+
+	for i := 0; i < 20000; i++ {
+		var key = r.IntN(2000)
+		if r.IntN(3) == 0 {
+			delete(model, key)
+			m = m.Dissoc(key)
+		} else {
+			model[key] = i
+			m = m.Assoc(key, i)
+		}
+
+		if i%1000 == 0 {
+			var snapshot = make(map[int]int, len(model))
+			for k, v := range model {
+				snapshot[k] = v
+			}
+			versions = append(versions, m)
+			models = append(models, snapshot)
+		}
+	}
+	versions = append(versions, m)
+	models = append(models, model)
+
+	// Every version must still match the model at the time it was made, with
+	// its keys in order.
+	for i, version := range versions {
+		if got, want := version.Len(), len(models[i]); got != want {
+			t.Fatalf("got version %d Len()=%d, want Len()=%d", i, got, want)
+		}
+		for key := 0; key < 2000; key++ {
+			if got, want := version.Get(key), models[i][key]; got != want {
+				t.Fatalf("got version %d Get(%d)=%d, want Get(%d)=%d", i, key, got, key, want)
+			}
+		}
+		var want []int
+		for key := range models[i] {
+			want = append(want, key)
+		}
+		slices.Sort(want)
+		if got := keysOf(version); !slices.Equal(got, want) {
+			t.Fatalf("got version %d keys %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestMapRange(t *testing.T) {
+	var m = sortedmap.New[int, string]()
+	for i := 0; i < 100; i += 2 {
+		m = m.Assoc(i, fmt.Sprint(i))
+	}
+
+	var testCases = []struct {
+		name   string
+		lo, hi int
+		want   []int
+	}{
+		{"Empty", 10, 10, nil},
+		{"Reversed", 20, 10, nil},
+		{"Inclusive", 10, 15, []int{10, 12, 14}},
+		{"Exclusive", 11, 16, []int{12, 14}},
+		{"Before", -10, 3, []int{0, 2}},
+		{"After", 95, 200, []int{96, 98}},
+		{"Outside", 200, 300, nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []int
+			for key, value := range m.Range(tc.lo, tc.hi) {
+				if value != fmt.Sprint(key) {
+					t.Fatalf("got value %q for key %d, want %q", value, key, fmt.Sprint(key))
+				}
+				got = append(got, key)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapRangeBreak(t *testing.T) {
+	var m = sortedmap.New[int, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Assoc(i, i)
+	}
+
+	var got []int
+	for key := range m.Range(10, 90) {
+		if key == 13 {
+			break
+		}
+		got = append(got, key)
+	}
+	if want := []int{10, 11, 12}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNewFunc(t *testing.T) {
+	var m = sortedmap.NewFunc[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(b), strings.ToLower(a))
+	})
+	m = m.Assoc("a", 1).Assoc("C", 3).Assoc("b", 2).Assoc("A", 4)
+
+	if got, want := m.String(), "map[C:3 b:2 A:4]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := m.Get("c"), 3; got != want {
+		t.Fatalf("got Get(c)=%d, want Get(c)=%d", got, want)
+	}
+}
+
+func BenchmarkMapAssoc(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			var m = sortedmap.New[int, int]()
+			for i := 0; i < n; i++ {
+				m = m.Assoc(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m = m.Assoc(i%n, i)
+			}
+		})
+	}
+}
+
+func BenchmarkMapGet(b *testing.B) {
+	for _, n := range []int{100, 10000, 1000000} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			var m = sortedmap.New[int, int]()
+			for i := 0; i < n; i++ {
+				m = m.Assoc(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = m.Get(i % n)
+			}
+		})
+	}
+}
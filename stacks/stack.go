@@ -0,0 +1,91 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package stacks provides a persistent last-in first-out Stack. It is a thin
+// wrapper around a persistent list, where the head of the list is the top of
+// the stack.
+package stacks
+
+import (
+	"iter"
+
+	"github.com/toddgaunt/persistent/lists"
+)
+
+// Stack is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when pushing an item onto a Stack, the previous version of
+// that Stack can be used in more operations and referenced without having
+// been mutated from any operations it was used as input for. The zero value
+// of Stack is an empty stack ready to use.
+type Stack[T any] struct {
+	list lists.List[T]
+}
+
+// New creates a new persistent stack by pushing each of items in order, so the
+// last of items is at the top of the stack.
+func New[T any](items ...T) Stack[T] {
+	var s Stack[T]
+	for _, item := range items {
+		s = s.Push(item)
+	}
+	return s
+}
+
+// FromList creates a new persistent stack from l, with the head of the list at
+// the top of the stack. The stack shares l rather than copying it.
+func FromList[T any](l lists.List[T]) Stack[T] {
+	return Stack[T]{list: l}
+}
+
+// List returns the items of s as a list, with the top of the stack at the
+// head of the list. The list shares s rather than copying it.
+func (s Stack[T]) List() lists.List[T] {
+	return s.list
+}
+
+// Len returns the number of items in s.
+func (s Stack[T]) Len() int {
+	return s.list.Len()
+}
+
+// IsEmpty returns true if s has no items.
+func (s Stack[T]) IsEmpty() bool {
+	return s.list.Len() == 0
+}
+
+// Push returns a new stack with item on top of s.
+func (s Stack[T]) Push(item T) Stack[T] {
+	return Stack[T]{list: s.list.Conj(item)}
+}
+
+// Pop returns a new stack without the item at the top of s, along with that
+// item and true. If s is empty, s itself is returned along with the zero
+// value and false.
+func (s Stack[T]) Pop() (Stack[T], T, bool) {
+	var rest, item, ok = s.list.Pop()
+	return Stack[T]{list: rest}, item, ok
+}
+
+// Peek returns the item at the top of s and true, or the zero value and false
+// if s is empty.
+func (s Stack[T]) Peek() (T, bool) {
+	return s.list.Peek()
+}
+
+// All returns an iterator over each item of s from the top of the stack to
+// the bottom.
+func (s Stack[T]) All() iter.Seq[T] {
+	return s.list.All()
+}
+
+// String returns a representation of a stack from the top of the stack to
+// the bottom, in the same form as a list:
+//
+//	With no items: ()
+//	With one item: (1)
+//	With more than one item: (3 2 1)
+func (s Stack[T]) String() string {
+	return s.list.String()
+}
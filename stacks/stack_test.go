@@ -0,0 +1,60 @@
+package stacks_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/stacks"
+)
+
+func TestStackEmpty(t *testing.T) {
+	var s stacks.Stack[int]
+	if !s.IsEmpty() || s.Len() != 0 {
+		t.Fatalf("got Len()=%d, want an empty stack", s.Len())
+	}
+	if got, ok := s.Peek(); got != 0 || ok {
+		t.Fatalf("got Peek()=%d, %t, want 0, false", got, ok)
+	}
+	if rest, got, ok := s.Pop(); got != 0 || ok || rest.Len() != 0 {
+		t.Fatalf("got Pop()=%v, %d, %t, want (), 0, false", rest, got, ok)
+	}
+	if got, want := s.String(), "()"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestStackPushPop(t *testing.T) {
+	var s = stacks.New(1, 2).Push(3)
+	if got, want := s.String(), "(3 2 1)"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, ok := s.Peek(); got != 3 || !ok {
+		t.Fatalf("got Peek()=%d, %t, want 3, true", got, ok)
+	}
+
+	var rest, got, ok = s.Pop()
+	if got != 3 || !ok {
+		t.Fatalf("got Pop()=%d, %t, want 3, true", got, ok)
+	}
+	if got, want := rest.String(), "(2 1)"; got != want {
+		t.Fatalf("got rest %s, want %s", got, want)
+	}
+	if got, want := s.Len(), 3; got != want {
+		t.Fatalf("got original Len()=%d after Pop, want Len()=%d", got, want)
+	}
+	if got, want := slices.Collect(s.All()), []int{3, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestStackList(t *testing.T) {
+	var l = lists.New(1, 2, 3)
+	var s = stacks.FromList(l)
+	if got, _ := s.Peek(); got != 1 {
+		t.Fatalf("got Peek()=%d, want 1", got)
+	}
+	if got, want := s.Push(0).List().String(), "(0 1 2 3)"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
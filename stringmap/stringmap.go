@@ -0,0 +1,378 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package stringmap provides a persistent Map keyed by strings which supports
+// queries by prefix. The implementation is a radix trie, where each edge of
+// the trie is labelled with a string rather than a single byte, so a chain of
+// nodes with only one child each is collapsed into a single node. Keys held
+// as byte slices can be used with the methods ending in Bytes, which look them
+// up without copying them into strings.
+package stringmap
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// node is a node within the trie. The key of a node is the concatenation of
+// the labels of every node from the root down to it, and the node holds a
+// value for that key only if hasValue is true. Children are kept in order of
+// the first byte of their labels, which are never empty and never share a
+// first byte.
+//
+// Other than the root, every node holds either a value or at least two
+// children. This keeps the trie canonical, so the same set of keys always
+// results in the same layout.
+type node[V any] struct {
+	label    string
+	hasValue bool
+	value    V
+	children []*node[V]
+}
+
+// child returns the index of the child of n with a label starting with b, and
+// true if there is one. If there isn't, the index the child would be inserted
+// at is returned.
+func (n *node[V]) child(b byte) (int, bool) {
+	return slices.BinarySearchFunc(n.children, b, func(c *node[V], b byte) int {
+		return int(c.label[0]) - int(b)
+	})
+}
+
+// withChildren returns a copy of n with children.
+func (n *node[V]) withChildren(children []*node[V]) *node[V] {
+	return &node[V]{label: n.label, hasValue: n.hasValue, value: n.value, children: children}
+}
+
+// compact returns n after something has been removed from beneath it: nil if
+// it is left with neither a value nor any children, or its only child
+// relabelled to include n's label if it has no value.
+func (n *node[V]) compact() *node[V] {
+	if n.hasValue {
+		return n
+	}
+	switch len(n.children) {
+	case 0:
+		return nil
+	case 1:
+		var c = n.children[0]
+		return &node[V]{label: n.label + c.label, hasValue: c.hasValue, value: c.value, children: c.children}
+	default:
+		return n
+	}
+}
+
+// bytestring is the type of keys accepted by lookups, so that keys held as
+// byte slices don't need to be copied into strings.
+type bytestring interface {
+	~string | ~[]byte
+}
+
+// hasPrefix returns true if s begins with prefix.
+func hasPrefix[S bytestring](s S, prefix string) bool {
+	return len(s) >= len(prefix) && string(s[:len(prefix)]) == prefix
+}
+
+func commonPrefix(a, b string) int {
+	var i = 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i += 1
+	}
+	return i
+}
+
+// assoc returns a new node with the key formed by n's key followed by rest
+// associated to value, and true if the key wasn't already present.
+func (n *node[V]) assoc(rest string, value V) (*node[V], bool) {
+	if rest == "" {
+		return &node[V]{label: n.label, hasValue: true, value: value, children: n.children}, !n.hasValue
+	}
+
+	var i, found = n.child(rest[0])
+	if !found {
+		var leaf = &node[V]{label: rest, hasValue: true, value: value}
+		return n.withChildren(slices.Insert(slices.Clone(n.children), i, leaf)), true
+	}
+
+	var c = n.children[i]
+	var common = commonPrefix(c.label, rest)
+	var updated *node[V]
+	var added = true
+	switch {
+	case common == len(c.label):
+		updated, added = c.assoc(rest[common:], value)
+	default:
+		// The key leaves the child's label partway through, so the label is
+		// split at that point by a new node.
+		var tail = &node[V]{label: c.label[common:], hasValue: c.hasValue, value: c.value, children: c.children}
+		updated = &node[V]{label: c.label[:common], children: []*node[V]{tail}}
+		updated, _ = updated.assoc(rest[common:], value)
+	}
+
+	var children = slices.Clone(n.children)
+	children[i] = updated
+	return n.withChildren(children), added
+}
+
+// dissoc returns a new node without the key formed by n's key followed by
+// rest, and true if there was an entry for the key to remove. If there wasn't,
+// n itself is returned. The returned node is not compacted.
+func (n *node[V]) dissoc(rest string) (*node[V], bool) {
+	if rest == "" {
+		if !n.hasValue {
+			return n, false
+		}
+		return &node[V]{label: n.label, children: n.children}, true
+	}
+
+	var i, found = n.child(rest[0])
+	if !found || !strings.HasPrefix(rest, n.children[i].label) {
+		return n, false
+	}
+
+	var c = n.children[i]
+	var updated, removed = c.dissoc(rest[len(c.label):])
+	if !removed {
+		return n, false
+	}
+
+	var children []*node[V]
+	if updated = updated.compact(); updated == nil {
+		children = slices.Delete(slices.Clone(n.children), i, i+1)
+	} else {
+		children = slices.Clone(n.children)
+		children[i] = updated
+	}
+	return n.withChildren(children), true
+}
+
+// find returns the node for key within n, or nil if there isn't one.
+func find[V any, S bytestring](n *node[V], key S) *node[V] {
+	for n != nil {
+		if len(key) == 0 {
+			if n.hasValue {
+				return n
+			}
+			return nil
+		}
+		var i, found = n.child(key[0])
+		if !found || !hasPrefix(key, n.children[i].label) {
+			return nil
+		}
+		key = key[len(n.children[i].label):]
+		n = n.children[i]
+	}
+	return nil
+}
+
+// forEach calls yield with the key and value of each node within n in order,
+// where key is the key of n. Iteration stops early if yield returns false, in
+// which case forEach also returns false.
+func (n *node[V]) forEach(key string, yield func(string, V) bool) bool {
+	if n.hasValue && !yield(key, n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.forEach(key+c.label, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Map is a persistent data structure that can be treated as a value
+// (similarly to an int) after any of the operations provided by this package.
+// This means even when Assoc'ing a key to a Map, the previous version of that
+// Map can be used in more operations and referenced without having been
+// mutated from any operations it was used as input for. Only the nodes along
+// the path to the key are copied by each operation, while the rest are shared
+// between both versions. Keys are iterated over in lexicographic order of
+// their bytes. The zero value of Map is an empty map ready to use.
+type Map[V any] struct {
+	count int
+	root  *node[V] // Root of the trie with an empty label, nil when empty
+}
+
+// New creates a new empty persistent map.
+func New[V any]() Map[V] {
+	return Map[V]{}
+}
+
+// Len returns the number of entries in m.
+func (m Map[V]) Len() int {
+	return m.count
+}
+
+// Get returns the value associated with key, or the zero value if key isn't
+// present in m.
+func (m Map[V]) Get(key string) V {
+	var value, _ = m.GetOK(key)
+	return value
+}
+
+// GetOK returns the value associated with key and true, or the zero value and
+// false if key isn't present in m.
+func (m Map[V]) GetOK(key string) (V, bool) {
+	return getOK(m.root, key)
+}
+
+// GetBytes is like Get, but with a key held as a byte slice.
+func (m Map[V]) GetBytes(key []byte) V {
+	var value, _ = getOK(m.root, key)
+	return value
+}
+
+// GetBytesOK is like GetOK, but with a key held as a byte slice.
+func (m Map[V]) GetBytesOK(key []byte) (V, bool) {
+	return getOK(m.root, key)
+}
+
+func getOK[V any, S bytestring](root *node[V], key S) (V, bool) {
+	if n := find(root, key); n != nil {
+		return n.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Assoc returns a new map with key associated to value, replacing any value
+// key was already associated to.
+func (m Map[V]) Assoc(key string, value V) Map[V] {
+	var root = m.root
+	if root == nil {
+		root = &node[V]{}
+	}
+
+	var updated, added = root.assoc(key, value)
+	var count = m.count
+	if added {
+		count += 1
+	}
+
+	return Map[V]{count: count, root: updated}
+}
+
+// AssocBytes is like Assoc, but with a key held as a byte slice. The key is
+// copied, so key may be modified afterwards.
+func (m Map[V]) AssocBytes(key []byte, value V) Map[V] {
+	return m.Assoc(string(key), value)
+}
+
+// Dissoc returns a new map without key. If key isn't present in m, m itself is
+// returned.
+func (m Map[V]) Dissoc(key string) Map[V] {
+	if m.root == nil {
+		return m
+	}
+
+	var root, removed = m.root.dissoc(key)
+	if !removed {
+		return m
+	}
+	if m.count == 1 {
+		return Map[V]{}
+	}
+
+	return Map[V]{count: m.count - 1, root: root}
+}
+
+// DissocBytes is like Dissoc, but with a key held as a byte slice.
+func (m Map[V]) DissocBytes(key []byte) Map[V] {
+	return m.Dissoc(string(key))
+}
+
+// LongestPrefix returns the longest key in m which is a prefix of s, along with
+// the value associated with it and true. If no key in m is a prefix of s, the
+// empty string, the zero value and false are returned.
+func (m Map[V]) LongestPrefix(s string) (string, V, bool) {
+	return longestPrefix(m.root, s)
+}
+
+// LongestPrefixBytes is like LongestPrefix, but with s held as a byte slice.
+// The key returned is a slice of s.
+func (m Map[V]) LongestPrefixBytes(s []byte) ([]byte, V, bool) {
+	return longestPrefix(m.root, s)
+}
+
+func longestPrefix[V any, S bytestring](root *node[V], s S) (S, V, bool) {
+	var best *node[V]
+	var length int
+
+	var consumed = 0
+	for n := root; n != nil; {
+		if n.hasValue {
+			best, length = n, consumed
+		}
+		if consumed == len(s) {
+			break
+		}
+		var i, found = n.child(s[consumed])
+		if !found || !hasPrefix(s[consumed:], n.children[i].label) {
+			break
+		}
+		consumed += len(n.children[i].label)
+		n = n.children[i]
+	}
+
+	if best == nil {
+		var zero V
+		return s[:0], zero, false
+	}
+	return s[:length], best.value, true
+}
+
+// All returns an iterator over each key and value of m, in lexicographic order
+// of the keys.
+func (m Map[V]) All() iter.Seq2[string, V] {
+	return m.WithPrefix("")
+}
+
+// WithPrefix returns an iterator over each key and value of m with a key
+// starting with prefix, in lexicographic order of the keys.
+func (m Map[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		var n, key = m.root, ""
+		for n != nil && len(key) < len(prefix) {
+			var rest = prefix[len(key):]
+			var i, found = n.child(rest[0])
+			if !found {
+				return
+			}
+			var c = n.children[i]
+			if !strings.HasPrefix(rest, c.label) && !strings.HasPrefix(c.label, rest) {
+				return
+			}
+			n, key = c, key+c.label
+		}
+		if n != nil {
+			n.forEach(key, yield)
+		}
+	}
+}
+
+// String returns a representation of a map in the same form as a Go map when
+// using the "%v" formatting verb as in the standard fmt package, with the
+// entries in lexicographic order of the keys:
+//
+//	With no entries: map[]
+//	With one entry: map[a:1]
+//	With more than one entry: map[a:1 b:2]
+func (m Map[V]) String() string {
+	var sb strings.Builder
+	sb.WriteString("map[")
+	var first = true
+	for key, value := range m.All() {
+		if !first {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%s:%v", key, value)
+		first = false
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
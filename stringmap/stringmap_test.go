@@ -0,0 +1,245 @@
+package stringmap_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/stringmap"
+)
+
+func keysOf[V any](m stringmap.Map[V]) []string {
+	var keys []string
+	for key := range m.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestMapEmpty(t *testing.T) {
+	var m stringmap.Map[int]
+	if got, want := m.Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, ok := m.GetOK(""); got != 0 || ok {
+		t.Fatalf("got GetOK()=%d, %t, want GetOK()=0, false", got, ok)
+	}
+	if got, want := m.Dissoc("a").Len(), 0; got != want {
+		t.Fatalf("got Len()=%d after Dissoc, want Len()=%d", got, want)
+	}
+	if _, _, ok := m.LongestPrefix("abc"); ok {
+		t.Fatalf("got LongestPrefix match in an empty map")
+	}
+	if got, want := m.String(), "map[]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMapAssoc(t *testing.T) {
+	var m1 = stringmap.New[int]().Assoc("team", 1).Assoc("tea", 2).Assoc("ten", 3).Assoc("", 0)
+	var m2 = m1.Assoc("te", 4).Assoc("tea", 5)
+
+	if got, want := m2.String(), "map[:0 te:4 tea:5 team:1 ten:3]"; got != want {
+		t.Fatalf("got m2 %s, want %s", got, want)
+	}
+	if got, want := m1.String(), "map[:0 tea:2 team:1 ten:3]"; got != want {
+		t.Fatalf("got m1 %s, want %s", got, want)
+	}
+	if got, want := m2.Len(), 5; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, ok := m2.GetOK("t"); ok {
+		t.Fatalf("got GetOK(t)=%d, true, want GetOK(t)=0, false", got)
+	}
+	if got, want := m2.Get("tea"), 5; got != want {
+		t.Fatalf("got Get(tea)=%d, want Get(tea)=%d", got, want)
+	}
+	if got := m2.Get("t"); got != 0 {
+		t.Fatalf("got Get(t)=%d, want the zero value", got)
+	}
+}
+
+func TestMapBytes(t *testing.T) {
+	var key = []byte("team")
+	var m = stringmap.New[int]().AssocBytes(key, 1).Assoc("tea", 2)
+	key[0] = 'b'
+
+	if got, ok := m.GetBytesOK([]byte("team")); got != 1 || !ok {
+		t.Fatalf("got GetBytesOK(team)=%d, %t, want 1, true", got, ok)
+	}
+	if got, ok := m.GetBytesOK(key); ok {
+		t.Fatalf("got GetBytesOK(beam)=%d, true, want 0, false", got)
+	}
+	if got, want := m.GetBytes([]byte("tea")), 2; got != want {
+		t.Fatalf("got GetBytes(tea)=%d, want %d", got, want)
+	}
+	var prefix, value, ok = m.LongestPrefixBytes([]byte("teapot"))
+	if string(prefix) != "tea" || value != 2 || !ok {
+		t.Fatalf("got LongestPrefixBytes(teapot)=%q, %d, %t, want tea, 2, true", prefix, value, ok)
+	}
+	if got, want := m.DissocBytes([]byte("tea")).String(), "map[team:1]"; got != want {
+		t.Fatalf("got %s after DissocBytes(tea), want %s", got, want)
+	}
+
+	var lookup = []byte("team")
+	var allocs = testing.AllocsPerRun(100, func() {
+		m.GetBytes(lookup)
+		m.LongestPrefixBytes(lookup)
+	})
+	if allocs != 0 {
+		t.Fatalf("got %v allocations per lookup, want 0", allocs)
+	}
+}
+
+func TestMapLongestPrefix(t *testing.T) {
+	var m = stringmap.New[string]().
+		Assoc("10.", "a").
+		Assoc("10.1.", "b").
+		Assoc("10.1.2.", "c").
+		Assoc("192.168.", "d")
+
+	var testCases = []struct {
+		name   string
+		s      string
+		prefix string
+		value  string
+		ok     bool
+	}{
+		{"Exact", "10.1.", "10.1.", "b", true},
+		{"Longest", "10.1.2.3", "10.1.2.", "c", true},
+		{"Shorter", "10.1.3.4", "10.1.", "b", true},
+		{"Partial edge", "10.2", "10.", "a", true},
+		{"Split edge", "192.1", "", "", false},
+		{"None", "172.16.0.1", "", "", false},
+		{"Empty", "", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var prefix, value, ok = m.LongestPrefix(tc.s)
+			if prefix != tc.prefix || value != tc.value || ok != tc.ok {
+				t.Fatalf("got %q, %q, %t, want %q, %q, %t", prefix, value, ok, tc.prefix, tc.value, tc.ok)
+			}
+		})
+	}
+}
+
+func TestMapWithPrefix(t *testing.T) {
+	var m stringmap.Map[int]
+	for i, key := range []string{"a", "ab", "abc", "abd", "b", "ba", "bcd"} {
+		m = m.Assoc(key, i)
+	}
+
+	var testCases = []struct {
+		name   string
+		prefix string
+		want   []string
+	}{
+		{"All", "", []string{"a", "ab", "abc", "abd", "b", "ba", "bcd"}},
+		{"Key", "ab", []string{"ab", "abc", "abd"}},
+		{"Within edge", "bc", []string{"bcd"}},
+		{"Leaf", "abd", []string{"abd"}},
+		{"Missing", "bd", nil},
+		{"Longer", "abcd", nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for key := range m.WithPrefix(tc.prefix) {
+				got = append(got, key)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapModel(t *testing.T) {
+	var r = rand.New(rand.NewPCG(7, 8))
+	var model = map[string]int{}
+	var m stringmap.Map[int]
+	var versions []stringmap.Map[int]
+	var models []map[string]int
+
+	var randomKey = func() string {
+		var sb strings.Builder
+		for n := r.IntN(6); n > 0; n-- {
+			sb.WriteByte("abc"[r.IntN(3)])
+		}
+		return sb.String()
+	}
+
+	for i := 0; i < 20000; i++ {
+		var key = randomKey()
+		if r.IntN(3) == 0 {
+			delete(model, key)
+			m = m.Dissoc(key)
+		} else {
+			model[key] = i
+			m = m.Assoc(key, i)
+		}
+
+		if i%1000 == 0 {
+			var snapshot = make(map[string]int, len(model))
+			for k, v := range model {
+				snapshot[k] = v
+			}
+			versions = append(versions, m)
+			models = append(models, snapshot)
+		}
+	}
+	versions = append(versions, m)
+	models = append(models, model)
+
+	for i, version := range versions {
+		var want = models[i]
+		if got := version.Len(); got != len(want) {
+			t.Fatalf("version %d: got Len()=%d, want Len()=%d", i, got, len(want))
+		}
+		for k, v := range want {
+			if got, ok := version.GetOK(k); !ok || got != v {
+				t.Fatalf("version %d: got GetOK(%q)=%d, %t, want GetOK(%q)=%d, true", i, k, got, ok, k, v)
+			}
+		}
+		var keys = keysOf(version)
+		if !slices.IsSorted(keys) || len(keys) != len(want) {
+			t.Fatalf("version %d: got keys %v, want %d sorted keys", i, keys, len(want))
+		}
+	}
+}
+
+func TestMapDissocAll(t *testing.T) {
+	var keys = []string{"", "a", "ab", "abc", "b", "bc", "bcd", "bce"}
+	var m stringmap.Map[int]
+	for i, key := range keys {
+		m = m.Assoc(key, i)
+	}
+
+	var empty = m
+	for _, key := range keys {
+		empty = empty.Dissoc(key)
+	}
+	if got, want := empty.Len(), 0; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := empty.String(), "map[]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := keysOf(m), keys; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func BenchmarkMapAssoc(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var m stringmap.Map[int]
+		for j := 0; j < 1000; j++ {
+			m = m.Assoc(strings.Repeat("k", j%10)+string(rune('a'+j%26)), j)
+		}
+	}
+}
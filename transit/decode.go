@@ -0,0 +1,347 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/toddgaunt/persistent/edn"
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+// Unmarshal parses the single Transit JSON value in data, returning it as a
+// value of one of the types described in the package documentation.
+func Unmarshal(data []byte) (any, error) {
+	var d = decoder{json: json.NewDecoder(bytes.NewReader(data))}
+	d.json.UseNumber()
+
+	var v, err = d.value(false)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.json.Token(); err != io.EOF {
+		return nil, fmt.Errorf("transit: unexpected data after value")
+	}
+	return v, nil
+}
+
+type decoder struct {
+	json  *json.Decoder
+	cache []string
+}
+
+func (d *decoder) token() (json.Token, error) {
+	var tok, err = d.json.Token()
+	if err == io.EOF {
+		return nil, fmt.Errorf("transit: unexpected end of input")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("transit: %w", err)
+	}
+	return tok, nil
+}
+
+// str returns s, which is a string as read, with any reference to the cache
+// replaced by the string it refers to.
+func (d *decoder) str(s string, mapKey bool) (string, error) {
+	if i, ok := cacheIndex(s); ok {
+		if i >= len(d.cache) {
+			return "", fmt.Errorf("transit: cache reference %s to a missing entry", s)
+		}
+		return d.cache[i], nil
+	}
+	if cacheable(s, mapKey) {
+		if len(d.cache) == cacheSize {
+			d.cache = d.cache[:0]
+		}
+		d.cache = append(d.cache, s)
+	}
+	return s, nil
+}
+
+func (d *decoder) value(mapKey bool) (any, error) {
+	var tok, err = d.token()
+	if err != nil {
+		return nil, err
+	}
+	return d.valueOf(tok, mapKey)
+}
+
+func (d *decoder) valueOf(tok json.Token, mapKey bool) (any, error) {
+	switch tok := tok.(type) {
+	case nil, bool:
+		return tok, nil
+	case json.Number:
+		return number(tok)
+	case string:
+		var s, err = d.str(tok, mapKey)
+		if err != nil {
+			return nil, err
+		}
+		return parseString(s)
+	case json.Delim:
+		switch tok {
+		case '[':
+			return d.array()
+		case '{':
+			return d.object()
+		}
+	}
+	return nil, fmt.Errorf("transit: unexpected %v", tok)
+}
+
+func number(n json.Number) (any, error) {
+	if strings.ContainsAny(string(n), ".eE") {
+		var f, err = n.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("transit: invalid number %s", n)
+		}
+		return f, nil
+	}
+	var i, err = n.Int64()
+	if err != nil {
+		return nil, fmt.Errorf("transit: integer %s out of range", n)
+	}
+	return i, nil
+}
+
+// parseString returns the value of the string s, which may be a scalar
+// encoded as a string such as a keyword.
+func parseString(s string) (any, error) {
+	if len(s) < 2 || s[0] != '~' {
+		return s, nil
+	}
+
+	var rest = s[2:]
+	switch s[1] {
+	case '~', '^', '`':
+		return s[1:], nil
+	case '_':
+		return nil, nil
+	case '?':
+		return rest == "t", nil
+	case ':':
+		return edn.Keyword(rest), nil
+	case '$':
+		return edn.Symbol(rest), nil
+	case 'c':
+		var r, size = utf8.DecodeRuneInString(rest)
+		if size == 0 || size != len(rest) {
+			return nil, fmt.Errorf("transit: invalid character %q", s)
+		}
+		return edn.Char(r), nil
+	case 'i':
+		var i, err = strconv.ParseInt(rest, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("transit: invalid integer %q", s)
+		}
+		return i, nil
+	case 'n':
+		if i, err := strconv.ParseInt(rest, 10, 64); err == nil {
+			return i, nil
+		}
+		// Bigints too large for an int64 may still fit in a uint64.
+		var u, err = strconv.ParseUint(rest, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("transit: integer %q out of range", s)
+		}
+		return u, nil
+	case 'd':
+		var f, err = strconv.ParseFloat(rest, 64)
+		if err != nil {
+			return nil, fmt.Errorf("transit: invalid float %q", s)
+		}
+		return f, nil
+	case 'z':
+		switch rest {
+		case "NaN":
+			return math.NaN(), nil
+		case "INF":
+			return math.Inf(1), nil
+		case "-INF":
+			return math.Inf(-1), nil
+		}
+		return nil, fmt.Errorf("transit: invalid special number %q", s)
+	case 'm':
+		var ms, err = strconv.ParseInt(rest, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("transit: invalid time %q", s)
+		}
+		return time.UnixMilli(ms).UTC(), nil
+	case 't':
+		var t, err = time.Parse(time.RFC3339Nano, rest)
+		if err != nil {
+			return nil, fmt.Errorf("transit: invalid time %q", s)
+		}
+		return t, nil
+	default:
+		return edn.Tagged{Tag: edn.Symbol(s[1:2]), Value: rest}, nil
+	}
+}
+
+// array parses the rest of an array, which may be a map, a tagged value or a
+// vector depending on its first element.
+func (d *decoder) array() (any, error) {
+	if !d.json.More() {
+		return d.end(vectors.New[any]())
+	}
+
+	var tok, err = d.token()
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := tok.(string); ok {
+		if s == "^ " {
+			return d.entries(true)
+		}
+		if s, err = d.str(s, false); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(s, "~#") {
+			return d.tagged(s[2:])
+		}
+		tok = s
+	}
+
+	var b vectors.Builder[any]
+	for {
+		// The first element has already been looked up in the cache, so
+		// it mustn't be added again.
+		var v any
+		if s, ok := tok.(string); ok && b.Len() == 0 {
+			v, err = parseString(s)
+		} else {
+			v, err = d.valueOf(tok, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.Append(v)
+
+		if !d.json.More() {
+			return d.end(b.Vector())
+		}
+		if tok, err = d.token(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// end consumes the closing delimiter of an array or object, returning v.
+func (d *decoder) end(v any) (any, error) {
+	if _, err := d.token(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// tagged parses the value of a tagged array after its tag.
+func (d *decoder) tagged(tag string) (any, error) {
+	var v any
+	var err error
+	switch tag {
+	case "cmap":
+		var tok json.Token
+		if tok, err = d.token(); err != nil {
+			return nil, err
+		}
+		if tok != json.Delim('[') {
+			return nil, fmt.Errorf("transit: cmap of %v rather than an array", tok)
+		}
+		v, err = d.entries(false)
+	case "set", "list":
+		var items any
+		if items, err = d.value(false); err != nil {
+			return nil, err
+		}
+		v, err = collection(tag, items)
+	default:
+		if v, err = d.value(false); err != nil {
+			return nil, err
+		}
+		if tag != "'" {
+			v = edn.Tagged{Tag: edn.Symbol(tag), Value: v}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if d.json.More() {
+		return nil, fmt.Errorf("transit: tagged value #%s with more than one value", tag)
+	}
+	return d.end(v)
+}
+
+// collection returns the items of the vector v as a set or a list.
+func collection(tag string, v any) (any, error) {
+	var items, ok = v.(vectors.Vector[any])
+	if !ok {
+		return nil, fmt.Errorf("transit: %s of %T rather than an array", tag, v)
+	}
+
+	if tag == "list" {
+		var b lists.Builder[any]
+		for _, item := range items.All() {
+			b.Append(item)
+		}
+		return b.List(), nil
+	}
+
+	var s = maps.NewWith[any, struct{}](edn.Hash, edn.Equal)
+	for _, item := range items.All() {
+		s = s.Assoc(item, struct{}{})
+	}
+	return s, nil
+}
+
+// entries parses the rest of an array of alternating keys and values, where
+// keys are read as map keys if mapKeys is true.
+func (d *decoder) entries(mapKeys bool) (any, error) {
+	var m = maps.NewWith[any, any](edn.Hash, edn.Equal)
+	for d.json.More() {
+		var key, err = d.value(mapKeys)
+		if err != nil {
+			return nil, err
+		}
+		if !d.json.More() {
+			return nil, fmt.Errorf("transit: map with a key %v but no value", key)
+		}
+		var value any
+		if value, err = d.value(false); err != nil {
+			return nil, err
+		}
+		m = m.Assoc(key, value)
+	}
+	return d.end(m)
+}
+
+// object parses the rest of a JSON object as a map, as written by the verbose
+// form of Transit JSON.
+func (d *decoder) object() (any, error) {
+	var m = maps.NewWith[any, any](edn.Hash, edn.Equal)
+	for d.json.More() {
+		var key, err = d.value(true)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if value, err = d.value(false); err != nil {
+			return nil, err
+		}
+		m = m.Assoc(key, value)
+	}
+	return d.end(m)
+}
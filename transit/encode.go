@@ -0,0 +1,288 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toddgaunt/persistent/edn"
+)
+
+// maxSafeInteger is the largest integer a JavaScript number holds exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// Marshal returns the Transit JSON encoding of v. An error is returned if v or
+// any value within it is of a type which can't be encoded.
+func Marshal(v any) ([]byte, error) {
+	var e = encoder{cache: map[string]int{}}
+	var rv = reflect.ValueOf(v)
+	if _, _, ok := scalar(rv, false); ok {
+		// Transit requires the top level value to be a collection, so a
+		// scalar is wrapped in a quote.
+		if err := e.tagged("'", func() error { return e.value(rv) }); err != nil {
+			return nil, err
+		}
+		return e.data, nil
+	}
+	if err := e.value(rv); err != nil {
+		return nil, err
+	}
+	return e.data, nil
+}
+
+type encoder struct {
+	data  []byte
+	cache map[string]int
+}
+
+// str writes s, which is a string as written, or a reference to it if it is
+// in the cache.
+func (e *encoder) str(s string, mapKey bool) {
+	if i, ok := e.cache[s]; ok {
+		s = cacheCode(i)
+	} else if cacheable(s, mapKey) {
+		if len(e.cache) == cacheSize {
+			clear(e.cache)
+		}
+		e.cache[s] = len(e.cache)
+	}
+	var quoted, _ = json.Marshal(s)
+	e.data = append(e.data, quoted...)
+}
+
+// tagged writes a tagged value with the value written by value.
+func (e *encoder) tagged(tag string, value func() error) error {
+	e.data = append(e.data, '[')
+	e.str("~#"+tag, false)
+	e.data = append(e.data, ',')
+	if err := value(); err != nil {
+		return err
+	}
+	e.data = append(e.data, ']')
+	return nil
+}
+
+// array writes an array of n values, with the value at index i written by
+// item(i).
+func (e *encoder) array(n int, item func(i int) error) error {
+	e.data = append(e.data, '[')
+	for i := 0; i < n; i += 1 {
+		if i > 0 {
+			e.data = append(e.data, ',')
+		}
+		if err := item(i); err != nil {
+			return err
+		}
+	}
+	e.data = append(e.data, ']')
+	return nil
+}
+
+func unwrap(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// scalar returns the encoding of v if it is a scalar value, true if the
+// encoding is a string rather than other JSON, and true if v is a scalar.
+// Strings are returned as written by Transit but before being quoted as JSON.
+// Map keys are always encoded as strings.
+func scalar(v reflect.Value, mapKey bool) (string, bool, bool) {
+	if v = unwrap(v); !v.IsValid() {
+		if mapKey {
+			return "~_", true, true
+		}
+		return "null", false, true
+	}
+
+	switch x := v.Interface().(type) {
+	case edn.Keyword:
+		return "~:" + string(x), true, true
+	case edn.Symbol:
+		return "~$" + string(x), true, true
+	case edn.Char:
+		return "~c" + string(rune(x)), true, true
+	case edn.Tagged:
+		// Tags of a single character with a string value are scalar
+		// extensions, such as ~u for UUIDs.
+		if s, ok := x.Value.(string); ok && len(x.Tag) == 1 {
+			return "~" + string(x.Tag) + s, true, true
+		}
+		return "", false, false
+	case time.Time:
+		return "~m" + strconv.FormatInt(x.UnixMilli(), 10), true, true
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if mapKey {
+			return map[bool]string{true: "~?t", false: "~?f"}[v.Bool()], true, true
+		}
+		return strconv.FormatBool(v.Bool()), false, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i = v.Int()
+		if mapKey || i > maxSafeInteger || i < -maxSafeInteger {
+			return "~i" + strconv.FormatInt(i, 10), true, true
+		}
+		return strconv.FormatInt(i, 10), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u = v.Uint()
+		if u > math.MaxInt64 {
+			// Integers too large for an int64 are written as bigints.
+			return "~n" + strconv.FormatUint(u, 10), true, true
+		}
+		if mapKey || u > maxSafeInteger {
+			return "~i" + strconv.FormatUint(u, 10), true, true
+		}
+		return strconv.FormatUint(u, 10), false, true
+	case reflect.Float32, reflect.Float64:
+		var f = v.Float()
+		switch {
+		case math.IsNaN(f):
+			return "~zNaN", true, true
+		case math.IsInf(f, 1):
+			return "~zINF", true, true
+		case math.IsInf(f, -1):
+			return "~z-INF", true, true
+		case mapKey:
+			return "~d" + strconv.FormatFloat(f, 'g', -1, 64), true, true
+		}
+		var s = strconv.FormatFloat(f, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			// Without a decimal point or exponent the number would be read as
+			// an integer.
+			s += ".0"
+		}
+		return s, false, true
+	case reflect.String:
+		var s = v.String()
+		if s != "" && (s[0] == '~' || s[0] == '^' || s[0] == '`') {
+			s = "~" + s
+		}
+		return s, true, true
+	}
+	return "", false, false
+}
+
+func (e *encoder) value(v reflect.Value) error {
+	if s, str, ok := scalar(v, false); ok {
+		if str {
+			e.str(s, false)
+		} else {
+			e.data = append(e.data, s...)
+		}
+		return nil
+	}
+
+	v = unwrap(v)
+	if t, ok := v.Interface().(edn.Tagged); ok {
+		return e.tagged(string(t.Tag), func() error { return e.value(reflect.ValueOf(t.Value)) })
+	}
+
+	if has(v, "GetOK", "Assoc", "All") {
+		var entries = collect(v)
+		if v.MethodByName("GetOK").Type().Out(0) == reflect.TypeFor[struct{}]() {
+			return e.tagged("set", func() error {
+				return e.array(len(entries), func(i int) error { return e.value(entries[i][0]) })
+			})
+		}
+		return e.entries(entries)
+	}
+	if has(v, "Len", "Nth", "Assoc") {
+		var n = int(v.MethodByName("Len").Call(nil)[0].Int())
+		var nth = v.MethodByName("Nth")
+		return e.array(n, func(i int) error {
+			return e.value(nth.Call([]reflect.Value{reflect.ValueOf(i)})[0])
+		})
+	}
+	if has(v, "Len", "All", "Insert", "Remove") {
+		var items = collect(v)
+		return e.tagged("list", func() error {
+			return e.array(len(items), func(i int) error { return e.value(items[i][0]) })
+		})
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return e.array(v.Len(), func(i int) error { return e.value(v.Index(i)) })
+	case reflect.Map:
+		var entries = make([][2]reflect.Value, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			entries = append(entries, [2]reflect.Value{it.Key(), it.Value()})
+		}
+		return e.entries(entries)
+	}
+
+	return fmt.Errorf("transit: cannot encode a value of type %s", v.Type())
+}
+
+// entries writes the entries of a map, as an array beginning with "^ " if
+// every key is a scalar or as a tagged cmap array of keys and values
+// otherwise.
+func (e *encoder) entries(entries [][2]reflect.Value) error {
+	var composite = false
+	for _, entry := range entries {
+		if _, _, ok := scalar(entry[0], true); !ok {
+			composite = true
+			break
+		}
+	}
+
+	if composite {
+		return e.tagged("cmap", func() error {
+			return e.array(2*len(entries), func(i int) error { return e.value(entries[i/2][i%2]) })
+		})
+	}
+
+	e.data = append(e.data, `["^ "`...)
+	for _, entry := range entries {
+		e.data = append(e.data, ',')
+		var key, _, _ = scalar(entry[0], true)
+		e.str(key, true)
+		e.data = append(e.data, ',')
+		if err := e.value(entry[1]); err != nil {
+			return err
+		}
+	}
+	e.data = append(e.data, ']')
+	return nil
+}
+
+// has returns true if v has every method in names.
+func has(v reflect.Value, names ...string) bool {
+	for _, name := range names {
+		if !v.MethodByName(name).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// collect returns the values yielded by each step of the iterator returned by
+// calling the All method of v, with the second value being invalid for an
+// iter.Seq.
+func collect(v reflect.Value) [][2]reflect.Value {
+	var values [][2]reflect.Value
+	var all = v.MethodByName("All").Call(nil)[0]
+	var yield = reflect.MakeFunc(all.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		var step [2]reflect.Value
+		copy(step[:], args)
+		values = append(values, step)
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+	all.Call([]reflect.Value{yield})
+	return values
+}
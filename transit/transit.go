@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package transit encodes persistent data structures in the JSON form of the
+// Transit format used by Clojure and ClojureScript, and decodes Transit back
+// into persistent data structures. Maps are written as arrays beginning with
+// "^ ", and repeated map keys, keywords, symbols and tags are replaced by
+// short references to their first occurrence, as the format specifies.
+//
+// Values are encoded according to their type, in the same way as the edn
+// package, using the Keyword, Symbol, Char and Tagged types of that package
+// for the values of Transit without a Go counterpart:
+//
+//   - nil, booleans, numbers and strings as their JSON counterparts, except
+//     for integers too large to be represented exactly by a JavaScript
+//     number, which are written as strings, and unsigned integers too large
+//     for an int64, which are written as bigints. Floating point numbers
+//     always have a decimal point or exponent.
+//   - time.Time values as Transit times, with millisecond precision.
+//   - edn.Keyword, edn.Symbol and edn.Char values as Transit keywords,
+//     symbols and characters, and edn.Tagged values as tagged values.
+//   - Maps with struct{} values as sets of their keys, other maps as maps,
+//     vectors and Go slices as arrays, and lists as lists, where maps,
+//     vectors and lists are any values with the methods described by the edn
+//     package.
+//
+// Decoding produces values of dynamic types: nil, bool, int64, uint64 for
+// bigints too large for an int64, float64, string, time.Time, edn.Keyword,
+// edn.Symbol, edn.Char, edn.Tagged, vectors.Vector[any], lists.List[any],
+// maps.HashMap[any, any] for maps and maps.HashMap[any, struct{}] for sets.
+// As with the edn package, the keys of decoded maps and sets are hashed and
+// compared with edn.Hash and edn.Equal, so the composite keys of a cmap such
+// as vectors and maps are compared by their contents. Tagged values of
+// unknown tags are decoded as edn.Tagged values, so they are encoded again
+// unchanged.
+package transit
+
+// The cache of strings used by both the encoder and the decoder. Each string
+// which is cacheable is added to the cache the first time it is seen, and is
+// written as a reference of "^" followed by one or two digits in base 44 each
+// time after. Once the cache is full it is emptied and starts over.
+const (
+	cacheDigits = 44
+	cacheBase   = '0'
+	cacheSize   = cacheDigits * cacheDigits
+)
+
+// cacheable returns true if s, which is a string as written, is added to the
+// cache when it is seen either as a map key or elsewhere.
+func cacheable(s string, mapKey bool) bool {
+	if len(s) <= 3 {
+		return false
+	}
+	if mapKey {
+		return true
+	}
+	return s[0] == '~' && (s[1] == ':' || s[1] == '$' || s[1] == '#')
+}
+
+// cacheCode returns the reference to the string at index i of the cache.
+func cacheCode(i int) string {
+	if i < cacheDigits {
+		return string([]byte{'^', byte(cacheBase + i)})
+	}
+	return string([]byte{'^', byte(cacheBase + i/cacheDigits), byte(cacheBase + i%cacheDigits)})
+}
+
+// cacheIndex returns the index of the string referred to by code, and true if
+// code is a reference.
+func cacheIndex(code string) (int, bool) {
+	switch {
+	case len(code) == 2 && code[0] == '^' && code[1] != ' ':
+		return int(code[1] - cacheBase), true
+	case len(code) == 3 && code[0] == '^':
+		return int(code[1]-cacheBase)*cacheDigits + int(code[2]-cacheBase), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,217 @@
+package transit_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/toddgaunt/persistent/edn"
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/sortedmap"
+	"github.com/toddgaunt/persistent/transit"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestMarshal(t *testing.T) {
+	var user = func(name string) sortedmap.Map[edn.Keyword, string] {
+		return sortedmap.New[edn.Keyword, string]().Assoc("name", name).Assoc("role", "admin")
+	}
+
+	var testCases = []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"Nil", nil, `["~#'",null]`},
+		{"Int", 42, `["~#'",42]`},
+		{"BigInt", int64(1) << 60, `["~#'","~i1152921504606846976"]`},
+		{"BigUint", uint64(math.MaxUint64), `["~#'","~n18446744073709551615"]`},
+		{"WholeFloat", 2.0, `["~#'",2.0]`},
+		{"String", "~tilde", `["~#'","~~tilde"]`},
+		{"Keyword", edn.Keyword("a"), `["~#'","~:a"]`},
+		{"NaN", math.NaN(), `["~#'","~zNaN"]`},
+		{"Time", time.UnixMilli(1500000000000), `["~#'","~m1500000000000"]`},
+		{"Vector", vectors.New[any](1, "a", true, 1.5), `[1,"a",true,1.5]`},
+		{"List", lists.New(1, 2), `["~#list",[1,2]]`},
+		{"Set", maps.New[int, struct{}]().Assoc(1, struct{}{}), `["~#set",[1]]`},
+		{"MapKeys", sortedmap.New[int, bool]().Assoc(1, true), `["^ ","~i1",true]`},
+		{"CompositeKeys", map[[1]int]int{{1}: 2}, `["~#cmap",[[1],2]]`},
+		{"Tagged", edn.Tagged{Tag: "point", Value: vectors.New(1, 2)}, `["~#point",[1,2]]`},
+		{"ScalarTag", edn.Tagged{Tag: "u", Value: "531a379e"}, `["~#'","~u531a379e"]`},
+		{"Cache", vectors.New(user("ann"), user("bob")),
+			`[["^ ","~:name","ann","~:role","admin"],["^ ","^0","bob","^1","admin"]]`},
+		{"CacheKeywordValues", vectors.New(edn.Keyword("long"), edn.Keyword("long"), edn.Keyword("a"), edn.Keyword("a")),
+			`["~:long","^0","~:a","~:a"]`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = transit.Marshal(tc.value)
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Quoted", `["~#'","~~a"]`, `["~#'","~~a"]`},
+		{"Vector", `[1,"~i2",2.5,"~d3",null,"~?t"]`, `[1,2,2.5,3.0,null,true]`},
+		{"Special", `["~zINF","~z-INF"]`, `["~zINF","~z-INF"]`},
+		{"Symbols", `["~$inc","~cx"]`, `["~$inc","~cx"]`},
+		{"List", `["~#list",[1,["~#list",[]]]]`, `["~#list",[1,["^0",[]]]]`},
+		{"Cache", `[["^ ","~:name","ann"],["^ ","^0","bob"]]`, `[["^ ","~:name","ann"],["^ ","^0","bob"]]`},
+		{"CachedTag", `[["~#point",[1]],["^0",[2]]]`, `[["~#point",[1]],["^0",[2]]]`},
+		{"Verbose", `{"~:a":1}`, `["^ ","~:a",1]`},
+		{"Unknown", `["~#point",[1,2]]`, `["~#point",[1,2]]`},
+		{"EmptyVector", `[]`, `[]`},
+		{"EmptyMap", `["^ "]`, `["^ "]`},
+		{"CompositeKey", `["~#cmap",[[1,2],3]]`, `["~#cmap",[[1,2],3]]`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var v, err = transit.Unmarshal([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			var got, _ = transit.Marshal(v)
+			if string(got) != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTypes(t *testing.T) {
+	var v, err = transit.Unmarshal([]byte(`["^ ","~:users",[["^ ","~:age",30,"~:tags",["~#set",["a"]]]],"~:when","~m0"]`))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	var root = v.(maps.HashMap[any, any])
+	var user = root.Get(edn.Keyword("users")).(vectors.Vector[any]).Nth(0).(maps.HashMap[any, any])
+	if got, want := user.Get(edn.Keyword("age")), int64(30); got != want {
+		t.Fatalf("got age %v, want %v", got, want)
+	}
+	if tags := user.Get(edn.Keyword("tags")).(maps.HashMap[any, struct{}]); !tags.Contains("a") {
+		t.Fatalf("got tags %v, want a set of a", tags)
+	}
+	if got, want := root.Get(edn.Keyword("when")), time.UnixMilli(0).UTC(); got != want {
+		t.Fatalf("got when %v, want %v", got, want)
+	}
+}
+
+func TestUnmarshalCompositeKeys(t *testing.T) {
+	var v, err = transit.Unmarshal([]byte(`["~#cmap",[[1,2],"vector",["^ ","~:a",1],"map",["~#set",[1,2]],"set",[1,2],"again"]]`))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	var m = v.(maps.HashMap[any, any])
+	if got, want := m.Len(), 3; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	var keys = []struct {
+		key  any
+		want string
+	}{
+		{vectors.New[any](int64(1), int64(2)), "again"},
+		{maps.NewWith[any, any](edn.Hash, edn.Equal).Assoc(edn.Keyword("a"), int64(1)), "map"},
+		{maps.NewWith[any, struct{}](edn.Hash, edn.Equal).Assoc(int64(2), struct{}{}).Assoc(int64(1), struct{}{}), "set"},
+	}
+	for _, k := range keys {
+		if got, ok := m.GetOK(k.key); !ok || got != k.want {
+			t.Fatalf("got %v, %t for key %v, want %v, true", got, ok, k.key, k.want)
+		}
+	}
+
+	v, err = transit.Unmarshal([]byte(`["~#set",[["^ ","~:a",1],["^ ","~:a",1],[1],[1]]]`))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := v.(maps.HashMap[any, struct{}]).Len(), 2; got != want {
+		t.Fatalf("got a set of %d elements, want %d", got, want)
+	}
+}
+
+func TestRoundTripNumbers(t *testing.T) {
+	for _, want := range []any{int64(math.MinInt64), int64(math.MaxInt64), uint64(math.MaxUint64), 2.0, -0.5, 1e21} {
+		var data, err = transit.Marshal(want)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		var got any
+		if got, err = transit.Unmarshal(data); err != nil {
+			t.Fatalf("got error %v for %s, want nil", err, data)
+		}
+		if got != want {
+			t.Fatalf("got %#v from %s, want %#v", got, data, want)
+		}
+	}
+}
+
+func TestCacheOverflow(t *testing.T) {
+	// Enough distinct keys to fill the cache more than once, written twice
+	// so the second map refers to keys cached by the first.
+	var m = maps.New[string, int]()
+	for i := 0; i < 5000; i++ {
+		m = m.Assoc(fmt.Sprintf("key%d", i), i)
+	}
+
+	var data, err = transit.Marshal(vectors.New(m, m))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	v, err := transit.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	var got = v.(vectors.Vector[any])
+	for i := 0; i < got.Len(); i++ {
+		var decoded = got.Nth(i).(maps.HashMap[any, any])
+		for key, value := range m.All() {
+			if other := decoded.Get(key); other != int64(value) {
+				t.Fatalf("got map %d %s=%v, want %d", i, key, other, value)
+			}
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		input string
+	}{
+		{"Empty", ""},
+		{"Invalid", `[1,`},
+		{"Trailing", `[1] [2]`},
+		{"MissingValue", `["^ ","a"]`},
+		{"MissingCache", `["^ ","^5",1]`},
+		{"BadInteger", `["~#'","~ix"]`},
+		{"TaggedTwice", `["~#point",1,2]`},
+		{"BadSet", `["~#set",1]`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if v, err := transit.Unmarshal([]byte(tc.input)); err == nil {
+				t.Fatalf("got %v and nil error, want an error", v)
+			}
+		})
+	}
+}
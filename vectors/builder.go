@@ -0,0 +1,39 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Builder is used to efficiently build a vector by appending values to it,
+// similarly to strings.Builder. It uses a transient vector internally, but
+// unlike a transient vector it may be used freely after any of its methods
+// are called, including after a persistent vector is made from it with
+// Vector. The zero value of Builder is an empty builder ready to use.
+type Builder[T any] struct {
+	tv TransientVector[T]
+}
+
+// Append appends value to the end of the vector being built.
+func (b *Builder[T]) Append(value T) {
+	b.tv.Conj(value)
+}
+
+// AppendSlice appends each of values to the end of the vector being built in
+// order.
+func (b *Builder[T]) AppendSlice(values []T) {
+	b.tv.ConjSlice(values)
+}
+
+// Len returns the number of values appended to the builder so far.
+func (b *Builder[T]) Len() int {
+	return b.tv.Len()
+}
+
+// Vector returns a persistent vector containing every value appended to the
+// builder so far. The builder may continue to be appended to afterwards
+// without affecting the returned vector.
+func (b *Builder[T]) Vector() Vector[T] {
+	var v = b.tv.Persistent()
+	b.tv = v.Transient()
+	return v
+}
@@ -0,0 +1,32 @@
+package vectors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestBuilder(t *testing.T) {
+	var b vectors.Builder[int]
+	if got := b.Vector(); got.Len() != 0 {
+		t.Fatalf("got %v from an empty builder, want []", got)
+	}
+
+	b.Append(testSlice[0])
+	b.AppendSlice(testSlice[1:40])
+	if got, want := b.Len(), 40; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+
+	var first = b.Vector()
+	b.AppendSlice(testSlice[40:])
+	var second = b.Vector()
+
+	if got, want := first.String(), fmt.Sprintf("%v", testSlice[:40]); got != want {
+		t.Fatalf("got first %s, want %s", got, want)
+	}
+	if got, want := second.String(), fmt.Sprintf("%v", testSlice); got != want {
+		t.Fatalf("got second %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// bytesLeaf is a full leaf of a Bytes vector, stored inline as an array.
+type bytesLeaf [nodeWidth]byte
+
+// bytesBranch is an interior node of a Bytes vector. Branches at level 1
+// point directly to leaves, while branches above them point to other
+// branches. Since every child of a branch is of the same type, determined by
+// the level of the branch, the children share a single array rather than each
+// branch carrying an array for both types, and are only accessed through the
+// branch and leaf methods.
+type bytesBranch struct {
+	children [nodeWidth]unsafe.Pointer
+}
+
+// branch returns the child branch at index i of b, which must be above level 1.
+func (b *bytesBranch) branch(i int) *bytesBranch {
+	return (*bytesBranch)(b.children[i])
+}
+
+// leaf returns the leaf at index i of b, which must be at level 1.
+func (b *bytesBranch) leaf(i int) *bytesLeaf {
+	return (*bytesLeaf)(b.children[i])
+}
+
+// Bytes is a persistent vector of bytes. It provides the same guarantees as
+// Vector[byte], but packs its leaves into fixed size arrays referenced
+// directly by their parent, which avoids the per-leaf node and slice headers
+// of the generic vector. This makes Bytes far more compact for storing large
+// binary blobs. The zero value of Bytes is an empty vector ready to use.
+type Bytes struct {
+	count  int          // Number of bytes in this vector
+	height int          // Number of levels of branches in the tree
+	tail   []byte       // Quickly access bytes at the end of the vector
+	root   *bytesBranch // Root of the tree; nil if all bytes are in the tail
+}
+
+// NewBytes creates a new persistent byte vector containing a copy of b.
+func NewBytes(b []byte) Bytes {
+	return Bytes{}.Append(b)
+}
+
+// Len returns the number of bytes in v.
+func (v Bytes) Len() int {
+	return v.count
+}
+
+// Nth returns the byte at the index provided. The index must be greater than
+// or equal to zero and less than v.Len().
+func (v Bytes) Nth(index int) byte {
+	if index < 0 || index >= v.count {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", index, v.count))
+	}
+
+	var tailStart = tailOffset(v.count)
+	if index >= tailStart {
+		return v.tail[index-tailStart]
+	}
+
+	var walk = v.root
+	for level := v.height; level > 1; level -= 1 {
+		walk = walk.branch(indexAt(level, index))
+	}
+
+	return walk.leaf(indexAt(1, index))[indexAt(0, index)]
+}
+
+// Assoc creates a new byte vector with the byte at index replaced by b. The
+// index must be greater than or equal to zero and less than v.Len().
+func (v Bytes) Assoc(index int, b byte) Bytes {
+	if index < 0 || index >= v.count {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", index, v.count))
+	}
+
+	var tailStart = tailOffset(v.count)
+	if index >= tailStart {
+		var newTail = cloneTail(v.tail)
+		newTail[index-tailStart] = b
+		v.tail = newTail
+		return v
+	}
+
+	// Clone the path down to the leaf containing index, and then the leaf.
+	var newRoot = *v.root
+	var walk = &newRoot
+	for level := v.height; level > 1; level -= 1 {
+		var i = indexAt(level, index)
+		var clone = *walk.branch(i)
+		walk.children[i] = unsafe.Pointer(&clone)
+		walk = &clone
+	}
+
+	var i = indexAt(1, index)
+	var leaf = *walk.leaf(i)
+	leaf[indexAt(0, index)] = b
+	walk.children[i] = unsafe.Pointer(&leaf)
+
+	v.root = &newRoot
+	return v
+}
+
+// Conj creates a new byte vector with b appended to the end.
+func (v Bytes) Conj(b byte) Bytes {
+	return v.Append([]byte{b})
+}
+
+// Append creates a new byte vector with a copy of every byte of p appended to
+// the end. Full leaves are copied directly out of p rather than a byte at a
+// time.
+func (v Bytes) Append(p []byte) Bytes {
+	for len(p) > 0 {
+		if len(v.tail) == nodeWidth {
+			v = v.pushTail()
+		}
+
+		var n = min(nodeWidth-len(v.tail), len(p))
+		var newTail = make([]byte, len(v.tail)+n, nodeWidth)
+		copy(newTail, v.tail)
+		copy(newTail[len(v.tail):], p[:n])
+
+		v.tail = newTail
+		v.count += n
+		p = p[n:]
+	}
+
+	return v
+}
+
+// pushTail moves the full tail of v into the tree, leaving v with an empty
+// tail. Nodes along the path to the new leaf are cloned, so other vectors
+// sharing the tree are unaffected.
+func (v Bytes) pushTail() Bytes {
+	var leaf = bytesLeaf(v.tail)
+	var leafIndex = v.count - nodeWidth
+
+	if v.root == nil {
+		v.root = &bytesBranch{}
+		v.height = 1
+	} else if leafIndex>>nodeBits == 1<<(v.height*nodeBits) {
+		// The tree is full, so deepen it with a new root holding the old one.
+		var newRoot = &bytesBranch{}
+		newRoot.children[0] = unsafe.Pointer(v.root)
+		v.root = newRoot
+		v.height += 1
+	}
+
+	var newRoot = *v.root
+	var walk = &newRoot
+	for level := v.height; level > 1; level -= 1 {
+		var i = indexAt(level, leafIndex)
+		var clone bytesBranch
+		if child := walk.branch(i); child != nil {
+			clone = *child
+		}
+		walk.children[i] = unsafe.Pointer(&clone)
+		walk = &clone
+	}
+	walk.children[indexAt(1, leafIndex)] = unsafe.Pointer(&leaf)
+
+	v.root = &newRoot
+	v.tail = nil
+	return v
+}
+
+// Bytes returns a copy of the contents of v as a byte slice.
+func (v Bytes) Bytes() []byte {
+	var b = make([]byte, 0, v.count)
+	var tailStart = tailOffset(v.count)
+	for i := 0; i < tailStart; i += nodeWidth {
+		var walk = v.root
+		for level := v.height; level > 1; level -= 1 {
+			walk = walk.branch(indexAt(level, i))
+		}
+		b = append(b, walk.leaf(indexAt(1, i))[:]...)
+	}
+	return append(b, v.tail...)
+}
+
+// String returns a representation of a byte vector in the same form as a Go
+// byte slice when using the "%v" formatting verb as in the standard fmt
+// package.
+func (v Bytes) String() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i, b := range v.Bytes() {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(&sb, "%d", b)
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
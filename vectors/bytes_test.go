@@ -0,0 +1,89 @@
+package vectors_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func newTestBytes(n int) []byte {
+	var b = make([]byte, n)
+	for i := range b {
+		b[i] = byte(i * 7)
+	}
+	return b
+}
+
+func TestBytes(t *testing.T) {
+	var testCases = []int{0, 1, 31, 32, 33, 64, 65, 32*32 + 32, 32*32 + 33, 32*32*32 + 100}
+
+	for _, n := range testCases {
+		n := n
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			var want = newTestBytes(n)
+			var got = vectors.NewBytes(want)
+
+			if got.Len() != n {
+				t.Fatalf("got Len()=%d, want Len()=%d", got.Len(), n)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Fatalf("got Bytes() differing from the input")
+			}
+			for i := 0; i < n; i++ {
+				if got.Nth(i) != want[i] {
+					t.Fatalf("want byte %d at index %d, got %d", want[i], i, got.Nth(i))
+				}
+			}
+		})
+	}
+}
+
+func TestBytesConj(t *testing.T) {
+	var want = newTestBytes(32*32 + 40)
+	var got vectors.Bytes
+	for _, b := range want {
+		got = got.Conj(b)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Fatalf("got Bytes() differing from the conjoined bytes")
+	}
+}
+
+func TestBytesAssoc(t *testing.T) {
+	var original = newTestBytes(32*32 + 40)
+	var vec = vectors.NewBytes(original)
+
+	for _, index := range []int{0, 31, 32, 500, 32 * 32, len(original) - 1} {
+		var updated = vec.Assoc(index, original[index]+1)
+		if got, want := updated.Nth(index), original[index]+1; got != want {
+			t.Fatalf("got updated.Nth(%d)=%d, want %d", index, got, want)
+		}
+		if !bytes.Equal(vec.Bytes(), original) {
+			t.Fatalf("Assoc(%d) modified the original vector", index)
+		}
+	}
+}
+
+func TestBytesShared(t *testing.T) {
+	// Appending to the same vector twice must not let either result see the
+	// bytes appended to the other.
+	var vec = vectors.NewBytes(newTestBytes(96))
+	var a = vec.Append(bytes.Repeat([]byte{1}, 100))
+	var b = vec.Append(bytes.Repeat([]byte{2}, 100))
+
+	if got, want := a.Bytes()[96:], bytes.Repeat([]byte{1}, 100); !bytes.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := b.Bytes()[96:], bytes.Repeat([]byte{2}, 100); !bytes.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBytesString(t *testing.T) {
+	var b = []byte{1, 2, 255}
+	if got, want := vectors.NewBytes(b).String(), fmt.Sprintf("%v", b); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
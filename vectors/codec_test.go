@@ -0,0 +1,86 @@
+package vectors_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toddgaunt/persistent/codec"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+// date is encoded by its codec as a string with only the day, rather than as
+// the full timestamp time.Time is encoded as.
+type date struct{ time.Time }
+
+func init() {
+	codec.Register(
+		func(d date) (string, error) { return d.Format(time.DateOnly), nil },
+		func(s string) (date, error) {
+			var t, err = time.Parse(time.DateOnly, s)
+			return date{t}, err
+		},
+	)
+}
+
+func newDate(s string) date {
+	var t, _ = time.Parse(time.DateOnly, s)
+	return date{t}
+}
+
+func TestVectorCodecJSON(t *testing.T) {
+	var v = vectors.New(newDate("2024-01-02"), newDate("1999-12-31"))
+	var data, err = json.Marshal(v)
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(data), `["2024-01-02","1999-12-31"]`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	var got vectors.Vector[date]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if !vectors.Equal(got, v) {
+		t.Fatalf("got %v, want %v", got, v)
+	}
+
+	streamed, err := vectors.DecodeJSON[date](json.NewDecoder(strings.NewReader(string(data))))
+	if err != nil || !vectors.Equal(streamed, v) {
+		t.Fatalf("got %v, %v, want %v, nil", streamed, err, v)
+	}
+
+	var mixed = vectors.New[any](newDate("2024-01-02"), 1, nil)
+	if data, err = json.Marshal(mixed); err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(data), `["2024-01-02",1,null]`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	var bad vectors.Vector[date]
+	if err := json.Unmarshal([]byte(`["2024-13-01"]`), &bad); err == nil {
+		t.Fatalf("got %v and nil error for an invalid date, want an error", bad)
+	}
+}
+
+func TestVectorCodecBinary(t *testing.T) {
+	var v = vectors.New(newDate("2024-01-02"), newDate("1999-12-31"))
+	var data, err = v.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if !strings.Contains(string(data), "1999-12-31") {
+		t.Fatalf("got % x, want the dates encoded by their codec", data)
+	}
+
+	var got vectors.Vector[date]
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if !vectors.Equal(got, v) {
+		t.Fatalf("got %v, want %v", got, v)
+	}
+}
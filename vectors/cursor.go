@@ -0,0 +1,117 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import "fmt"
+
+// Cursor provides fast access to a vector for sequential or otherwise
+// localized patterns of Nth and Assoc calls. A cursor keeps a focus on the
+// leaf it most recently accessed, so any access to an index within that leaf
+// takes constant time rather than walking the tree from the root.
+//
+// Values changed with Assoc are written to a private copy of the focused leaf,
+// and are only written into the tree once the focus moves to a different leaf
+// or Vector is called. This means a sequence of Assoc calls within one leaf
+// clones the path to that leaf only once.
+//
+// A Cursor is a separate view of a vector rather than a cache within it. The
+// Nth and Assoc methods of Vector never use or move a cursor's focus, since a
+// Vector is an immutable value which may be shared between goroutines, so
+// sequential access only benefits from a focus when it goes through a Cursor.
+// The vector a cursor was made from is never modified by it, and changes made
+// through the cursor are only seen in the vectors returned by its Vector
+// method.
+//
+// Unlike a Vector, a Cursor is mutable and must not be used by multiple
+// goroutines at the same time.
+type Cursor[T any] struct {
+	vec    Vector[T]
+	start  int  // Index of the first value of the focused leaf
+	values []T  // The focused leaf, or nil if there is no focus
+	dirty  bool // Set when values is a private copy with uncommitted changes
+}
+
+// Cursor creates a new cursor over v.
+func (v Vector[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{vec: v}
+}
+
+// Len returns the number of values in the vector the cursor is over.
+func (c *Cursor[T]) Len() int {
+	return c.vec.count
+}
+
+// focus moves the focus of the cursor to the leaf containing index,
+// committing any changes to the previously focused leaf.
+func (c *Cursor[T]) focus(index int) {
+	if c.values != nil && index >= c.start && index < c.start+len(c.values) {
+		return
+	}
+
+	if index < 0 || index >= c.vec.count {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", index, c.vec.count))
+	}
+
+	c.commit()
+	c.values = findValues(c.vec.count, c.vec.depth, c.vec.root, c.vec.tail, index)
+	c.start = index &^ nodeMask
+}
+
+// commit writes the focused leaf into the vector if it has been changed,
+// cloning the path to it.
+func (c *Cursor[T]) commit() {
+	if !c.dirty {
+		return
+	}
+	c.dirty = false
+
+	if indexInTail(c.start, c.vec.count, c.vec.tail) {
+		c.vec.tail = c.values
+		return
+	}
+
+	var newRoot = cloneNode(persistent, c.vec.root)
+	var indirect = &newRoot
+	for level := c.vec.depth; level > 0; level -= 1 {
+		indirect = &(*indirect).nodes[indexAt(level, c.start)]
+		if level > 1 {
+			*indirect = cloneNode(persistent, *indirect)
+		}
+	}
+	if c.vec.depth == 0 {
+		newRoot = newLeaf(persistent, c.values)
+	} else {
+		*indirect = newLeaf(persistent, c.values)
+	}
+	c.vec.root = newRoot
+}
+
+// Nth returns the value at the index provided. The index must be greater than
+// or equal to zero and less than c.Len().
+func (c *Cursor[T]) Nth(index int) T {
+	c.focus(index)
+
+	return c.values[index-c.start]
+}
+
+// Assoc sets the value at the index provided. The index must be greater than
+// or equal to zero and less than c.Len().
+func (c *Cursor[T]) Assoc(index int, value T) {
+	c.focus(index)
+
+	if !c.dirty {
+		c.values = cloneTail(c.values)
+		c.dirty = true
+	}
+	c.values[index-c.start] = value
+}
+
+// Vector returns a persistent vector containing every change made through the
+// cursor so far. The cursor may continue to be used afterwards.
+func (c *Cursor[T]) Vector() Vector[T] {
+	c.commit()
+
+	return c.vec
+}
@@ -0,0 +1,77 @@
+package vectors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestCursorNth(t *testing.T) {
+	var slice = make([]int, 32*32+40)
+	for i := range slice {
+		slice[i] = i * 3
+	}
+
+	var cursor = vectors.New(slice...).Cursor()
+	if got, want := cursor.Len(), len(slice); got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+
+	// Walk forwards and then backwards to move the focus both ways.
+	for i := 0; i < len(slice); i++ {
+		if got := cursor.Nth(i); got != slice[i] {
+			t.Fatalf("want element %d at index %d, got %d", slice[i], i, got)
+		}
+	}
+	for i := len(slice) - 1; i >= 0; i-- {
+		if got := cursor.Nth(i); got != slice[i] {
+			t.Fatalf("want element %d at index %d, got %d", slice[i], i, got)
+		}
+	}
+}
+
+func TestCursorAssoc(t *testing.T) {
+	var testCases = []int{1, 32, 33, 32*32 + 32, 32*32 + 33, 32*32*2 + 5}
+
+	for _, n := range testCases {
+		n := n
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			var original = vectors.New(make([]int, n)...)
+			var cursor = original.Cursor()
+			var want = original
+			for i := 0; i < n; i++ {
+				cursor.Assoc(i, i+1)
+				want = want.Assoc(i, i+1)
+				if got := cursor.Nth(i); got != i+1 {
+					t.Fatalf("got Nth(%d)=%d after Assoc, want %d", i, got, i+1)
+				}
+			}
+
+			if got, want := cursor.Vector().String(), want.String(); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := vectors.CountIf(original, func(x int) bool { return x != 0 }), 0; got != want {
+				t.Fatalf("got %d changed values in the original vector, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestCursorVectorThenAssoc(t *testing.T) {
+	var cursor = vectors.New(testSlice...).Cursor()
+	cursor.Assoc(0, -1)
+	var first = cursor.Vector()
+	cursor.Assoc(1, -2)
+	var second = cursor.Vector()
+
+	if got := first.Nth(1); got != testSlice[1] {
+		t.Fatalf("got first.Nth(1)=%d, want %d", got, testSlice[1])
+	}
+	if got := second.Nth(0); got != -1 {
+		t.Fatalf("got second.Nth(0)=%d, want -1", got)
+	}
+	if got := second.Nth(1); got != -2 {
+		t.Fatalf("got second.Nth(1)=%d, want -2", got)
+	}
+}
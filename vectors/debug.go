@@ -0,0 +1,38 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// DebugTransients enables recording where each transient vector is
+// invalidated or made persistent, so that the panic from misusing a transient
+// vector names the call which invalidated or froze it. Recording the call
+// site slows down every mutation of a transient vector, so it is disabled by
+// default and is meant to be set while tracking down a misused transient.
+var DebugTransients = false
+
+// callSite describes the call made to the method skip frames above callSite,
+// naming the method and the file and line it was called from.
+func callSite(skip int) string {
+	var pcs [2]uintptr
+	if runtime.Callers(skip+1, pcs[:]) < 2 {
+		return "unknown caller"
+	}
+
+	var frames = runtime.CallersFrames(pcs[:])
+	var method, _ = frames.Next()
+	var caller, _ = frames.Next()
+
+	var name = method.Function
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return fmt.Sprintf("%s at %s:%d", name, caller.File, caller.Line)
+}
@@ -0,0 +1,67 @@
+package vectors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestDebugTransients(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		debug      bool
+		invalidate func(tvec *vectors.TransientVector[int])
+		use        func(tvec *vectors.TransientVector[int])
+		want       string
+	}{
+		{
+			name:       "Disabled",
+			debug:      false,
+			invalidate: func(tvec *vectors.TransientVector[int]) { tvec.Conj(1) },
+			use:        func(tvec *vectors.TransientVector[int]) { tvec.Len() },
+			want:       "attempted operation on an invalid transient vector",
+		},
+		{
+			name:       "Conj",
+			debug:      true,
+			invalidate: func(tvec *vectors.TransientVector[int]) { tvec.Conj(1) },
+			use:        func(tvec *vectors.TransientVector[int]) { tvec.Len() },
+			want:       "invalidated by Conj at ",
+		},
+		{
+			name:       "Persistent",
+			debug:      true,
+			invalidate: func(tvec *vectors.TransientVector[int]) { tvec.Persistent() },
+			use:        func(tvec *vectors.TransientVector[int]) { tvec.Conj(2) },
+			want:       "made persistent by Persistent at ",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			vectors.DebugTransients = tc.debug
+			defer func() { vectors.DebugTransients = false }()
+
+			var tvec = vectors.New(testSlice...).Transient()
+			var copied = tvec
+			tc.invalidate(&tvec)
+
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+				var msg = r.(string)
+				if !strings.Contains(msg, tc.want) {
+					t.Fatalf("got panic %q, want it to contain %q", msg, tc.want)
+				}
+				if tc.debug && !strings.Contains(msg, "debug_test.go:") {
+					t.Fatalf("got panic %q, want it to name the invalidating call site", msg)
+				}
+			}()
+			tc.use(&copied)
+		})
+	}
+}
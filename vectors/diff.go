@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import "fmt"
+
+// EditOp is the kind of change made to a vector by an Edit.
+type EditOp int
+
+const (
+	OpReplace EditOp = iota // Replace the value at Index with Value
+	OpInsert                // Insert Value at Index, shifting later values up
+	OpDelete                // Delete the value at Index, shifting later values down
+)
+
+// Edit is a single change to a vector. Edits are plain values so a sequence
+// of them can be stored or sent elsewhere to be replayed with Apply.
+type Edit[T any] struct {
+	Op    EditOp
+	Index int
+	Value T // Unused by OpDelete
+}
+
+// Diff returns a sequence of edits which transforms a into b when passed to
+// Apply. Values common to the start and end of both vectors are skipped, and
+// the values between them are replaced, inserted, or deleted by position, so
+// the result is small when a and b differ in one region but is not
+// necessarily the smallest possible sequence of edits. As with Equal, subtrees
+// shared by a and b are skipped without comparing their values.
+func Diff[T comparable](a, b Vector[T]) []Edit[T] {
+	return DiffFunc(a, b, func(x, y T) bool { return x == y })
+}
+
+// DiffFunc is like Diff, but uses eq to determine if two values are equal.
+func DiffFunc[T any](a, b Vector[T], eq func(T, T) bool) []Edit[T] {
+	var prefix = commonPrefix(a, b, eq)
+	var suffix = commonSuffix(a, b, min(a.count, b.count)-prefix, eq)
+
+	var aEnd, bEnd = a.count - suffix, b.count - suffix
+	var edits []Edit[T]
+
+	var i = prefix
+	for ; i < aEnd && i < bEnd; i++ {
+		edits = append(edits, Edit[T]{Op: OpReplace, Index: i, Value: b.Nth(i)})
+	}
+	for ; i < bEnd; i++ {
+		edits = append(edits, Edit[T]{Op: OpInsert, Index: i, Value: b.Nth(i)})
+	}
+	for j := i; j < aEnd; j++ {
+		edits = append(edits, Edit[T]{Op: OpDelete, Index: i})
+	}
+
+	return edits
+}
+
+// Apply creates a new vector by applying each of edits to v in order. The
+// index of each edit refers to the vector produced by the edits before it,
+// and must be within that vector; an insert may also be at its end. Edits are
+// applied through a Zipper, so edits in ascending order of index, such as those
+// returned by Diff, take time proportional to the length of v plus the number
+// of edits.
+func Apply[T any](v Vector[T], edits []Edit[T]) Vector[T] {
+	if len(edits) == 0 {
+		return v
+	}
+
+	var z = v.Zipper()
+	for _, edit := range edits {
+		var length = z.Len()
+		if edit.Op == OpInsert {
+			length += 1
+		}
+		if edit.Index < 0 || edit.Index >= length {
+			panic(fmt.Sprintf("index out of range [%d] with length %d", edit.Index, z.Len()))
+		}
+
+		for z.Pos() < edit.Index {
+			z.Right()
+		}
+		for z.Pos() > edit.Index {
+			z.Left()
+		}
+
+		switch edit.Op {
+		case OpReplace:
+			z.Set(edit.Value)
+		case OpInsert:
+			z.Insert(edit.Value)
+		case OpDelete:
+			z.Delete()
+		default:
+			panic(fmt.Sprintf("vectors: unknown edit op %d", edit.Op))
+		}
+	}
+
+	return z.Vector()
+}
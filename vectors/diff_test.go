@@ -0,0 +1,83 @@
+package vectors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestDiffApply(t *testing.T) {
+	var long = vectors.New(testSlice...)
+
+	var testCases = []struct {
+		name      string
+		a         vectors.Vector[int]
+		b         vectors.Vector[int]
+		wantEdits int
+	}{
+		{"Empty", vectors.New[int](), vectors.New[int](), 0},
+		{"Equal", long, long, 0},
+		{"FromEmpty", vectors.New[int](), vectors.New(1, 2, 3), 3},
+		{"ToEmpty", vectors.New(1, 2, 3), vectors.New[int](), 3},
+		{"Replace", long, long.Assoc(40, -1), 1},
+		{"Append", long, long.Conj(-1).Conj(-2), 2},
+		{"Insert", vectors.New(1, 2, 4, 5), vectors.New(1, 2, 3, 4, 5), 1},
+		{"Delete", vectors.New(1, 2, 3, 4, 5), vectors.New(1, 2, 4, 5), 1},
+		{"Middle", vectors.New(1, 2, 3, 4, 5), vectors.New(1, 9, 9, 9, 9, 5), 4},
+		{"Shrink", vectors.New(1, 2, 3, 4, 5), vectors.New(1, 9, 5), 3},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var edits = vectors.Diff(tc.a, tc.b)
+			if got, want := len(edits), tc.wantEdits; got != want {
+				t.Fatalf("got %d edits, want %d: %v", got, want, edits)
+			}
+			if got, want := vectors.Apply(tc.a, edits).String(), tc.b.String(); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	var v = vectors.New(1, 2, 3)
+	var got = vectors.Apply(v, []vectors.Edit[int]{
+		{Op: vectors.OpInsert, Index: 3, Value: 4},
+		{Op: vectors.OpDelete, Index: 0},
+		{Op: vectors.OpReplace, Index: 1, Value: 30},
+		{Op: vectors.OpInsert, Index: 0, Value: 0},
+	})
+	if got, want := got.String(), fmt.Sprintf("%v", []int{0, 2, 30, 4}); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := v.String(), "[1 2 3]"; got != want {
+		t.Fatalf("got original %s, want %s", got, want)
+	}
+}
+
+func TestApplyOutOfRange(t *testing.T) {
+	var testCases = []struct {
+		name string
+		edit vectors.Edit[int]
+	}{
+		{"ReplaceEnd", vectors.Edit[int]{Op: vectors.OpReplace, Index: 3}},
+		{"DeleteEnd", vectors.Edit[int]{Op: vectors.OpDelete, Index: 3}},
+		{"InsertPastEnd", vectors.Edit[int]{Op: vectors.OpInsert, Index: 4}},
+		{"Negative", vectors.Edit[int]{Op: vectors.OpInsert, Index: -1}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			vectors.Apply(vectors.New(1, 2, 3), []vectors.Edit[int]{tc.edit})
+		})
+	}
+}
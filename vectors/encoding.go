@@ -0,0 +1,120 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+
+	"github.com/toddgaunt/persistent/codec"
+)
+
+// GobEncode implements the gob.GobEncoder interface. The vector is encoded as
+// a gob encoded slice of its values.
+func (v Vector[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v.appendTo(make([]T, 0, v.count))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing the contents of
+// v with the vector encoded in data. The decoded values are packed directly
+// into full leaves rather than appended one at a time.
+func (v *Vector[T]) GobDecode(data []byte) error {
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return err
+	}
+	*v = fromSlice(values)
+	return nil
+}
+
+// binaryVersion is the version of the format written by MarshalBinary.
+const binaryVersion = 1
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The format
+// of the encoded vector is, in order:
+//
+//	version: a single byte, currently 1
+//	count:   the number of values as a uvarint
+//	values:  each value as a uvarint length followed by that many bytes
+//
+// Each value is encoded by codec.MarshalBinary, so values of a type with a
+// registered codec are converted to their representation first. Any type of
+// value which codec.MarshalBinary can't encode results in an error.
+func (v Vector[T]) MarshalBinary() ([]byte, error) {
+	var data = []byte{binaryVersion}
+	data = binary.AppendUvarint(data, uint64(v.count))
+
+	var err error
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			var b []byte
+			if b, err = codec.MarshalBinary(value); err != nil {
+				return false
+			}
+			data = binary.AppendUvarint(data, uint64(len(b)))
+			data = append(data, b...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// replacing the contents of v with the vector encoded in data using the format
+// described by MarshalBinary. The decoded values are packed directly into full
+// leaves rather than appended one at a time.
+func (v *Vector[T]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("vectors: missing binary format version")
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("vectors: unsupported binary format version %d", data[0])
+	}
+	data = data[1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("vectors: invalid value count")
+	}
+	data = data[n:]
+
+	// Every value takes at least a byte to encode its length, so this bounds
+	// the allocation below by the size of the input.
+	if count > uint64(len(data)) {
+		return fmt.Errorf("vectors: value count %d exceeds the encoded data", count)
+	}
+
+	var values = make([]T, count)
+	for i := range values {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return fmt.Errorf("vectors: invalid length for value %d", i)
+		}
+		data = data[n:]
+
+		if err := codec.UnmarshalBinary(data[:size], &values[i]); err != nil {
+			return fmt.Errorf("vectors: value %d: %w", i, err)
+		}
+		data = data[size:]
+	}
+
+	if len(data) > 0 {
+		return fmt.Errorf("vectors: %d trailing bytes after values", len(data))
+	}
+
+	*v = fromSlice(values)
+	return nil
+}
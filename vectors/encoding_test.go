@@ -0,0 +1,182 @@
+package vectors_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestVectorGob(t *testing.T) {
+	var deep = make([]int, 32*32*2+7)
+	for i := range deep {
+		deep[i] = i
+	}
+
+	var testCases = []struct {
+		name  string
+		slice []int
+	}{
+		{"Empty", []int{}},
+		{"Tail", []int{1, 2, 3}},
+		{"FullTail", deep[:32]},
+		{"Trie", deep[:65]},
+		{"DeepTrie", deep},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(vectors.New(tc.slice...)); err != nil {
+				t.Fatalf("got encode error %v", err)
+			}
+
+			var got vectors.Vector[int]
+			if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+				t.Fatalf("got decode error %v", err)
+			}
+
+			if got.Len() != len(tc.slice) {
+				t.Fatalf("got Len()=%d, want Len()=%d", got.Len(), len(tc.slice))
+			}
+			for i := range tc.slice {
+				if got.Nth(i) != tc.slice[i] {
+					t.Fatalf("want element %d at index %d, got %d", tc.slice[i], i, got.Nth(i))
+				}
+			}
+
+			// The decoded vector must have the same layout as one built with
+			// Conj, so it can be grown and hashed just the same.
+			var want = vectors.New(tc.slice...).Conj(-1)
+			if got, want := vectors.HashComparable(got.Conj(-1)), vectors.HashComparable(want); got != want {
+				t.Fatalf("got hash %d after Conj, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestVectorGobStruct(t *testing.T) {
+	type record struct {
+		Name  string
+		Items vectors.Vector[string]
+	}
+
+	var buf bytes.Buffer
+	var want = record{"fruit", vectors.New("apple", "banana")}
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatalf("got encode error %v", err)
+	}
+
+	var got record
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("got decode error %v", err)
+	}
+	if got.Name != want.Name || got.Items.String() != want.Items.String() {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestVectorBinary(t *testing.T) {
+	t.Run("Ints", func(t *testing.T) {
+		var want = vectors.New(testSlice...)
+		data, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("got marshal error %v", err)
+		}
+		var got vectors.Vector[int]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("got unmarshal error %v", err)
+		}
+		if got, want := got.String(), want.String(); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		var wrong vectors.Vector[int16]
+		if err := wrong.UnmarshalBinary(data); err == nil {
+			t.Fatalf("got nil error decoding ints as int16s, want error")
+		}
+	})
+
+	t.Run("Int64s", func(t *testing.T) {
+		var slice = make([]int64, 32*32+33)
+		for i := range slice {
+			slice[i] = int64(i) * -7
+		}
+		var want = vectors.New(slice...)
+		data, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("got marshal error %v", err)
+		}
+		var got vectors.Vector[int64]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("got unmarshal error %v", err)
+		}
+		if got, want := got.String(), want.String(); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("Strings", func(t *testing.T) {
+		var want = vectors.New("hello", "", "world")
+		data, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("got marshal error %v", err)
+		}
+		var got vectors.Vector[string]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("got unmarshal error %v", err)
+		}
+		if got, want := fmt.Sprintf("%q", got), fmt.Sprintf("%q", want); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		data, err := vectors.New[float64]().MarshalBinary()
+		if err != nil {
+			t.Fatalf("got marshal error %v", err)
+		}
+		if !bytes.Equal(data, []byte{1, 0}) {
+			t.Fatalf("got %v, want [1 0]", data)
+		}
+		var got = vectors.New(1.0)
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("got unmarshal error %v", err)
+		}
+		if got.Len() != 0 {
+			t.Fatalf("got Len()=%d, want Len()=0", got.Len())
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		if _, err := vectors.New(map[int]int{}).MarshalBinary(); err == nil {
+			t.Fatalf("got nil error marshaling maps, want error")
+		}
+	})
+}
+
+func TestVectorUnmarshalBinaryInvalid(t *testing.T) {
+	var testCases = []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", []byte{}},
+		{"Version", []byte{2, 0}},
+		{"MissingCount", []byte{1}},
+		{"CountTooLarge", []byte{1, 5, 1, 0}},
+		{"ShortValue", []byte{1, 1, 2, 0}},
+		{"Trailing", []byte{1, 0, 0}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var v vectors.Vector[uint8]
+			if err := v.UnmarshalBinary(tc.data); err == nil {
+				t.Fatalf("got nil error, want error")
+			}
+		})
+	}
+}
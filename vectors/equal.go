@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Equal returns true if a and b contain the same values in the same order,
+// analogous to slices.Equal from the standard Go slices package. Subtrees
+// which a and b share are skipped without comparing their values, so
+// comparing a vector with a version of itself takes time proportional to the
+// number of values that differ rather than to the length of the vectors.
+func Equal[T comparable](a, b Vector[T]) bool {
+	return EqualFunc(a, b, func(x, y T) bool { return x == y })
+}
+
+// EqualFunc is like Equal, but uses eq to determine if two values are equal.
+func EqualFunc[T any](a, b Vector[T], eq func(T, T) bool) bool {
+	return a.count == b.count && commonPrefix(a, b, eq) == a.count
+}
+
+// commonPrefix returns the number of values at the start of a and b which are
+// equal.
+func commonPrefix[T any](a, b Vector[T], eq func(T, T) bool) int {
+	var n = min(a.count, b.count)
+
+	var i = 0
+	for i < n {
+		if run := sharedRun(a, b, i, false); run > 0 {
+			i += min(run, n-i)
+			continue
+		}
+
+		var aValues, aOffset = a.LeafAt(i)
+		var bValues, bOffset = b.LeafAt(i)
+		var k = min(len(aValues)-aOffset, len(bValues)-bOffset, n-i)
+		for j := 0; j < k; j++ {
+			if !eq(aValues[aOffset+j], bValues[bOffset+j]) {
+				return i + j
+			}
+		}
+		i += k
+	}
+
+	return n
+}
+
+// commonSuffix returns the number of values at the end of a and b which are
+// equal, stopping once limit values have been found.
+func commonSuffix[T any](a, b Vector[T], limit int, eq func(T, T) bool) int {
+	var n = 0
+	for n < limit {
+		var ai, bi = a.count - 1 - n, b.count - 1 - n
+		if ai == bi {
+			if run := sharedRun(a, b, ai, true); run > 0 {
+				n += min(run, limit-n)
+				continue
+			}
+		}
+
+		var aValues, aOffset = a.LeafAt(ai)
+		var bValues, bOffset = b.LeafAt(bi)
+		var k = min(aOffset+1, bOffset+1, limit-n)
+		for j := 0; j < k; j++ {
+			if !eq(aValues[aOffset-j], bValues[bOffset-j]) {
+				return n + j
+			}
+		}
+		n += k
+	}
+
+	return n
+}
+
+// sharedRun returns the number of values at the same indices of a and b that
+// are known to be equal because they are stored in the very same node,
+// starting from index i and counting forwards, or backwards if backwards is
+// true. The run includes index i itself, and is zero if the value at index i
+// isn't shared.
+func sharedRun[T any](a, b Vector[T], i int, backwards bool) int {
+	var limit = min(a.count-len(a.tail), b.count-len(b.tail))
+	if a.depth != b.depth || i >= limit {
+		return 0
+	}
+
+	var na, nb = a.root, b.root
+	for level := a.depth; ; level -= 1 {
+		if na == nb {
+			// The node covers a span of values, aligned to its size.
+			var span = 1 << ((level + 1) * nodeBits)
+			var start = i &^ (span - 1)
+			if backwards {
+				return i - start + 1
+			}
+			return min(start+span, limit) - i
+		}
+		if level == 0 || na == nil || nb == nil {
+			return 0
+		}
+		na, nb = na.nodes[indexAt(level, i)], nb.nodes[indexAt(level, i)]
+	}
+}
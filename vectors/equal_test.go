@@ -0,0 +1,70 @@
+package vectors_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestEqual(t *testing.T) {
+	var slice = make([]int, 32*32*2+10)
+	for i := range slice {
+		slice[i] = i
+	}
+	var vec = vectors.New(slice...)
+
+	var testCases = []struct {
+		name string
+		a, b vectors.Vector[int]
+		want bool
+	}{
+		{"Empty", vectors.New[int](), vectors.New[int](), true},
+		{"Same", vec, vec, true},
+		{"Rebuilt", vec, vectors.New(slice...), true},
+		{"DifferentLength", vec, vec.Conj(1), false},
+		{"AssocTrie", vec, vec.Assoc(500, -1), false},
+		{"AssocTail", vec, vec.Assoc(len(slice)-1, -1), false},
+		{"AssocSameValue", vec, vec.Assoc(500, 500), true},
+		{"Prefix", vec.Truncate(100), vectors.New(slice[:100]...), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := vectors.Equal(tc.a, tc.b), tc.want; got != want {
+				t.Fatalf("got Equal()=%v, want %v", got, want)
+			}
+			if got, want := vectors.Equal(tc.b, tc.a), tc.want; got != want {
+				t.Fatalf("got reversed Equal()=%v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEqualFuncSkipsShared(t *testing.T) {
+	var vec = vectors.New(make([]int, 32*32*4)...)
+	var updated = vec.Assoc(1000, 1)
+
+	var calls = 0
+	var eq = func(x, y int) bool {
+		calls += 1
+		return x == y
+	}
+
+	if vectors.EqualFunc(vec, updated, eq) {
+		t.Fatalf("got equal vectors, want unequal")
+	}
+	// Only the leaf containing the changed value, and the tail, are compared.
+	if calls > 32 {
+		t.Fatalf("got %d calls to eq, want no more than 32", calls)
+	}
+
+	calls = 0
+	var edits = vectors.DiffFunc(vec, updated, eq)
+	if len(edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(edits))
+	}
+	if calls > 2*32+1 {
+		t.Fatalf("got %d calls to eq while diffing, want no more than %d", calls, 2*32+1)
+	}
+}
@@ -0,0 +1,14 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Mutating calls f while v is marked as being mutated, as it is for the
+// duration of Conj and the other methods which mutate a transient vector, so
+// tests can use v during a mutation as another goroutine could.
+func Mutating[T any](v *TransientVector[T], f func()) {
+	v.invalidate()
+	defer v.release()
+	f()
+}
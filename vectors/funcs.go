@@ -0,0 +1,159 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// FlatMap creates a new vector from the concatenation of the vectors returned
+// by calling f on each value of v in order. The result is built using a single
+// transient vector, so no intermediate persistent vectors are created.
+func FlatMap[T, U any](v Vector[T], f func(T) Vector[U]) Vector[U] {
+	var result = Vector[U]{}.Transient()
+
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			var inner = f(value)
+			forEachLeaf(inner.count, inner.depth, inner.root, inner.tail, func(innerValues []U) bool {
+				for _, innerValue := range innerValues {
+					result.Conj(innerValue)
+				}
+				return true
+			})
+		}
+		return true
+	})
+
+	return result.Persistent()
+}
+
+// Distinct creates a new vector containing the values of v with any duplicates
+// removed. The first occurrence of each value is kept, so the order of the
+// values in v is preserved.
+func Distinct[T comparable](v Vector[T]) Vector[T] {
+	return DistinctFunc(v, func(value T) T { return value })
+}
+
+// DistinctFunc is like Distinct, but two values are considered duplicates if
+// key returns the same result for both of them.
+func DistinctFunc[T any, K comparable](v Vector[T], key func(T) K) Vector[T] {
+	var seen = make(map[K]struct{})
+	var result = Vector[T]{}.Transient()
+
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			var k = key(value)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			result.Conj(value)
+		}
+		return true
+	})
+
+	return result.Persistent()
+}
+
+// Any returns true if pred returns true for at least one value of v. Values
+// are tested in order and no more values are tested once one satisfies pred.
+func Any[T any](v Vector[T], pred func(T) bool) bool {
+	return !forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			if pred(value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// All returns true if pred returns true for every value of v, which is always
+// the case for an empty vector. Values are tested in order and no more values
+// are tested once one fails to satisfy pred.
+func All[T any](v Vector[T], pred func(T) bool) bool {
+	return forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			if !pred(value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// CountIf returns the number of values of v for which pred returns true.
+func CountIf[T any](v Vector[T], pred func(T) bool) int {
+	var n = 0
+
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			if pred(value) {
+				n += 1
+			}
+		}
+		return true
+	})
+
+	return n
+}
+
+// Repeat creates a new vector containing n copies of value. Every full leaf
+// of the vector, and every full node above them, is shared so the vector
+// takes up memory proportional to its depth rather than its length. Repeat
+// panics if n is negative.
+func Repeat[T any](n int, value T) Vector[T] {
+	if n < 0 {
+		panic("vectors: negative Repeat count")
+	}
+
+	var tailStart = tailOffset(n)
+
+	var leaves = make([]*node[T], tailStart>>nodeBits)
+	if len(leaves) > 0 {
+		var values = make([]T, nodeWidth)
+		for i := range values {
+			values[i] = value
+		}
+		var leaf = newLeaf(persistent, values)
+		for i := range leaves {
+			leaves[i] = leaf
+		}
+	}
+
+	var tail = make([]T, n-tailStart)
+	for i := range tail {
+		tail[i] = value
+	}
+
+	return fromLeaves(leaves, tail)
+}
+
+// Interleave creates a new vector containing the first value of a, then the
+// first value of b, then the second value of a, and so on until the shorter of
+// a and b runs out of values. The result is built using a single transient
+// vector.
+func Interleave[T any](a, b Vector[T]) Vector[T] {
+	var result = Vector[T]{}.Transient()
+
+	var aSeq, bSeq = a.Seq(), b.Seq()
+	for !aSeq.IsEmpty() && !bSeq.IsEmpty() {
+		var aChunk, bChunk = aSeq.Chunk(), bSeq.Chunk()
+		var n = min(len(aChunk), len(bChunk))
+		for i := 0; i < n; i++ {
+			result.Conj(aChunk[i])
+			result.Conj(bChunk[i])
+		}
+		aSeq, bSeq = advance(aSeq, n), advance(bSeq, n)
+	}
+
+	return result.Persistent()
+}
+
+// advance returns the sequence following the first n values of s, where n is
+// no more than the length of s.Chunk().
+func advance[T any](s Seq[T], n int) Seq[T] {
+	if n == len(s.values) {
+		return s.ChunkRest()
+	}
+	return Seq[T]{vec: s.vec, index: s.index + n, values: s.values[n:]}
+}
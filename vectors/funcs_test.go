@@ -0,0 +1,227 @@
+package vectors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestFlatMap(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		slice []int
+		f     func(int) vectors.Vector[int]
+		want  []int
+	}{
+		{
+			name:  "Empty",
+			slice: []int{},
+			f:     func(x int) vectors.Vector[int] { return vectors.New(x, x) },
+			want:  []int{},
+		},
+		{
+			name:  "Duplicate",
+			slice: []int{1, 2, 3},
+			f:     func(x int) vectors.Vector[int] { return vectors.New(x, x) },
+			want:  []int{1, 1, 2, 2, 3, 3},
+		},
+		{
+			name:  "Drop",
+			slice: []int{1, 2, 3, 4},
+			f: func(x int) vectors.Vector[int] {
+				if x%2 == 0 {
+					return vectors.New[int]()
+				}
+				return vectors.New(x)
+			},
+			want: []int{1, 3},
+		},
+		{
+			name:  "DeepTrie",
+			slice: testSlice,
+			f:     func(x int) vectors.Vector[int] { return vectors.New(testSlice[:x]...) },
+			want: func() []int {
+				var want []int
+				for _, x := range testSlice {
+					want = append(want, testSlice[:x]...)
+				}
+				return want
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got = vectors.FlatMap(vectors.New(tc.slice...), tc.f)
+			if got.Len() != len(tc.want) {
+				t.Fatalf("got Len()=%d, want Len()=%d", got.Len(), len(tc.want))
+			}
+			if got, want := got.String(), fmt.Sprintf("%v", tc.want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		slice []int
+		want  []int
+	}{
+		{"Empty", []int{}, []int{}},
+		{"NoDuplicates", []int{1, 2, 3}, []int{1, 2, 3}},
+		{"Duplicates", []int{3, 1, 3, 2, 1, 3}, []int{3, 1, 2}},
+		{"DeepTrie", append(append([]int{}, testSlice...), testSlice...), testSlice},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got = vectors.Distinct(vectors.New(tc.slice...))
+			if got, want := got.String(), fmt.Sprintf("%v", tc.want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDistinctFunc(t *testing.T) {
+	var vec = vectors.New("apple", "Avocado", "banana", "cherry", "Blueberry")
+	var got = vectors.DistinctFunc(vec, func(s string) byte {
+		return s[0] | 0x20
+	})
+	if got, want := got.String(), "[apple banana cherry]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	var isEven = func(x int) bool { return x%2 == 0 }
+	var isPositive = func(x int) bool { return x > 0 }
+
+	var testCases = []struct {
+		name      string
+		slice     []int
+		pred      func(int) bool
+		wantAny   bool
+		wantAll   bool
+		wantCount int
+	}{
+		{"Empty", []int{}, isEven, false, true, 0},
+		{"NoneMatch", []int{1, 3, 5}, isEven, false, false, 0},
+		{"SomeMatch", []int{1, 2, 3, 4}, isEven, true, false, 2},
+		{"AllMatch", []int{2, 4, 6}, isEven, true, true, 3},
+		{"DeepTrieSomeMatch", testSlice, isEven, true, false, len(testSlice) / 2},
+		{"DeepTrieAllMatch", testSlice, isPositive, true, true, len(testSlice)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var vec = vectors.New(tc.slice...)
+			if got, want := vectors.Any(vec, tc.pred), tc.wantAny; got != want {
+				t.Fatalf("got Any()=%v, want Any()=%v", got, want)
+			}
+			if got, want := vectors.All(vec, tc.pred), tc.wantAll; got != want {
+				t.Fatalf("got All()=%v, want All()=%v", got, want)
+			}
+			if got, want := vectors.CountIf(vec, tc.pred), tc.wantCount; got != want {
+				t.Fatalf("got CountIf()=%v, want CountIf()=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestAnyShortCircuits(t *testing.T) {
+	var calls = 0
+	var vec = vectors.New(testSlice...)
+	vectors.Any(vec, func(x int) bool {
+		calls += 1
+		return x == 2
+	})
+	if calls != 2 {
+		t.Fatalf("got %d calls to pred, want 2", calls)
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	var testCases = []int{0, 1, 32, 33, 32*32 + 32, 32*32*3 + 5}
+
+	for _, n := range testCases {
+		n := n
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			var got = vectors.Repeat(n, "x")
+			var want = vectors.New(make([]string, n)...)
+			for i := 0; i < n; i++ {
+				want = want.Assoc(i, "x")
+			}
+
+			if got.Len() != n {
+				t.Fatalf("got Len()=%d, want Len()=%d", got.Len(), n)
+			}
+			if got, want := got.Stats(), want.Stats(); got.Depth != want.Depth || got.TailLen != want.TailLen {
+				t.Fatalf("got stats %+v, want stats %+v", got, want)
+			}
+			if got, want := got.String(), want.String(); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+
+			// Updating a shared leaf must only change a single index.
+			if n > 0 {
+				var updated = got.Assoc(0, "y")
+				if got, want := vectors.CountIf(updated, func(s string) bool { return s == "y" }), 1; got != want {
+					t.Fatalf("got %d updated values, want %d", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRepeatNegative(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.Repeat(-1, 0)
+}
+
+func TestInterleave(t *testing.T) {
+	var negated = make([]int, len(testSlice)-3)
+	for i := range negated {
+		negated[i] = -testSlice[i]
+	}
+
+	var testCases = []struct {
+		name string
+		a    []int
+		b    []int
+		want []int
+	}{
+		{"Empty", []int{}, []int{}, []int{}},
+		{"OneEmpty", []int{1, 2}, []int{}, []int{}},
+		{"SameLength", []int{1, 3, 5}, []int{2, 4, 6}, []int{1, 2, 3, 4, 5, 6}},
+		{"ShorterA", []int{1}, []int{2, 4, 6}, []int{1, 2}},
+		{"ShorterB", []int{1, 3, 5}, []int{2}, []int{1, 2}},
+		{"DeepTrie", testSlice, negated, func() []int {
+			var want []int
+			for i := range negated {
+				want = append(want, testSlice[i], negated[i])
+			}
+			return want
+		}()},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got = vectors.Interleave(vectors.New(tc.a...), vectors.New(tc.b...))
+			if got, want := got.String(), fmt.Sprintf("%v", tc.want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"hash/maphash"
+	"sync"
+	"unsafe"
+)
+
+// cachedHashes is the number of hashes, each computed with a different hash
+// function, cached within a node.
+const cachedHashes = 4
+
+// nodeHashes are the structural hashes cached within a node, each along with
+// the owner that computed it so hashes computed with different hash functions
+// aren't mixed up. The most recently cached hash is first.
+type nodeHashes [cachedHashes]struct {
+	owner any
+	sum   uint64
+}
+
+// combine mixes the hash x into the running hash h.
+func combine(h, x uint64) uint64 {
+	return h ^ (x + 0x9e3779b97f4a7c15 + (h << 6) + (h >> 2))
+}
+
+// Hasher computes structural hashes of vectors. The hash of every node in a
+// vector's trie is cached the first time it is computed, so hashing vectors
+// which share most of their structure with a previously hashed vector only
+// requires hashing the nodes that differ. Each node caches the hashes of only
+// the few Hashers and hash functions that most recently visited it, so a
+// single long-lived Hasher should be used for each kind of hash.
+type Hasher[T any] struct {
+	hash func(T) uint64
+}
+
+// NewHasher creates a new Hasher which uses hash to hash each value of a
+// vector.
+func NewHasher[T any](hash func(T) uint64) *Hasher[T] {
+	return &Hasher[T]{hash: hash}
+}
+
+// Hash returns the structural hash of v. Vectors with equal values in the
+// same order have the same hash.
+func (h *Hasher[T]) Hash(v Vector[T]) uint64 {
+	return hashVector(v, h, h.hash)
+}
+
+// Hash returns the structural hash of v, using hasher to hash each value.
+// Hashes are cached within the nodes of v for the function value hasher, as
+// with a Hasher, so passing the same function each time, such as a function
+// declared at the top level of a package, only hashes the nodes which changed
+// since the last call. A closure created anew for each call doesn't benefit
+// from the cache; a Hasher may be used for such a function instead.
+func Hash[T any](v Vector[T], hasher func(T) uint64) uint64 {
+	// A function value is a pointer to the closure holding the function and
+	// the variables it captured, which identifies the function. Holding the
+	// pointer in the cache keeps the closure from being reclaimed and its
+	// address reused by another function while hashes cached for it remain.
+	var owner = *(*unsafe.Pointer)(unsafe.Pointer(&hasher))
+	return hashVector(v, owner, hasher)
+}
+
+// hashVector returns the structural hash of v, caching the hashes of its
+// nodes as computed by owner.
+func hashVector[T any](v Vector[T], owner any, hash func(T) uint64) uint64 {
+	var sum = combine(0, uint64(v.count))
+	if v.root != nil {
+		sum = combine(sum, hashNode(v.root, v.depth, owner, hash))
+	}
+	return combine(sum, hashValues(v.tail, hash))
+}
+
+func hashValues[T any](values []T, hash func(T) uint64) uint64 {
+	var sum uint64
+	for _, value := range values {
+		sum = combine(sum, hash(value))
+	}
+	return sum
+}
+
+func hashNode[T any](n *node[T], level int, owner any, hash func(T) uint64) uint64 {
+	var cached = n.hash.Load()
+	if cached != nil {
+		for i := range cached {
+			if cached[i].owner == owner {
+				return cached[i].sum
+			}
+		}
+	}
+
+	var sum uint64
+	if level == 0 {
+		sum = hashValues(n.values, hash)
+	} else {
+		for _, child := range n.nodes {
+			if child == nil {
+				break
+			}
+			sum = combine(sum, hashNode(child, level-1, owner, hash))
+		}
+	}
+
+	// Nodes reachable from a persistent vector are never mutated, even if they
+	// were made by a transient vector, so their hash is safe to cache. The
+	// least recently cached hash makes room for the new one.
+	var hashes nodeHashes
+	hashes[0].owner, hashes[0].sum = owner, sum
+	if cached != nil {
+		copy(hashes[1:], cached[:])
+	}
+	n.hash.Store(&hashes)
+
+	return sum
+}
+
+var seed = maphash.MakeSeed()
+
+// HashComparable returns the structural hash of v, hashing each value with
+// hash/maphash using a seed chosen randomly for the process. Hashes are
+// cached within the nodes of v, making repeated hashing of vectors that share
+// structure cheap.
+func HashComparable[T comparable](v Vector[T]) uint64 {
+	return comparableHasher[T]().Hash(v)
+}
+
+// comparableHashers holds the single Hasher used for each comparable type so
+// that HashComparable can reuse hashes cached by previous calls.
+var comparableHashers sync.Map
+
+func comparableHasher[T comparable]() *Hasher[T] {
+	var key *T
+	if h, ok := comparableHashers.Load(key); ok {
+		return h.(*Hasher[T])
+	}
+
+	h, _ := comparableHashers.LoadOrStore(key, NewHasher(func(value T) uint64 {
+		return maphash.Comparable(seed, value)
+	}))
+	return h.(*Hasher[T])
+}
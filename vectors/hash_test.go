@@ -0,0 +1,126 @@
+package vectors_test
+
+import (
+	"hash/maphash"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestHashComparable(t *testing.T) {
+	var deep = make([]int, 32*32+40)
+	for i := range deep {
+		deep[i] = i
+	}
+
+	var testCases = []struct {
+		name  string
+		a     vectors.Vector[int]
+		b     vectors.Vector[int]
+		equal bool
+	}{
+		{"Empty", vectors.New[int](), vectors.New[int](), true},
+		{"Equal", vectors.New(1, 2, 3), vectors.New(1, 2, 3), true},
+		{"Differ", vectors.New(1, 2, 3), vectors.New(1, 2, 4), false},
+		{"Order", vectors.New(1, 2, 3), vectors.New(3, 2, 1), false},
+		{"Length", vectors.New(0, 0), vectors.New(0, 0, 0), false},
+		{"DeepTrieEqual", vectors.New(deep...), vectors.New(deep...), true},
+		{"DeepTrieAssoc", vectors.New(deep...), vectors.New(deep...).Assoc(7, -1), false},
+		{"DeepTrieConj", vectors.New(deep...).Conj(1), vectors.New(append(deep, 1)...), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// Hash twice to exercise the cached hashes too.
+			for i := 0; i < 2; i++ {
+				var a, b = vectors.HashComparable(tc.a), vectors.HashComparable(tc.b)
+				if got, want := a == b, tc.equal; got != want {
+					t.Fatalf("got equal hashes=%v, want equal hashes=%v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHasherCachesPerHasher(t *testing.T) {
+	var seed = maphash.MakeSeed()
+	var vec = vectors.New(testSlice...)
+
+	var byValue = vectors.NewHasher(func(x int) uint64 { return maphash.Comparable(seed, x) })
+	var constant = vectors.NewHasher(func(x int) uint64 { return 1 })
+
+	var a = byValue.Hash(vec)
+	var b = constant.Hash(vec)
+	if a == b {
+		t.Fatalf("got equal hashes from different hashers")
+	}
+	if got := byValue.Hash(vec); got != a {
+		t.Fatalf("got %d after hashing with a different hasher, want %d", got, a)
+	}
+	if got := vectors.Hash(vec, func(x int) uint64 { return 1 }); got != b {
+		t.Fatalf("got %d from Hash, want %d", got, b)
+	}
+}
+
+func TestHashKeepsHasherCache(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+	var calls = 0
+	var hasher = vectors.NewHasher(func(x int) uint64 {
+		calls += 1
+		return uint64(x)
+	})
+
+	var want = hasher.Hash(vec)
+	if got := vectors.Hash(vec, func(x int) uint64 { return uint64(x) }); got != want {
+		t.Fatalf("got %d from Hash, want %d from a Hasher", got, want)
+	}
+
+	// Only the values in the tail are hashed again if Hash left the cached
+	// hashes of the nodes alone.
+	calls = 0
+	hasher.Hash(vec)
+	if want := vec.Len() % 32; calls != want {
+		t.Fatalf("got %d values hashed again, want %d", calls, want)
+	}
+}
+
+func TestHashAllocs(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+	var hash = func(x int) uint64 { return uint64(x) }
+	if allocs := testing.AllocsPerRun(100, func() { vectors.Hash(vec, hash) }); allocs != 0 {
+		t.Fatalf("got %v allocations per Hash, want 0", allocs)
+	}
+}
+
+func TestHashCaches(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+	var calls = 0
+	var hash = func(x int) uint64 {
+		calls += 1
+		return uint64(x)
+	}
+
+	var want = vectors.Hash(vec, hash)
+	calls = 0
+	if got := vectors.Hash(vec, hash); got != want {
+		t.Fatalf("got %d from the cached hashes, want %d", got, want)
+	}
+	if want := vec.Len() % 32; calls != want {
+		t.Fatalf("got %d values hashed again, want %d", calls, want)
+	}
+
+	// Only the leaf on the path to the changed value, and the tail, are
+	// hashed for a vector sharing the rest of its nodes.
+	calls = 0
+	var changed = vec.Assoc(0, -1)
+	if got := vectors.Hash(changed, hash); got == want {
+		t.Fatalf("got the same hash %d for a changed vector", got)
+	}
+	if want := 32 + vec.Len()%32; calls != want {
+		t.Fatalf("got %d values hashed, want %d", calls, want)
+	}
+	if got, want := vectors.Hash(changed, hash), vectors.NewHasher(hash).Hash(changed); got != want {
+		t.Fatalf("got %d from Hash, want %d from a Hasher", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Heap adapts a transient vector to the heap.Interface interface, so the
+// functions of the standard container/heap package can maintain a priority
+// queue backed by a vector. A persistent snapshot of the heap can be taken at
+// any point with Persistent, which makes the heap usable as a versioned
+// priority queue.
+type Heap[T any] struct {
+	tv   TransientVector[T]
+	less func(a, b T) bool
+}
+
+// NewHeap creates a new Heap over the values of tv ordered by less, with the
+// least value at the top of the heap. The values of tv must already satisfy
+// the heap invariants, or heap.Init must be called before using the heap. The
+// Heap takes ownership of tv, which must not be used afterwards.
+func NewHeap[T any](tv TransientVector[T], less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{tv: tv, less: less}
+}
+
+// Len returns the number of values in the heap.
+func (h *Heap[T]) Len() int {
+	return h.tv.Len()
+}
+
+// Less reports whether the value at index i should be ordered before the value
+// at index j.
+func (h *Heap[T]) Less(i, j int) bool {
+	return h.less(h.tv.Nth(i), h.tv.Nth(j))
+}
+
+// Swap exchanges the values at indices i and j.
+func (h *Heap[T]) Swap(i, j int) {
+	var a, b = h.tv.Nth(i), h.tv.Nth(j)
+	h.tv.Assoc(i, b)
+	h.tv.Assoc(j, a)
+}
+
+// Push appends x, which must be a T, to the end of the vector. It is meant to
+// be called by heap.Push rather than directly.
+func (h *Heap[T]) Push(x any) {
+	h.tv.Conj(x.(T))
+}
+
+// Pop removes and returns the last value of the vector. It is meant to be
+// called by heap.Pop rather than directly.
+func (h *Heap[T]) Pop() any {
+	var last = h.tv.Peek()
+	h.tv.Pop()
+	return last
+}
+
+// Persistent returns a persistent vector of the values of the heap in their
+// current order. The heap may continue to be used afterwards without
+// affecting the returned vector.
+func (h *Heap[T]) Persistent() Vector[T] {
+	var v = h.tv.Persistent()
+	h.tv = v.Transient()
+	return v
+}
@@ -0,0 +1,45 @@
+package vectors_test
+
+import (
+	"container/heap"
+	"sort"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestHeap(t *testing.T) {
+	var values = []int{5, 3, 9, 1, 7, 2, 8, 6, 4, 0}
+	for i := 0; i < 100; i++ {
+		values = append(values, (i*37)%101)
+	}
+
+	var h = vectors.NewHeap(vectors.New(values[:5]...).Transient(), func(a, b int) bool { return a < b })
+	heap.Init(h)
+	for _, value := range values[5:] {
+		heap.Push(h, value)
+	}
+
+	var snapshot = h.Persistent()
+
+	var want = append([]int{}, values...)
+	sort.Ints(want)
+	for i := range want {
+		if got := heap.Pop(h).(int); got != want[i] {
+			t.Fatalf("got %d popped from the heap, want %d", got, want[i])
+		}
+	}
+	if h.Len() != 0 {
+		t.Fatalf("got Len()=%d after popping everything, want 0", h.Len())
+	}
+
+	// The snapshot is unaffected by popping from the heap, and can be used to
+	// make a new heap from the same point.
+	if got, want := snapshot.Len(), len(values); got != want {
+		t.Fatalf("got snapshot Len()=%d, want %d", got, want)
+	}
+	var restored = vectors.NewHeap(snapshot.Transient(), func(a, b int) bool { return a < b })
+	if got := heap.Pop(restored).(int); got != want[0] {
+		t.Fatalf("got %d popped from the restored heap, want %d", got, want[0])
+	}
+}
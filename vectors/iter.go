@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Enumerate returns an iterator over the index and value of each value of v,
+// beginning at index start. The tree is walked once per leaf rather than once
+// per value. The start index must be between zero and v.Len(), inclusive.
+func (v Vector[T]) Enumerate(start int) iter.Seq2[int, T] {
+	if start < 0 || start > v.count {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", start, v.count))
+	}
+
+	return func(yield func(int, T) bool) {
+		var index = start
+		for index < v.count {
+			var values = findValues(v.count, v.depth, v.root, v.tail, index)
+			for _, value := range values[indexAt(0, index):] {
+				if !yield(index, value) {
+					return
+				}
+				index += 1
+			}
+		}
+	}
+}
+
+// All returns an iterator over the index and value of each value of v.
+func (v Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		var index = 0
+		forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+			for _, value := range values {
+				if !yield(index, value) {
+					return false
+				}
+				index += 1
+			}
+			return true
+		})
+	}
+}
+
+// Values returns an iterator over each value of v.
+func (v Vector[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+			for _, value := range values {
+				if !yield(value) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// All returns an iterator over the index and value of each value of v. The
+// iterator reads from v, so it panics if v is invalidated by being mutated
+// before or during iteration.
+func (v TransientVector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		var index = 0
+		v.ensureValid()
+		forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+			for _, value := range values {
+				if !yield(index, value) {
+					return false
+				}
+				v.ensureValid()
+				index += 1
+			}
+			return true
+		})
+	}
+}
+
+// Values returns an iterator over each value of v. The iterator reads from v,
+// so it panics if v is invalidated by being mutated before or during
+// iteration.
+func (v TransientVector[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range v.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package vectors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestVectorEnumerate(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+
+	for _, start := range []int{0, 1, 31, 32, 40, len(testSlice)} {
+		var want = start
+		for i, value := range vec.Enumerate(start) {
+			if i != want {
+				t.Fatalf("got index %d, want index %d", i, want)
+			}
+			if value != testSlice[i] {
+				t.Fatalf("want element %d at index %d, got %d", testSlice[i], i, value)
+			}
+			want += 1
+		}
+		if want != len(testSlice) {
+			t.Fatalf("got iteration ending at %d, want %d", want, len(testSlice))
+		}
+	}
+}
+
+func TestVectorEnumerateBreak(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+	var count = 0
+	for i := range vec.Enumerate(10) {
+		if i == 40 {
+			break
+		}
+		count += 1
+	}
+	if count != 30 {
+		t.Fatalf("got %d values before break, want 30", count)
+	}
+}
+
+func TestVectorEnumerateOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).Enumerate(4)
+}
+
+func TestVectorAll(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+
+	var want = 0
+	for i, value := range vec.All() {
+		if i != want {
+			t.Fatalf("got index %d, want index %d", i, want)
+		}
+		if value != testSlice[i] {
+			t.Fatalf("want element %d at index %d, got %d", testSlice[i], i, value)
+		}
+		want += 1
+	}
+	if want != len(testSlice) {
+		t.Fatalf("got iteration ending at %d, want %d", want, len(testSlice))
+	}
+}
+
+func TestVectorValues(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+
+	var got []int
+	for value := range vec.Values() {
+		if value > 40 {
+			break
+		}
+		got = append(got, value)
+	}
+	if got, want := fmt.Sprint(got), fmt.Sprint(testSlice[:40]); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestTransientVectorAll(t *testing.T) {
+	var tvec = vectors.New(testSlice...).Transient()
+
+	var want = 0
+	for i, value := range tvec.All() {
+		if i != want {
+			t.Fatalf("got index %d, want index %d", i, want)
+		}
+		if value != testSlice[i] {
+			t.Fatalf("want element %d at index %d, got %d", testSlice[i], i, value)
+		}
+		want += 1
+	}
+	if want != len(testSlice) {
+		t.Fatalf("got iteration ending at %d, want %d", want, len(testSlice))
+	}
+
+	// Iterating is a read, so the transient is still valid afterwards.
+	tvec.Conj(66)
+}
+
+func TestTransientVectorValues(t *testing.T) {
+	var tvec = vectors.New(testSlice...).Transient()
+
+	var got []int
+	for value := range tvec.Values() {
+		got = append(got, value)
+	}
+	if got, want := fmt.Sprint(got), fmt.Sprint(testSlice); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestTransientVectorValuesMutated(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+
+	var tvec = vectors.New(testSlice...).Transient()
+	for value := range tvec.Values() {
+		tvec.Assoc(0, value)
+	}
+}
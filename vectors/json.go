@@ -0,0 +1,90 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/toddgaunt/persistent/codec"
+)
+
+// MarshalJSON implements the json.Marshaler interface. The vector is encoded
+// as a JSON array of its values, with an empty vector encoded as [] rather
+// than null. Values of a type with a codec registered in the codec package
+// are encoded as their representation.
+func (v Vector[T]) MarshalJSON() ([]byte, error) {
+	if !codec.Applies[T]() {
+		return json.Marshal(v.appendTo(make([]T, 0, v.count)))
+	}
+
+	var reprs = make([]any, 0, v.count)
+	for _, value := range v.All() {
+		var repr, err = codec.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("vectors: value %d: %w", len(reprs), err)
+		}
+		reprs = append(reprs, repr)
+	}
+	return json.Marshal(reprs)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, replacing the
+// contents of v with the values of a JSON array. JSON null decodes to an empty
+// vector. The decoded values are packed directly into full leaves rather than
+// appended one at a time, unless a codec is registered for T in the codec
+// package, in which case each value is decoded from its representation.
+func (v *Vector[T]) UnmarshalJSON(data []byte) error {
+	if codec.For[T]() != nil {
+		var decoded, err = DecodeJSON[T](json.NewDecoder(bytes.NewReader(data)))
+		if err != nil {
+			return err
+		}
+		*v = decoded
+		return nil
+	}
+
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = fromSlice(values)
+	return nil
+}
+
+// DecodeJSON reads the next JSON array from dec and returns a vector of its
+// values. Each value is decoded with dec straight into a transient vector as
+// it is read, so a large array is never held in full as a slice or as raw
+// JSON. Values are decoded from their representation if a codec is
+// registered for T in the codec package. JSON null decodes to an empty vector.
+func DecodeJSON[T any](dec *json.Decoder) (Vector[T], error) {
+	var tok, err = dec.Token()
+	if err != nil {
+		return Vector[T]{}, err
+	}
+	if tok == nil {
+		return Vector[T]{}, nil
+	}
+	if tok != json.Delim('[') {
+		return Vector[T]{}, fmt.Errorf("vectors: got JSON %v, want an array", tok)
+	}
+
+	var tv TransientVector[T]
+	for dec.More() {
+		var value T
+		if err := codec.DecodeWith(&value, dec.Decode); err != nil {
+			return Vector[T]{}, fmt.Errorf("vectors: value %d: %w", tv.Len(), err)
+		}
+		tv.Conj(value)
+	}
+
+	// The closing bracket is all that's left, since dec checks that the
+	// array is well formed.
+	if _, err := dec.Token(); err != nil {
+		return Vector[T]{}, err
+	}
+	return tv.Persistent(), nil
+}
@@ -0,0 +1,132 @@
+package vectors_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestVectorJSON(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		vector vectors.Vector[vectors.Vector[int]]
+		want   string
+	}{
+		{"Empty", vectors.New[vectors.Vector[int]](), "[]"},
+		{"Nested", vectors.New(vectors.New(1, 2), vectors.New[int]()), "[[1,2],[]]"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var data, err = json.Marshal(tc.vector)
+			if err != nil {
+				t.Fatalf("got marshal error %v", err)
+			}
+			if got := string(data); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+
+			var got vectors.Vector[vectors.Vector[int]]
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("got unmarshal error %v", err)
+			}
+			if got.String() != tc.vector.String() {
+				t.Fatalf("got %v, want %v", got, tc.vector)
+			}
+		})
+	}
+}
+
+func TestVectorJSONLarge(t *testing.T) {
+	var want = vectors.New[int]()
+	for i := 0; i < 32*32+5; i++ {
+		want = want.Conj(i)
+	}
+	var data, err = json.Marshal(want)
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+
+	var got vectors.Vector[int]
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if !vectors.Equal(got, want) {
+		t.Fatalf("got %d values, want %d values", got.Len(), want.Len())
+	}
+}
+
+func TestVectorJSONErrors(t *testing.T) {
+	for _, data := range []string{`{"a":1}`, `[1,"x"]`, `[1`} {
+		var v vectors.Vector[int]
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			t.Fatalf("got %v and nil error for %s, want an error", v, data)
+		}
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString(`{"items": [`)
+	for i := 0; i < 32*32+5; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.Itoa(i))
+	}
+	sb.WriteString(`], "empty": null}`)
+
+	var dec = json.NewDecoder(strings.NewReader(sb.String()))
+	for _, want := range []json.Token{json.Delim('{'), "items"} {
+		if tok, err := dec.Token(); err != nil || tok != want {
+			t.Fatalf("got token %v, %v, want %v, nil", tok, err, want)
+		}
+	}
+
+	var items, err = vectors.DecodeJSON[int](dec)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := items.Len(), 32*32+5; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	for i := range items.Len() {
+		if items.Nth(i) != i {
+			t.Fatalf("want element %d at index %d, got %d", i, i, items.Nth(i))
+		}
+	}
+
+	if tok, err := dec.Token(); err != nil || tok != "empty" {
+		t.Fatalf("got token %v, %v, want empty, nil", tok, err)
+	}
+	empty, err := vectors.DecodeJSON[int](dec)
+	if err != nil || empty.Len() != 0 {
+		t.Fatalf("got %v, %v, want [], nil", empty, err)
+	}
+}
+
+func TestDecodeJSONStream(t *testing.T) {
+	var dec = json.NewDecoder(strings.NewReader(`["a"] ["b", "c"] []`))
+	for _, want := range []string{"[a]", "[b c]", "[]"} {
+		var got, err = vectors.DecodeJSON[string](dec)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got.String() != want {
+			t.Fatalf("got %v, want %s", got, want)
+		}
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	for _, data := range []string{``, `{"a":1}`, `1`, `[1,"x"]`, `[1`, `[1,]`} {
+		var dec = json.NewDecoder(strings.NewReader(data))
+		if v, err := vectors.DecodeJSON[int](dec); err == nil {
+			t.Fatalf("got %v and nil error for %s, want an error", v, data)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import "iter"
+
+// FromList creates a new persistent vector containing the items of l in order,
+// from the head of the list to the end. The list is usually a lists.List, but
+// any value with the same Len and All methods is accepted, since the lists
+// package depends on this one for lists.FromVector.
+func FromList[T any](l interface {
+	Len() int
+	All() iter.Seq[T]
+}) Vector[T] {
+	var tv = Vector[T]{}.Transient()
+	tv.Reserve(l.Len())
+	for item := range l.All() {
+		tv.Conj(item)
+	}
+	return tv.Persistent()
+}
@@ -0,0 +1,22 @@
+package vectors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestFromList(t *testing.T) {
+	for _, n := range []int{0, 1, 32, 33, len(testSlice)} {
+		var got = vectors.FromList(lists.New(testSlice[:n]...))
+		var want = vectors.New(testSlice[:n]...)
+		if !vectors.Equal(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := got.Stats(), want.Stats(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got stats %+v, want %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Seq is a lazy sequence view over the values of a vector, similar to a
+// Clojure seq. Like a list, a Seq is consumed with First and Rest, but it
+// shares all of its memory with the vector it was made from and only walks the
+// tree when moving from one leaf to the next. Seq values are persistent, so
+// any Seq may be used again after calling Rest on it.
+//
+// A Seq is also chunked: Chunk returns the rest of the values in the current
+// leaf at once, and ChunkRest skips past them, which allows values to be
+// processed a leaf at a time.
+type Seq[T any] struct {
+	vec    Vector[T]
+	index  int // Index within vec of the first value of the sequence
+	values []T // The rest of the leaf containing index
+}
+
+// Seq creates a new sequence view over the values of v, in order.
+func (v Vector[T]) Seq() Seq[T] {
+	return newSeq(v, 0)
+}
+
+func newSeq[T any](v Vector[T], index int) Seq[T] {
+	var s = Seq[T]{vec: v, index: index}
+	if index < v.count {
+		s.values = findValues(v.count, v.depth, v.root, v.tail, index)[indexAt(0, index):]
+	}
+	return s
+}
+
+// IsEmpty returns true if there are no values left in the sequence.
+func (s Seq[T]) IsEmpty() bool {
+	return len(s.values) == 0
+}
+
+// Len returns the number of values left in the sequence.
+func (s Seq[T]) Len() int {
+	return s.vec.count - s.index
+}
+
+// First returns the first value of the sequence, or the zero value of T if
+// the sequence is empty.
+func (s Seq[T]) First() T {
+	if len(s.values) == 0 {
+		var zero T
+		return zero
+	}
+	return s.values[0]
+}
+
+// Rest returns a sequence of all but the first value of s. The rest of an
+// empty sequence is also empty.
+func (s Seq[T]) Rest() Seq[T] {
+	if len(s.values) == 0 {
+		return s
+	}
+	if len(s.values) > 1 {
+		return Seq[T]{vec: s.vec, index: s.index + 1, values: s.values[1:]}
+	}
+	return newSeq(s.vec, s.index+1)
+}
+
+// Chunk returns the values from the first value of the sequence up to the end
+// of the leaf containing it. The returned slice shares memory with the vector
+// and must not be modified.
+func (s Seq[T]) Chunk() []T {
+	return s.values[:len(s.values):len(s.values)]
+}
+
+// ChunkRest returns a sequence of the values following those returned by
+// Chunk.
+func (s Seq[T]) ChunkRest() Seq[T] {
+	return newSeq(s.vec, s.index+len(s.values))
+}
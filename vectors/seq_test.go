@@ -0,0 +1,67 @@
+package vectors_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestSeq(t *testing.T) {
+	var seq = vectors.New(testSlice...).Seq()
+	for i := 0; i < len(testSlice); i++ {
+		if got, want := seq.Len(), len(testSlice)-i; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		if seq.IsEmpty() {
+			t.Fatalf("got empty seq at index %d", i)
+		}
+		if got := seq.First(); got != testSlice[i] {
+			t.Fatalf("want element %d at index %d, got %d", testSlice[i], i, got)
+		}
+		seq = seq.Rest()
+	}
+
+	if !seq.IsEmpty() {
+		t.Fatalf("got non-empty seq after the last value")
+	}
+	if got := seq.First(); got != 0 {
+		t.Fatalf("got First()=%d of an empty seq, want 0", got)
+	}
+	if !seq.Rest().IsEmpty() {
+		t.Fatalf("got non-empty Rest() of an empty seq")
+	}
+}
+
+func TestSeqPersistent(t *testing.T) {
+	var seq = vectors.New(1, 2, 3).Seq()
+	var rest = seq.Rest()
+	if got := seq.First(); got != 1 {
+		t.Fatalf("got First()=%d after calling Rest, want 1", got)
+	}
+	if got := rest.First(); got != 2 {
+		t.Fatalf("got Rest().First()=%d, want 2", got)
+	}
+}
+
+func TestSeqChunks(t *testing.T) {
+	var seq = vectors.New(testSlice...).Seq().Rest()
+
+	var got []int
+	var chunks = 0
+	for ; !seq.IsEmpty(); seq = seq.ChunkRest() {
+		got = append(got, seq.Chunk()...)
+		chunks += 1
+	}
+
+	if chunks != 3 {
+		t.Fatalf("got %d chunks, want 3", chunks)
+	}
+	if len(got) != len(testSlice)-1 {
+		t.Fatalf("got %d values, want %d", len(got), len(testSlice)-1)
+	}
+	for i := range got {
+		if got[i] != testSlice[i+1] {
+			t.Fatalf("want element %d at index %d, got %d", testSlice[i+1], i, got[i])
+		}
+	}
+}
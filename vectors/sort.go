@@ -0,0 +1,45 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Sorter adapts a transient vector to the sort.Interface interface, so the
+// functions of the standard sort package can reorder the values of a vector
+// in place. The Sorter swaps values by updating its transient vector in place,
+// and since copies of a transient vector are invalidated when it changes, the
+// reordered vector must be retrieved afterwards with Transient.
+type Sorter[T any] struct {
+	tv   TransientVector[T]
+	less func(a, b T) bool
+}
+
+// Sortable creates a new Sorter which orders the values of tv using less. The
+// Sorter takes ownership of tv, which must not be used afterwards.
+func Sortable[T any](tv TransientVector[T], less func(a, b T) bool) *Sorter[T] {
+	return &Sorter[T]{tv: tv, less: less}
+}
+
+// Len returns the number of values in the vector being sorted.
+func (s *Sorter[T]) Len() int {
+	return s.tv.Len()
+}
+
+// Less reports whether the value at index i should be ordered before the value
+// at index j.
+func (s *Sorter[T]) Less(i, j int) bool {
+	return s.less(s.tv.Nth(i), s.tv.Nth(j))
+}
+
+// Swap exchanges the values at indices i and j.
+func (s *Sorter[T]) Swap(i, j int) {
+	var a, b = s.tv.Nth(i), s.tv.Nth(j)
+	s.tv.Assoc(i, b)
+	s.tv.Assoc(j, a)
+}
+
+// Transient returns the transient vector containing the values as reordered
+// so far. The Sorter must not be used afterwards.
+func (s *Sorter[T]) Transient() TransientVector[T] {
+	return s.tv
+}
@@ -0,0 +1,32 @@
+package vectors_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestSortable(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+	var slice = make([]int, 32*32+50)
+	for i := range slice {
+		slice[i] = rng.Intn(1000)
+	}
+
+	var vec = vectors.New(slice...)
+	var sorter = vectors.Sortable(vec.Transient(), func(a, b int) bool { return a < b })
+	sort.Sort(sorter)
+	var sorted = sorter.Transient().Persistent()
+
+	var want = append([]int{}, slice...)
+	sort.Ints(want)
+	if got, want := sorted.String(), fmt.Sprintf("%v", want); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := vec.String(), fmt.Sprintf("%v", slice); got != want {
+		t.Fatalf("got original %s, want %s", got, want)
+	}
+}
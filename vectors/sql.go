@@ -0,0 +1,39 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements the driver.Valuer interface, so a vector can be stored in
+// a JSON or JSONB column. The vector is encoded as by MarshalJSON and returned
+// as a string, which drivers pass to the database as text rather than as
+// binary data.
+func (v Vector[T]) Value() (driver.Value, error) {
+	var data, err = v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface, replacing the contents of v with
+// the vector encoded as JSON in src, which may be a string or a byte slice. A
+// NULL column scans to an empty vector.
+func (v *Vector[T]) Scan(src any) error {
+	switch x := src.(type) {
+	case nil:
+		*v = Vector[T]{}
+		return nil
+	case string:
+		return v.UnmarshalJSON([]byte(x))
+	case []byte:
+		return v.UnmarshalJSON(x)
+	default:
+		return fmt.Errorf("vectors: cannot scan %T into a vector", src)
+	}
+}
@@ -0,0 +1,51 @@
+package vectors_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestVectorValue(t *testing.T) {
+	var got, err = vectors.New("a", "b").Value()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if want := `["a","b"]`; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestVectorScan(t *testing.T) {
+	var testCases = []struct {
+		name string
+		src  any
+		want string
+	}{
+		{"Null", nil, "[]"},
+		{"String", `["a","b"]`, "[a b]"},
+		{"Bytes", []byte(`["c"]`), "[c]"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var v = vectors.New("old")
+			if err := v.Scan(tc.src); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got := v.String(); got != tc.want {
+				t.Fatalf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVectorScanErrors(t *testing.T) {
+	for _, src := range []any{1, `{"a":1}`, []byte("[1]")} {
+		var v vectors.Vector[string]
+		if err := v.Scan(src); err == nil {
+			t.Fatalf("got %v and nil error scanning %v, want an error", v, src)
+		}
+	}
+}
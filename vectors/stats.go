@@ -0,0 +1,53 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Stats describes the shape of the tree backing a vector, which is useful for
+// debugging and for understanding the memory used by a vector.
+type Stats struct {
+	Len     int   // Number of values in the vector
+	Depth   int   // Depth of the tree under the root
+	Nodes   []int // Number of nodes at each level, with leaves at level 0
+	TailLen int   // Number of values in the tail rather than the tree
+}
+
+func newStats[T any](count, depth int, root *node[T], tail []T) Stats {
+	var s = Stats{
+		Len:     count,
+		Depth:   depth,
+		TailLen: len(tail),
+	}
+
+	if root != nil {
+		s.Nodes = make([]int, depth+1)
+		countNodes(root, depth, s.Nodes)
+	}
+
+	return s
+}
+
+func countNodes[T any](n *node[T], level int, counts []int) {
+	counts[level] += 1
+	if level == 0 {
+		return
+	}
+	for _, child := range n.nodes {
+		if child != nil {
+			countNodes(child, level-1, counts)
+		}
+	}
+}
+
+// Stats returns statistics describing the tree backing v.
+func (v Vector[T]) Stats() Stats {
+	return newStats(v.count, v.depth, v.root, v.tail)
+}
+
+// Stats returns statistics describing the tree backing v.
+func (v TransientVector[T]) Stats() Stats {
+	v.ensureValid()
+
+	return newStats(v.count, v.depth, v.root, v.tail)
+}
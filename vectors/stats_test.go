@@ -0,0 +1,35 @@
+package vectors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestVectorStats(t *testing.T) {
+	var testCases = []struct {
+		name string
+		len  int
+		want vectors.Stats
+	}{
+		{"Empty", 0, vectors.Stats{}},
+		{"Tail", 32, vectors.Stats{Len: 32, TailLen: 32}},
+		{"RootLeaf", 33, vectors.Stats{Len: 33, Depth: 0, Nodes: []int{1}, TailLen: 1}},
+		{"Trie", 32*32 + 32, vectors.Stats{Len: 32*32 + 32, Depth: 1, Nodes: []int{32, 1}, TailLen: 32}},
+		{"DeepTrie", 32*32 + 33, vectors.Stats{Len: 32*32 + 33, Depth: 2, Nodes: []int{33, 2, 1}, TailLen: 1}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var vec = vectors.New(make([]int, tc.len)...)
+			if got, want := vec.Stats(), tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %+v, want %+v", got, want)
+			}
+			if got, want := vec.Transient().Stats(), tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got transient %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// defaultSep separates the values of a vector encoded as text.
+const defaultSep = ","
+
+// MarshalText implements the encoding.TextMarshaler interface, so a vector can
+// be used with flag.TextVar and other text based formats. The vector is
+// encoded as its values separated by commas, with an empty vector encoded as
+// empty text. Values are encoded using their own MarshalText method if they
+// implement encoding.TextMarshaler. Otherwise strings are written as-is, and
+// booleans and numbers as with the strconv package. Any other type of value
+// results in an error. No escaping is done, so values containing a comma
+// can't be decoded again; use Delimited to separate values differently.
+func (v Vector[T]) MarshalText() ([]byte, error) {
+	return marshalText(v, defaultSep)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, replacing
+// the contents of v with the values in text separated by commas, decoded as
+// described by MarshalText. Space around booleans and numbers is ignored.
+// Empty text decodes to an empty vector.
+func (v *Vector[T]) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text, defaultSep)
+}
+
+// Delimited wraps a vector so that it's encoded as text with its values
+// separated by Sep, rather than by commas as with Vector.MarshalText. The
+// zero value of Delimited is an empty vector separated by commas.
+type Delimited[T any] struct {
+	Vector Vector[T]
+	Sep    string
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, encoding the
+// vector as Vector.MarshalText does but with its values separated by Sep.
+func (d Delimited[T]) MarshalText() ([]byte, error) {
+	return marshalText(d.Vector, separator(d.Sep))
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, replacing
+// the vector with the values in text separated by Sep, decoded as
+// Vector.UnmarshalText does.
+func (d *Delimited[T]) UnmarshalText(text []byte) error {
+	return unmarshalText(&d.Vector, text, separator(d.Sep))
+}
+
+// String returns the vector encoded as text, or an empty string if it can't
+// be encoded.
+func (d Delimited[T]) String() string {
+	var text, _ = d.MarshalText()
+	return string(text)
+}
+
+// separator returns sep, or the default separator if sep is empty.
+func separator(sep string) string {
+	if sep == "" {
+		return defaultSep
+	}
+	return sep
+}
+
+func marshalText[T any](v Vector[T], sep string) ([]byte, error) {
+	var text = []byte{}
+	var first = true
+	var err error
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			if !first {
+				text = append(text, sep...)
+			}
+			first = false
+			if text, err = appendTextValue(text, value); err != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return text, nil
+}
+
+func unmarshalText[T any](v *Vector[T], text []byte, sep string) error {
+	if len(text) == 0 {
+		*v = Vector[T]{}
+		return nil
+	}
+
+	var fields = strings.Split(string(text), sep)
+	var values = make([]T, len(fields))
+	for i, field := range fields {
+		if err := parseTextValue(field, &values[i]); err != nil {
+			return fmt.Errorf("vectors: value %d: %w", i, err)
+		}
+	}
+	*v = fromSlice(values)
+	return nil
+}
+
+func appendTextValue[T any](text []byte, value T) ([]byte, error) {
+	if m, ok := any(value).(encoding.TextMarshaler); ok {
+		var b, err = m.MarshalText()
+		return append(text, b...), err
+	}
+
+	var rv = reflect.ValueOf(&value).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		return append(text, rv.String()...), nil
+	case reflect.Bool:
+		return strconv.AppendBool(text, rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(text, rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(text, rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(text, rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	default:
+		return nil, fmt.Errorf("vectors: cannot marshal value of type %T as text", value)
+	}
+}
+
+func parseTextValue[T any](field string, value *T) error {
+	if u, ok := any(value).(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(field))
+	}
+
+	var rv = reflect.ValueOf(value).Elem()
+	var trimmed = strings.TrimSpace(field)
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(field)
+	case reflect.Bool:
+		var b, err = strconv.ParseBool(trimmed)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n, err = strconv.ParseInt(trimmed, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n, err = strconv.ParseUint(trimmed, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f, err = strconv.ParseFloat(trimmed, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot unmarshal text into value of type %T", *value)
+	}
+	return nil
+}
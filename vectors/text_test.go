@@ -0,0 +1,128 @@
+package vectors_test
+
+import (
+	"flag"
+	"io"
+	"net/netip"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestVectorMarshalText(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		value interface{ MarshalText() ([]byte, error) }
+		want  string
+	}{
+		{"Empty", vectors.New[string](), ""},
+		{"Strings", vectors.New("a", "", "b c"), "a,,b c"},
+		{"Ints", vectors.New[int8](-128, 0, 127), "-128,0,127"},
+		{"Floats", vectors.New[float32](1.5, 0.1), "1.5,0.1"},
+		{"Bools", vectors.New(true, false), "true,false"},
+		{"TextMarshaler", vectors.New(netip.MustParseAddr("::1"), netip.MustParseAddr("10.0.0.1")), "::1,10.0.0.1"},
+		{"Delimited", vectors.Delimited[string]{Vector: vectors.New("/bin", "/usr/bin"), Sep: ":"}, "/bin:/usr/bin"},
+		{"DelimitedZero", vectors.Delimited[int]{Vector: vectors.New(1, 2)}, "1,2"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got, err = tc.value.MarshalText()
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVectorUnmarshalText(t *testing.T) {
+	var strs vectors.Vector[string]
+	if err := strs.UnmarshalText([]byte(" a,,b ")); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := strs.Len(), 3; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := strs.Nth(0)+"|"+strs.Nth(1)+"|"+strs.Nth(2), " a||b "; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	var ints = vectors.New(9)
+	if err := ints.UnmarshalText([]byte("1, 2 ,3")); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := ints.String(), "[1 2 3]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if err := ints.UnmarshalText(nil); err != nil || ints.Len() != 0 {
+		t.Fatalf("got %v, %v, want [], nil", ints, err)
+	}
+
+	var addrs = vectors.Delimited[netip.Addr]{Sep: " "}
+	if err := addrs.UnmarshalText([]byte("::1 10.0.0.1")); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := addrs.Vector.Nth(1), netip.MustParseAddr("10.0.0.1"); got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestVectorTextFlag(t *testing.T) {
+	var fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var ports vectors.Vector[uint16]
+	var path = vectors.Delimited[string]{Sep: ":"}
+	fs.TextVar(&ports, "ports", vectors.New[uint16](80), "ports to listen on")
+	fs.TextVar(&path, "path", vectors.Delimited[string]{Vector: vectors.New("/bin"), Sep: ":"}, "search path")
+
+	if got, want := ports.String(), "[80]"; got != want {
+		t.Fatalf("got default %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-ports", "80,443", "-path", "/bin:/usr/bin"}); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if got, want := ports.String(), "[80 443]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := path.Vector.String(), "[/bin /usr/bin]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if err := fs.Parse([]string{"-ports", "80,70000"}); err == nil {
+		t.Fatalf("got nil error for an out of range port, want an error")
+	}
+}
+
+func TestVectorTextErrors(t *testing.T) {
+	if text, err := vectors.New(struct{}{}).MarshalText(); err == nil {
+		t.Fatalf("got %q and nil error marshaling structs, want an error", text)
+	}
+
+	var testCases = []struct {
+		name string
+		text string
+	}{
+		{"NotANumber", "1,x"},
+		{"Overflow", "1,128"},
+		{"Empty", "1,,2"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var v vectors.Vector[int8]
+			if err := v.UnmarshalText([]byte(tc.text)); err == nil {
+				t.Fatalf("got %v and nil error, want an error", v)
+			}
+		})
+	}
+
+	var s vectors.Vector[struct{}]
+	if err := s.UnmarshalText([]byte("a")); err == nil {
+		t.Fatalf("got %v and nil error unmarshaling structs, want an error", s)
+	}
+}
@@ -8,7 +8,14 @@
 // idioms and techniques.
 package vectors
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
 
 // These constants determine the maximum width of vector nodes
 const nodeBits = 5
@@ -30,7 +37,7 @@ func indexInTail[T any](index int, count int, tail []T) bool {
 // given count. Returns true if a Vec of depth can be appended to without
 // creating a new root, otherwise returns false.
 func isDeepEnoughToAppend(depth, count int) bool {
-	return (count >> nodeBits) <= (1 << depth)
+	return (count >> nodeBits) <= (1 << (depth * nodeBits))
 }
 
 // findValues returns the slice of values within the vector which contains the
@@ -45,22 +52,67 @@ func findValues[T any](count, depth int, root *node[T], tail []T, index int) []T
 	}
 
 	// The index is not associated with the tail, so do a slow lookup for the
-	// node it is associated with.
+	// node it is associated with. Most vectors are shallow, so the walk is
+	// unrolled for them.
+	switch depth {
+	case 0:
+		return root.values
+	case 1:
+		return root.nodes[(index>>nodeBits)&nodeMask].values
+	case 2:
+		return root.nodes[(index>>(2*nodeBits))&nodeMask].nodes[(index>>nodeBits)&nodeMask].values
+	}
+
 	var walk = root
-	for level := depth; level > 0; level -= 1 {
-		walk = walk.nodes[indexAt(level, index)]
+	for shift := depth * nodeBits; shift > 0; shift -= nodeBits {
+		walk = walk.nodes[(index>>shift)&nodeMask]
 	}
 
 	return walk.values
 }
 
+// forEachLeaf calls yield with each slice of values within the vector in
+// index order, finishing with the tail. Iteration stops early if yield returns
+// false, in which case forEachLeaf also returns false.
+func forEachLeaf[T any](count, depth int, root *node[T], tail []T, yield func([]T) bool) bool {
+	for i := 0; i < count-len(tail); i += nodeWidth {
+		if !yield(findValues(count, depth, root, tail, i)) {
+			return false
+		}
+	}
+
+	if len(tail) > 0 {
+		return yield(tail)
+	}
+
+	return true
+}
+
 func cloneTail[T any](tail []T) []T {
 	var newTail = make([]T, len(tail))
 	copy(newTail, tail)
 	return newTail
 }
 
-type id int
+// id identifies the transient vector which owns a node. It also holds the edit
+// count of that transient, which every copy of the transient is checked
+// against to detect use after invalidation.
+//
+// Every field is accessed atomically, since a copy of a transient may check
+// its id while another goroutine is mutating the transient.
+type id struct {
+	edit atomic.Int64
+	// writing is set while a transient owning this id is being mutated, to
+	// detect the transient being used from more than one goroutine at once.
+	writing atomic.Bool
+	// frozen is set once the transient has been made persistent, after which
+	// it may still be read but its nodes, now shared with the persistent
+	// vector, must not be mutated.
+	frozen atomic.Bool
+	// site describes where the transient was last invalidated or frozen. It
+	// is only recorded when DebugTransients is set.
+	site atomic.Pointer[string]
+}
 
 var persistent *id = nil
 
@@ -69,6 +121,8 @@ type node[T any] struct {
 	id     *id
 	nodes  []*node[T]
 	values []T
+	// hash caches the most recent structural hashes computed for this node.
+	hash atomic.Pointer[nodeHashes]
 }
 
 func newNode[T any](id *id) *node[T] {
@@ -121,25 +175,244 @@ type Vector[T any] struct {
 
 // New creates a new persistent vector constructed from the values provided.
 func New[T any](vals ...T) Vector[T] {
-	var v = Vector[T]{}.Transient()
+	var tv = Vector[T]{}.Transient()
+	tv.ConjSlice(vals)
+	return tv.Persistent()
+}
 
-	for i := 0; i < len(vals); i++ {
-		v = v.Conj(vals[i])
+// fromSlice creates a new persistent vector by packing values directly into
+// leaves, building the tree above them one level at a time. The vector takes
+// ownership of values, which must not be modified afterwards.
+func fromSlice[T any](values []T) Vector[T] {
+	if len(values) == 0 {
+		return Vector[T]{}
 	}
 
-	return v.Persistent()
+	// The tail always holds between 1 and nodeWidth values so the tree only
+	// ever contains full leaves, just as if the vector was built with Conj.
+	var tailStart = tailOffset(len(values))
+
+	var leaves = make([]*node[T], 0, tailStart>>nodeBits)
+	for i := 0; i < tailStart; i += nodeWidth {
+		leaves = append(leaves, newLeaf(persistent, values[i:i+nodeWidth:i+nodeWidth]))
+	}
+
+	return fromLeaves(leaves, values[tailStart:len(values):len(values)])
+}
+
+// TransientFromSlice creates a new transient vector from the values of s
+// without copying them. The transient vector takes ownership of s, using its
+// memory for the leaves of the tree, so s must not be used afterwards.
+func TransientFromSlice[T any](s []T) TransientVector[T] {
+	var owner = new(id)
+	if len(s) == 0 {
+		return TransientVector[T]{id: owner, edit: owner.edit.Load()}
+	}
+
+	var tailStart = tailOffset(len(s))
+
+	var leaves = make([]*node[T], 0, tailStart>>nodeBits)
+	for i := 0; i < tailStart; i += nodeWidth {
+		leaves = append(leaves, newLeaf(owner, s[i:i+nodeWidth:i+nodeWidth]))
+	}
+
+	// The tail is limited to the length of s so that appending to it can't
+	// overwrite memory beyond the end of s.
+	var v = fromLeaves(leaves, s[tailStart:len(s):len(s)])
+
+	return TransientVector[T]{
+		id:    owner,
+		edit:  owner.edit.Load(),
+		count: v.count,
+		depth: v.depth,
+		tail:  v.tail,
+		root:  v.root,
+	}
+}
+
+// tailOffset returns the index of the first value in the tail of a vector
+// with count values.
+func tailOffset(count int) int {
+	if count == 0 {
+		return 0
+	}
+	return ((count - 1) >> nodeBits) << nodeBits
+}
+
+// fromLeaves creates a new persistent vector from full leaves and a tail,
+// building the tree above the leaves one level at a time. Runs of identical
+// full nodes share a single parent node, so repeated leaves take up no more
+// memory than a single one at each level.
+func fromLeaves[T any](leaves []*node[T], tail []T) Vector[T] {
+	var level = leaves
+	var depth = 0
+	for len(level) > 1 {
+		var parents = make([]*node[T], 0, (len(level)+nodeMask)>>nodeBits)
+		var shared *node[T]
+		for i := 0; i < len(level); i += nodeWidth {
+			var children = level[i:min(i+nodeWidth, len(level))]
+			if shared != nil && isRun(children, shared.nodes[0]) {
+				parents = append(parents, shared)
+				continue
+			}
+
+			var parent = newNode[T](persistent)
+			copy(parent.nodes, children)
+			parents = append(parents, parent)
+			if isRun(children, children[0]) {
+				shared = parent
+			}
+		}
+		level = parents
+		depth += 1
+	}
+
+	var root *node[T]
+	if len(level) > 0 {
+		root = level[0]
+	}
+
+	return Vector[T]{
+		count: len(leaves)<<nodeBits + len(tail),
+		depth: depth,
+		tail:  tail,
+		root:  root,
+	}
+}
+
+// isRun returns true if nodes is a full node's worth of only child.
+func isRun[T any](nodes []*node[T], child *node[T]) bool {
+	if len(nodes) != nodeWidth {
+		return false
+	}
+	for _, n := range nodes {
+		if n != child {
+			return false
+		}
+	}
+	return true
+}
+
+// appendTo appends the values of v to dst in order and returns the extended
+// slice, similarly to the builtin append.
+func (v Vector[T]) appendTo(dst []T) []T {
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		dst = append(dst, values...)
+		return true
+	})
+	return dst
+}
+
+// take creates a new vector containing only the first n values of v, which
+// must be between zero and v.count. The new vector shares the tree of v up to
+// the leaf containing its last value, which becomes the new tail, and only the
+// nodes along the right edge of the remaining tree are cloned.
+func (v Vector[T]) take(n int) Vector[T] {
+	if n == v.count {
+		return v
+	}
+	if n == 0 {
+		return Vector[T]{}
+	}
+
+	var tailStart = tailOffset(n)
+	if tailStart == v.count-len(v.tail) {
+		// The cut is within the tail, so the tree is unaffected.
+		return Vector[T]{
+			count: n,
+			depth: v.depth,
+			tail:  v.tail[: n-tailStart : n-tailStart],
+			root:  v.root,
+		}
+	}
+
+	var tail = findValues(v.count, v.depth, v.root, v.tail, tailStart)[: n-tailStart : n-tailStart]
+
+	var leaves = tailStart >> nodeBits
+	if leaves == 0 {
+		return Vector[T]{count: n, tail: tail}
+	}
+
+	// Remove levels from the top of the tree that only the cut values needed.
+	var depth = v.depth
+	var root = v.root
+	for depth > 0 && (leaves-1)>>((depth-1)*nodeBits) == 0 {
+		root = root.nodes[0]
+		depth -= 1
+	}
+
+	return Vector[T]{
+		count: n,
+		depth: depth,
+		tail:  tail,
+		root:  trimNode(root, depth, tailStart-1),
+	}
+}
+
+// drop creates a new vector containing all but the first n values of v, where
+// n must be between zero and v.count. When n is a multiple of nodeWidth the
+// leaves of v are reused as-is and only the tree above them is rebuilt,
+// otherwise the values are copied into new leaves.
+func (v Vector[T]) drop(n int) Vector[T] {
+	if n == 0 {
+		return v
+	}
+	if n == v.count {
+		return Vector[T]{}
+	}
+
+	var tailStart = v.count - len(v.tail)
+	if n&nodeMask != 0 || n > tailStart {
+		var values = make([]T, 0, v.count-n)
+		for seq := newSeq(v, n); !seq.IsEmpty(); seq = seq.ChunkRest() {
+			values = append(values, seq.Chunk()...)
+		}
+		return fromSlice(values)
+	}
+
+	return fromLeaves(v.appendLeaves(nil, n, tailStart), v.tail)
+}
+
+// appendLeaves appends the leaves of the tree of v holding the values from
+// index start (inclusive) to end (exclusive) to dst, where start and end are
+// multiples of nodeWidth no greater than the index of the tail.
+func (v Vector[T]) appendLeaves(dst []*node[T], start, end int) []*node[T] {
+	dst = slices.Grow(dst, (end-start)>>nodeBits)
+	for i := start; i < end; i += nodeWidth {
+		var walk = v.root
+		for level := v.depth; level > 0; level -= 1 {
+			walk = walk.nodes[indexAt(level, i)]
+		}
+		dst = append(dst, walk)
+	}
+	return dst
+}
+
+// trimNode returns a node at the given level containing only the values of n
+// up to and including the value at index last.
+func trimNode[T any](n *node[T], level, last int) *node[T] {
+	if level == 0 {
+		return n
+	}
+
+	var i = indexAt(level, last)
+	var trimmed = newNode[T](persistent)
+	copy(trimmed.nodes[:i], n.nodes[:i])
+	trimmed.nodes[i] = trimNode(n.nodes[i], level-1, last)
+
+	return trimmed
 }
 
 // Transient creates a new transient vector using v as its base
 func (v Vector[T]) Transient() TransientVector[T] {
-	id := new(id)
+	var owner = new(id)
 	return TransientVector[T]{
-		id:      id,
-		invalid: false,
-		count:   v.count,
-		depth:   v.depth,
-		tail:    cloneTail(v.tail),
-		root:    v.root,
+		id:    owner,
+		edit:  owner.edit.Load(),
+		count: v.count,
+		depth: v.depth,
+		tail:  cloneTail(v.tail),
+		root:  v.root,
 	}
 }
 
@@ -149,11 +422,30 @@ func (v Vector[T]) Len() int {
 }
 
 // Nth returns from the vector the value at the index provided. The index must
-// be greater than zero and less than v.count.
+// be greater than zero and less than v.count. Each call walks the tree from the
+// root; a Cursor keeps its place for sequential access.
 func (v Vector[T]) Nth(index int) T {
 	return findValues(v.count, v.depth, v.root, v.tail, index)[indexAt(0, index)]
 }
 
+// LeafAt returns the leaf of v containing the value at index, along with the
+// offset of that value within the leaf, so that values[offset] is the same as
+// v.Nth(index). The leaf is returned without copying so it shares memory
+// with v, and it must not be modified. The index must be greater than or
+// equal to zero and less than v.Len().
+//
+// Leaves can be used to process a vector a slice at a time:
+//
+//	for i := 0; i < v.Len(); {
+//		values, offset := v.LeafAt(i)
+//		process(values[offset:])
+//		i += len(values) - offset
+//	}
+func (v Vector[T]) LeafAt(index int) (values []T, offset int) {
+	values = findValues(v.count, v.depth, v.root, v.tail, index)
+	return values[:len(values):len(values)], indexAt(0, index)
+}
+
 // Peek returns the last value from a vector.
 func (v Vector[T]) Peek() T {
 	return v.Nth(v.count - 1)
@@ -184,8 +476,8 @@ func (v Vector[T]) Assoc(index int, value T) Vector[T] {
 
 	// Walk through the tree, cloning the path to the updated node.
 	var walk = newRoot
-	for level := v.depth; level > 0; level -= 1 {
-		var i = indexAt(level, index)
+	for shift := v.depth * nodeBits; shift > 0; shift -= nodeBits {
+		var i = (index >> shift) & nodeMask
 		walk.nodes[i] = cloneNode(persistent, walk.nodes[i])
 		walk = walk.nodes[i]
 	}
@@ -200,6 +492,210 @@ func (v Vector[T]) Assoc(index int, value T) Vector[T] {
 	}
 }
 
+// Swap creates a new vector with the values at indices i and j exchanged.
+// Both indices must be greater than or equal to zero and less than v.Len().
+// The paths to both values are cloned only once, even where they overlap.
+func (v Vector[T]) Swap(i, j int) Vector[T] {
+	var a, b = v.Nth(i), v.Nth(j)
+	if i == j {
+		return v
+	}
+
+	// A transient only clones nodes it doesn't own yet, so the second Assoc
+	// reuses any nodes the first one already cloned.
+	var tv = v.Transient()
+	tv.Assoc(i, b)
+	tv.Assoc(j, a)
+	return tv.Persistent()
+}
+
+// SplitAt creates two new vectors from v, the first containing the values
+// before index i and the second containing the values from index i onward.
+// The index must be between zero and v.Len(), inclusive. The first vector
+// shares the tree of v up to index i. The second vector shares the leaves of v
+// when i is a multiple of 32, and otherwise copies the values after i.
+func (v Vector[T]) SplitAt(i int) (Vector[T], Vector[T]) {
+	if i < 0 || i > v.count {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, v.count))
+	}
+
+	return v.take(i), v.drop(i)
+}
+
+// CopyTo copies values from the start of v into dst, returning the number of
+// values copied, which is the minimum of len(dst) and v.Len(). Values are
+// copied a leaf at a time similarly to the builtin copy.
+func (v Vector[T]) CopyTo(dst []T) int {
+	var n = 0
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		n += copy(dst[n:], values)
+		return n < len(dst)
+	})
+	return n
+}
+
+// Truncate creates a new vector containing only the first n values of v. The
+// length n must be between zero and v.Len(), inclusive. Rather than rebuilding
+// the vector, the tree of v is cut after the leaf containing the last value
+// kept, which takes O(log n) time.
+func (v Vector[T]) Truncate(n int) Vector[T] {
+	if n < 0 || n > v.count {
+		panic(fmt.Sprintf("length out of range [%d] with length %d", n, v.count))
+	}
+
+	return v.take(n)
+}
+
+// Grow creates a new vector of length n by appending zero values to v. The
+// length n must be greater than or equal to v.Len().
+func (v Vector[T]) Grow(n int) Vector[T] {
+	var zero T
+	return v.GrowFill(n, zero)
+}
+
+// GrowFill creates a new vector of length n by appending copies of value to
+// v. The length n must be greater than or equal to v.Len(). Rather than
+// appending one value at a time, a single full leaf of value is shared by
+// every position of the tree it is needed in.
+func (v Vector[T]) GrowFill(n int, value T) Vector[T] {
+	if n < v.count {
+		panic(fmt.Sprintf("length out of range [%d] with length %d", n, v.count))
+	}
+	if n == v.count {
+		return v
+	}
+
+	var tv = v.Transient()
+	for tv.count < n && len(tv.tail) < nodeWidth {
+		tv.Conj(value)
+	}
+	if tv.count == n {
+		return tv.Persistent()
+	}
+
+	// The tail is full and more values follow it, so it moves into the tree
+	// followed by as many shared full leaves as needed.
+	tv.pushLeaf(newLeaf(tv.id, tv.tail), tv.count-nodeWidth)
+
+	var tailStart = tailOffset(n)
+	if tv.count < tailStart {
+		var values = make([]T, nodeWidth)
+		for i := range values {
+			values[i] = value
+		}
+		var leaf = newLeaf(persistent, values)
+		for start := tv.count; start < tailStart; start += nodeWidth {
+			tv.pushLeaf(leaf, start)
+		}
+	}
+
+	tv.tail = make([]T, n-tailStart, nodeWidth)
+	for i := range tv.tail {
+		tv.tail[i] = value
+	}
+	tv.count = n
+
+	return tv.Persistent()
+}
+
+// FillRange creates a new vector with every value from index start
+// (inclusive) to end (exclusive) replaced by value. The range must satisfy
+// 0 <= start <= end <= v.Len(). Each leaf overlapping the range, and the path
+// to it, is cloned only once rather than once per value.
+func (v Vector[T]) FillRange(start, end int, value T) Vector[T] {
+	if start < 0 || end < start || end > v.count {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d] with length %d", start, end, v.count))
+	}
+	if start == end {
+		return v
+	}
+
+	// A transient only clones nodes it doesn't own yet, so each leaf and node
+	// above it is cloned on the first Assoc to it and reused after that.
+	var tv = v.Transient()
+	for i := start; i < end; i++ {
+		tv.Assoc(i, value)
+	}
+
+	return tv.Persistent()
+}
+
+// ReplaceRange creates a new vector with the values from index start
+// (inclusive) to end (exclusive) replaced by the values of replacement, which
+// may be of any length. The range must satisfy 0 <= start <= end <= v.Len().
+// The values before start are shared with v by splitting its tree, while the
+// values of replacement and those after end are appended to it, so this takes
+// time proportional to the number of values appended rather than to the length
+// of v.
+func (v Vector[T]) ReplaceRange(start, end int, replacement Vector[T]) Vector[T] {
+	if start < 0 || end < start || end > v.count {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d] with length %d", start, end, v.count))
+	}
+	if start == v.count {
+		return concat(v, replacement.Seq())
+	}
+
+	var tv = v.take(start).Transient()
+	conjSeq(&tv, replacement.Seq())
+	conjSeq(&tv, newSeq(v, end))
+
+	return tv.Persistent()
+}
+
+// RemoveRange creates a new vector with the values from index start
+// (inclusive) to end (exclusive) removed. The range must satisfy
+// 0 <= start <= end <= v.Len().
+//
+// Only removing values from the end of v takes O(log n) time, by splitting
+// the tree of v after the values before start. Every leaf of a vector's tree
+// is full, so the values after end must be moved to fill the leaves left by
+// the removed values; joining two trees in O(log n) time would need leaves
+// which aren't full, which a Vector doesn't have. When start and end are both
+// multiples of 32 the leaves after end are reused as-is, so only the tree
+// above them is rebuilt, taking time proportional to the number of leaves of
+// v. Otherwise the values after end are appended to those before start,
+// taking time proportional to the number of values after end.
+func (v Vector[T]) RemoveRange(start, end int) Vector[T] {
+	if start < 0 || end < start || end > v.count {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d] with length %d", start, end, v.count))
+	}
+	if start == 0 {
+		return v.drop(end)
+	}
+	if end == v.count {
+		return v.take(start)
+	}
+
+	var tailStart = v.count - len(v.tail)
+	if start&nodeMask == 0 && end&nodeMask == 0 && end <= tailStart {
+		var leaves = make([]*node[T], 0, (tailStart-end+start)>>nodeBits)
+		leaves = v.appendLeaves(leaves, 0, start)
+		leaves = v.appendLeaves(leaves, end, tailStart)
+		return fromLeaves(leaves, v.tail)
+	}
+
+	return concat(v.take(start), newSeq(v, end))
+}
+
+// concat creates a new vector with the values of s appended to v.
+func concat[T any](v Vector[T], s Seq[T]) Vector[T] {
+	if s.IsEmpty() {
+		return v
+	}
+	var tv = v.Transient()
+	conjSeq(&tv, s)
+	return tv.Persistent()
+}
+
+// conjSeq appends the values of s to tv in place.
+func conjSeq[T any](tv *TransientVector[T], s Seq[T]) {
+	for ; !s.IsEmpty(); s = s.ChunkRest() {
+		for _, value := range s.Chunk() {
+			tv.Conj(value)
+		}
+	}
+}
+
 // Conj creates a new vector with a value appended to the end.
 func (v Vector[T]) Conj(val T) Vector[T] {
 	// Either the tail is being appended to, or a node in the tree is.
@@ -229,10 +725,14 @@ func (v Vector[T]) Conj(val T) Vector[T] {
 
 	// Walk through the tree with an indirect pointer to find location the tail
 	// will end up being moved to, creating new nodes along the way as needed.
+	// Existing nodes along the path are cloned, since other vectors sharing
+	// them may append their own tails to the same empty slots.
 	var indirect = &newRoot
 	for level := newDepth; level > 0; level -= 1 {
 		if *indirect == nil {
 			*indirect = newNode[T](persistent)
+		} else {
+			*indirect = cloneNode(persistent, *indirect)
 		}
 		indirect = &(*indirect).nodes[indexAt(level, v.count-1)]
 	}
@@ -257,28 +757,82 @@ func (v Vector[T]) Conj(val T) Vector[T] {
 //		With one item: [1]
 //		With more than one item: [1 2 3]
 func (v Vector[T]) String() string {
-	var s = "["
-	for i := 0; i < v.count; i += 1 {
-		if i == 0 {
-			s += fmt.Sprintf("%v", v.Nth(i))
-		} else {
-			s += fmt.Sprintf(" %v", v.Nth(i))
+	return formatValues(v.count, v.depth, v.root, v.tail)
+}
+
+// formatValues writes the values within a vector into a string a leaf at a
+// time, in the same form as a Go slice formatted with the "%v" verb.
+func formatValues[T any](count, depth int, root *node[T], tail []T) string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	var first = true
+	forEachLeaf(count, depth, root, tail, func(values []T) bool {
+		for _, value := range values {
+			if !first {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprint(&sb, value)
+			first = false
 		}
+		return true
+	})
+	sb.WriteByte(']')
+
+	return sb.String()
+}
+
+// Format implements the fmt.Formatter interface, formatting a vector in the
+// same form as a Go slice with the verb and flags applied to each value. The
+// "%#v" verb formats the vector as a call to New:
+//
+//	%v:  [1 2 3]
+//	%#v: vectors.New[int](1, 2, 3)
+func (v Vector[T]) Format(f fmt.State, verb rune) {
+	var format = fmt.FormatString(f, verb)
+
+	var open, sep, close = "[", " ", "]"
+	if verb == 'v' && f.Flag('#') {
+		open = fmt.Sprintf("vectors.New[%v](", reflect.TypeFor[T]())
+		sep, close = ", ", ")"
 	}
-	s += "]"
 
-	return s
+	io.WriteString(f, open)
+	var i = 0
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(values []T) bool {
+		for _, value := range values {
+			if i > 0 {
+				io.WriteString(f, sep)
+			}
+			fmt.Fprintf(f, format, value)
+			i += 1
+		}
+		return true
+	})
+	io.WriteString(f, close)
 }
 
 // TransientVector provides the same API as a persistent vector, however a
-// transient vector becomes invalid after any operation that creates a new
-// vector from an itself. While transient vectors are similar in structure
-// to a persistent vectors, they are meant to be used in places where
-// persistence isn't needed, and faster performance for certain operations is
-// required. Each time an operation on a TransientVector is performed, a new
-// one is created using the same underlying memory. The old TransientVector is
-// then marked invalidated so if it is used again with any of the operations
-// this package provides, a panic occurs.
+// transient vector is updated in place rather than creating a new vector for
+// each operation. While transient vectors are similar in structure to a
+// persistent vectors, they are meant to be used in places where persistence
+// isn't needed, and faster performance for certain operations is required.
+//
+// Operations which only read from a transient vector (such as Len, Nth, All
+// and ToSlice) leave it valid. Operations which mutate it (Assoc, Conj,
+// ConjSlice and Pop) update it in place through a pointer, and invalidate
+// every copy of it made beforehand. Any operation on an invalidated transient
+// vector panics. Persistent freezes the transient vector and all of its
+// copies, since the memory it used is then shared with the persistent vector
+// returned: they may still be read, but any mutation of them panics.
+//
+// A transient vector may be handed from one goroutine to another, but must
+// only be used by one goroutine at a time, with the hand-off synchronized as
+// for any other memory shared between goroutines. Only concurrent mutation is
+// detected: mutating a transient vector while another goroutine is mutating
+// it panics rather than silently corrupting it, and so may reading it during
+// another goroutine's mutation. Use from another goroutine which doesn't
+// overlap a mutation, but isn't synchronized with it either, isn't detected,
+// and is left for the race detector to find.
 type TransientVector[T any] struct {
 	// id is used to ensure transients mutate only nodes with their unique ID.
 	// This works because a new ID is allocated whenever a transient vector is
@@ -290,35 +844,94 @@ type TransientVector[T any] struct {
 	// Also note that the zero value of TransientVector is valid, even though it
 	// isn't assigned an ID. This is because:
 	//     1. An empty TransientVector can't possibly point to nodes owned by another vector.
-	//     2. Once made persistent it's nodes will have a nil id, the same as persistent vectors.
-	id      *id
-	invalid bool     // Set to true to after a mutation.
-	count   int      // Number of items in this vector
-	depth   int      // Depth of the tree under root
-	tail    []T      // Quickly access items at the end of the vector
-	root    *node[T] // Root of the tree containg either child nodes or items
+	//     2. An ID is allocated by the first mutation, before any node is made.
+	id    *id
+	edit  int64    // Edit count of id this transient is valid for
+	count int      // Number of items in this vector
+	depth int      // Depth of the tree under root
+	tail  []T      // Quickly access items at the end of the vector
+	root  *node[T] // Root of the tree containg either child nodes or items
 }
 
+// ensureValid panics if v has been invalidated by a mutation, or if v is being
+// mutated by another goroutine at the same time.
 func (v TransientVector[T]) ensureValid() {
-	if v.invalid {
+	if v.id == nil {
+		return
+	}
+	if v.id.writing.Load() {
+		panic("concurrent use of a transient vector while it is being mutated")
+	}
+	if v.id.edit.Load() != v.edit {
+		if site := v.id.site.Load(); site != nil {
+			panic("attempted operation on an invalid transient vector, invalidated by " + *site)
+		}
 		panic("attempted operation on an invalid transient vector")
 	}
 }
 
-func (v TransientVector[T]) invalidate() {
+// invalidate invalidates every copy of v by advancing the edit count of its
+// id, then updates v to be the only valid transient for the new edit count.
+// The id is marked as being written to until release is called, which must
+// be deferred by the caller.
+func (v *TransientVector[T]) invalidate() {
 	v.ensureValid()
-	v.invalid = true
+
+	if v.id == nil {
+		v.id = new(id)
+	}
+	if v.id.frozen.Load() {
+		if site := v.id.site.Load(); site != nil {
+			panic("attempted mutation of a transient vector after it was made persistent by " + *site)
+		}
+		panic("attempted mutation of a transient vector after it was made persistent")
+	}
+	if !v.id.writing.CompareAndSwap(false, true) {
+		panic("concurrent mutation of a transient vector")
+	}
+	v.edit = v.id.edit.Add(1)
+	if DebugTransients {
+		var site = callSite(2)
+		v.id.site.Store(&site)
+	}
 }
 
-// Persistent creates a new persistent Vector from a transient vector.
+// release marks the id of v as no longer being written to.
+func (v *TransientVector[T]) release() {
+	v.id.writing.Store(false)
+}
+
+// Persistent creates a new persistent Vector from a transient vector, freezing
+// the transient vector. Reads from the transient vector and its copies remain
+// valid afterwards, but mutating any of them panics.
 func (v TransientVector[T]) Persistent() Vector[T] {
-	v.invalidate()
+	v.ensureValid()
+
+	if v.id != nil {
+		v.id.frozen.Store(true)
+		if DebugTransients {
+			var site = callSite(1)
+			v.id.site.Store(&site)
+		}
+	}
+
+	// Remove any levels from the top of the tree which were reserved ahead of
+	// time but aren't needed by the values in it.
+	var depth, root = v.depth, v.root
+	var leaves = tailOffset(v.count) >> nodeBits
+	if leaves == 0 {
+		depth, root = 0, nil
+	}
+	for depth > 0 && (leaves-1)>>((depth-1)*nodeBits) == 0 {
+		root = root.nodes[0]
+		depth -= 1
+	}
 
 	return Vector[T]{
-		depth: v.depth,
+		depth: depth,
 		count: v.count,
 		tail:  cloneTail(v.tail),
-		root:  cloneNode(persistent, v.root),
+		root:  cloneNode(persistent, root),
 	}
 }
 
@@ -350,23 +963,26 @@ func (v TransientVector[T]) Peek() T {
 func (v TransientVector[T]) String() string {
 	v.ensureValid()
 
-	var s = "["
-	for i := 0; i < v.count; i += 1 {
-		if i == 0 {
-			s += fmt.Sprintf("%v", v.Nth(i))
-		} else {
-			s += fmt.Sprintf(" %v", v.Nth(i))
-		}
-	}
-	s += "]"
+	return formatValues(v.count, v.depth, v.root, v.tail)
+}
+
+// ToSlice returns a new slice containing the values of v in order. Unlike
+// converting v to a persistent vector first, no nodes of v are cloned.
+func (v TransientVector[T]) ToSlice() []T {
+	v.ensureValid()
 
-	return s
+	var values = make([]T, 0, v.count)
+	forEachLeaf(v.count, v.depth, v.root, v.tail, func(leaf []T) bool {
+		values = append(values, leaf...)
+		return true
+	})
+	return values
 }
 
-// Assoc returns a transient vector with a value updated at the given index,
-// invalidating the transient vector that was operated on.
-func (v TransientVector[T]) Assoc(index int, value T) TransientVector[T] {
+// Assoc updates the value at the given index of v in place.
+func (v *TransientVector[T]) Assoc(index int, value T) {
 	v.invalidate()
+	defer v.release()
 
 	if index < 0 || index >= v.count {
 		panic(fmt.Sprintf("index out of range [%d] with length %d", index, v.count))
@@ -374,14 +990,7 @@ func (v TransientVector[T]) Assoc(index int, value T) TransientVector[T] {
 
 	if indexInTail(index, v.count, v.tail) {
 		v.tail[indexAt(0, index)] = value
-		return TransientVector[T]{
-			id:      v.id,
-			invalid: false,
-			depth:   v.depth,
-			count:   v.count,
-			tail:    v.tail,
-			root:    v.root,
-		}
+		return
 	}
 
 	if v.root.id != v.id {
@@ -391,82 +1000,140 @@ func (v TransientVector[T]) Assoc(index int, value T) TransientVector[T] {
 
 	// Walk through the tree and update the leaf value found.
 	var walk = v.root
-	for level := v.depth; level > 0; level -= 1 {
-		var i = indexAt(level, index)
+	for shift := v.depth * nodeBits; shift > 0; shift -= nodeBits {
+		var i = (index >> shift) & nodeMask
 		if walk.nodes[i].id != v.id {
 			walk.nodes[i] = cloneNode(v.id, walk.nodes[i])
 		}
 		walk = walk.nodes[i]
 	}
 	walk.values[indexAt(0, index)] = value
-
-	return TransientVector[T]{
-		id:      v.id,
-		invalid: false,
-		depth:   v.depth,
-		count:   v.count,
-		tail:    v.tail,
-		root:    v.root,
-	}
 }
 
-// Conj returns a transient vector with a value appended to the end,
-// invalidating the transient vector operated on.
-func (v TransientVector[T]) Conj(val T) TransientVector[T] {
+// Conj appends a value to the end of v in place.
+func (v *TransientVector[T]) Conj(val T) {
 	v.invalidate()
+	defer v.release()
 
 	// Either the tail is being appended to, or a node in the tree is.
 	if len(v.tail) < nodeWidth {
 		// The tail still has space, so just append to it.
+		v.tail = append(v.tail, val)
+		v.count += 1
+		return
+	}
+
+	// There is no room in the tail, so move the tail into the tree.
+	v.pushLeaf(newLeaf(v.id, v.tail), v.count-nodeWidth)
+
+	// Create a new tail for conjugating the new value to. Allocate enough
+	// space for a full tail up-front to optimize appending new values.
+	v.tail = make([]T, 0, nodeWidth)
+	v.tail = append(v.tail, val)
+	v.count += 1
+}
+
+// ConjSlice appends each of values to the end of v in order, in place. Values
+// are copied into the tail a leaf's worth at a time rather than one at a time.
+func (v *TransientVector[T]) ConjSlice(values []T) {
+	v.invalidate()
+	defer v.release()
 
-		return TransientVector[T]{
-			id:      v.id,
-			invalid: false,
-			depth:   v.depth,
-			count:   v.count + 1,
-			tail:    append(v.tail, val),
-			root:    v.root,
+	for len(values) > 0 {
+		if len(v.tail) == nodeWidth {
+			// There is no room in the tail, so move the tail into the tree.
+			v.pushLeaf(newLeaf(v.id, v.tail), v.count-nodeWidth)
+			v.tail = make([]T, 0, nodeWidth)
 		}
+
+		var n = min(nodeWidth-len(v.tail), len(values))
+		v.tail = append(v.tail, values[:n]...)
+		v.count += n
+		values = values[n:]
 	}
+}
 
-	// There is no room in the tail, so move the tail into the tree.
+// Reserve prepares v to grow to at least n values in place. The tree is
+// deepened ahead of time so that it doesn't need to be deepened as values are
+// appended, and the tail is given room for a full leaf of values if it has
+// none to spare.
+func (v *TransientVector[T]) Reserve(n int) {
+	v.invalidate()
+	defer v.release()
 
-	var newDepth = v.depth
-	var newRoot = v.root
+	if cap(v.tail) < nodeWidth {
+		var tail = make([]T, len(v.tail), nodeWidth)
+		copy(tail, v.tail)
+		v.tail = tail
+	}
 
-	if !isDeepEnoughToAppend(v.depth, v.count) {
+	for !isDeepEnoughToAppend(v.depth, tailOffset(n)) {
+		if v.root != nil {
+			var newRoot = newNode[T](v.id)
+			newRoot.nodes[0] = v.root
+			v.root = newRoot
+		}
+		v.depth += 1
+	}
+}
+
+// pushLeaf inserts leaf into the tree of v as the leaf beginning at index,
+// which must be the first index following every leaf already in the tree.
+// Nodes along the way which aren't owned by v are cloned first.
+func (v *TransientVector[T]) pushLeaf(leaf *node[T], index int) {
+	if !isDeepEnoughToAppend(v.depth, index+nodeWidth) {
 		// No space left in the current tree, so deepen the tree one level
 		// with a new root node to contain the old root.
-		newDepth = v.depth + 1
-		newRoot = newNode[T](v.id)
+		var newRoot = newNode[T](v.id)
 		newRoot.nodes[0] = v.root
+		v.root = newRoot
+		v.depth += 1
 	}
 
 	// Walk through the tree with an indirect pointer to find the location the
-	// old tail will end up being moved to, then move it in as a value node.
-	var indirect = &newRoot
-	for level := newDepth; level > 0; level -= 1 {
+	// leaf will end up being moved to, then move it in.
+	var indirect = &v.root
+	for level := v.depth; level > 0; level -= 1 {
 		if *indirect == nil {
 			*indirect = newNode[T](v.id)
 		}
 		if (*indirect).id != v.id {
 			*indirect = cloneNode(v.id, *indirect)
 		}
-		indirect = &(*indirect).nodes[indexAt(level, v.count-1)]
+		indirect = &(*indirect).nodes[indexAt(level, index)]
 	}
-	*indirect = newLeaf(v.id, v.tail)
+	*indirect = leaf
+}
 
-	// Create a new tail for conjugating the new value to. Allocate enough
-	// space for a full tail up-front to optimize appending new values.
-	var newTail = make([]T, 0, nodeWidth)
-	newTail = append(newTail, val)
+// Pop removes the last value from v in place. Pop panics if the vector is
+// empty.
+func (v *TransientVector[T]) Pop() {
+	v.invalidate()
+	defer v.release()
 
-	return TransientVector[T]{
-		id:      v.id,
-		invalid: false,
-		depth:   newDepth,
-		count:   v.count + 1,
-		tail:    newTail,
-		root:    newRoot,
+	if v.count == 0 {
+		panic("attempted to pop an empty vector")
+	}
+
+	if len(v.tail) > 1 {
+		// The tail is owned by this transient, so just shorten it.
+		v.tail = v.tail[:len(v.tail)-1]
+		v.count -= 1
+		return
 	}
+
+	// The last leaf of the tree becomes the new tail, which is copied so that
+	// it can be appended to in place.
+	var popped = Vector[T]{
+		count: v.count,
+		depth: v.depth,
+		tail:  v.tail,
+		root:  v.root,
+	}.take(v.count - 1)
+
+	v.tail = make([]T, len(popped.tail), nodeWidth)
+	copy(v.tail, popped.tail)
+	v.count = popped.count
+	v.depth = popped.depth
+	v.root = popped.root
 }
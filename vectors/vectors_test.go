@@ -2,6 +2,8 @@ package vectors_test
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/toddgaunt/persistent/vectors"
@@ -25,6 +27,31 @@ func TestVectorNth(t *testing.T) {
 	}
 }
 
+func TestVectorLeafAt(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+
+	var got []int
+	for i := 0; i < vec.Len(); {
+		values, offset := vec.LeafAt(i)
+		if values[offset] != vec.Nth(i) {
+			t.Fatalf("got values[offset]=%d, want %d", values[offset], vec.Nth(i))
+		}
+		if cap(values) != len(values) {
+			t.Fatalf("got leaf with spare capacity %d", cap(values)-len(values))
+		}
+		got = append(got, values[offset:]...)
+		i += len(values) - offset
+	}
+
+	if got, want := fmt.Sprintf("%v", got), fmt.Sprintf("%v", testSlice); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if _, offset := vec.LeafAt(40); offset != 8 {
+		t.Fatalf("got offset %d, want 8", offset)
+	}
+}
+
 func TestVectorAssoc(t *testing.T) {
 	var testCases = []struct {
 		name   string
@@ -143,6 +170,443 @@ func TestVectorConj(t *testing.T) {
 	}
 }
 
+func TestVectorConjDepthBoundaries(t *testing.T) {
+	// Appending one value at a time must deepen the tree exactly when it is
+	// full, at tail offsets of 32, 32*32 and 32*32*32 values.
+	var slice = make([]int, 32*32*32+2*32+1)
+	for i := range slice {
+		slice[i] = i
+	}
+	var checks = map[int]bool{}
+	for _, size := range []int{32, 32 * 32, 32 * 32 * 32} {
+		for _, n := range []int{size - 1, size, size + 1, size + 32, size + 33} {
+			checks[n] = true
+		}
+	}
+
+	var vec = vectors.New[int]()
+	var tvec = vectors.New[int]().Transient()
+	for n := 1; n <= len(slice); n++ {
+		vec = vec.Conj(n - 1)
+		tvec.Conj(n - 1)
+		if !checks[n] && n != len(slice) {
+			continue
+		}
+
+		var want = vectors.New(slice[:n]...).Stats()
+		for _, got := range []vectors.Vector[int]{vec, tvec.Persistent()} {
+			for i := 0; i < n; i++ {
+				if got.Nth(i) != i {
+					t.Fatalf("got Nth(%d)=%d with %d values, want Nth(%d)=%d", i, got.Nth(i), n, i, i)
+				}
+			}
+			if !reflect.DeepEqual(got.Stats(), want) {
+				t.Fatalf("got stats %+v with %d values, want %+v", got.Stats(), n, want)
+			}
+		}
+		tvec = vec.Transient()
+	}
+}
+
+func TestVectorConjShared(t *testing.T) {
+	// Conj onto the same vector twice must not let either result see the
+	// values appended to the other, even once their tails move into the tree.
+	var vec = vectors.New(make([]int, 96)...)
+	var a, b = vec, vec
+	for i := 0; i < 70; i++ {
+		a = a.Conj(1)
+	}
+	for i := 0; i < 70; i++ {
+		b = b.Conj(2)
+	}
+	for i := vec.Len(); i < a.Len(); i++ {
+		if got := a.Nth(i); got != 1 {
+			t.Fatalf("got a.Nth(%d)=%d, want a.Nth(%d)=1", i, got, i)
+		}
+	}
+}
+
+func TestVectorConjOldVersion(t *testing.T) {
+	// Conj onto an old version of a vector must leave the newer versions
+	// grown from it unchanged, at every depth of the tree.
+	for _, size := range []int{32, 96, 32*32 + 32, 32*32*2 + 40} {
+		var old = vectors.New(make([]int, size)...)
+		var newer = old
+		for i := 0; i < 100; i++ {
+			newer = newer.Conj(1)
+		}
+		var want = newer.String()
+
+		var other = old
+		for i := 0; i < 100; i++ {
+			other = other.Conj(2)
+		}
+		if got := newer.String(); got != want {
+			t.Fatalf("got newer version %s after Conj onto the old version, want %s", got, want)
+		}
+	}
+}
+
+func TestVectorSwap(t *testing.T) {
+	var testCases = []struct {
+		name string
+		i, j int
+	}{
+		{"Same", 3, 3},
+		{"SameLeaf", 0, 1},
+		{"DifferentLeaves", 0, 40},
+		{"TrieAndTail", 5, 64},
+		{"Tail", 64, 64},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var vec = vectors.New(testSlice...)
+			var swapped = vec.Swap(tc.i, tc.j)
+
+			var want = append([]int{}, testSlice...)
+			want[tc.i], want[tc.j] = want[tc.j], want[tc.i]
+
+			if got, want := swapped.String(), fmt.Sprintf("%v", want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := vec.String(), fmt.Sprintf("%v", testSlice); got != want {
+				t.Fatalf("got original %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestVectorSwapOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).Swap(0, 3)
+}
+
+func TestVectorSplitAt(t *testing.T) {
+	var slice = make([]int, 32*32*2+5)
+	for i := range slice {
+		slice[i] = i
+	}
+	var vec = vectors.New(slice...)
+
+	for _, i := range []int{0, 1, 31, 32, 33, 64, 1024, 1056, 32 * 32 * 2, len(slice) - 1, len(slice)} {
+		i := i
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var prefix, suffix = vec.SplitAt(i)
+			if got, want := prefix.String(), fmt.Sprintf("%v", slice[:i]); got != want {
+				t.Fatalf("got prefix %s, want %s", got, want)
+			}
+			if got, want := suffix.String(), fmt.Sprintf("%v", slice[i:]); got != want {
+				t.Fatalf("got suffix %s, want %s", got, want)
+			}
+
+			// Both halves must have the same layout as vectors built with
+			// Conj so they can continue to be grown.
+			if got, want := vectors.HashComparable(prefix.Conj(-1)), vectors.HashComparable(vectors.New(append(slice[:i:i], -1)...)); got != want {
+				t.Fatalf("got prefix hash %d after Conj, want %d", got, want)
+			}
+			if got, want := vectors.HashComparable(suffix.Conj(-1)), vectors.HashComparable(vectors.New(append(slice[i:len(slice):len(slice)], -1)...)); got != want {
+				t.Fatalf("got suffix hash %d after Conj, want %d", got, want)
+			}
+		})
+	}
+
+	if got, want := vec.String(), fmt.Sprintf("%v", slice); got != want {
+		t.Fatalf("got original %s, want %s", got, want)
+	}
+}
+
+func TestVectorSplitAtOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).SplitAt(4)
+}
+
+func TestVectorCopyTo(t *testing.T) {
+	var vec = vectors.New(testSlice...)
+
+	for _, size := range []int{0, 1, 32, 40, len(testSlice), len(testSlice) + 10} {
+		var dst = make([]int, size)
+		var n = vec.CopyTo(dst)
+		if got, want := n, min(size, len(testSlice)); got != want {
+			t.Fatalf("got %d values copied, want %d", got, want)
+		}
+		for i := 0; i < n; i++ {
+			if dst[i] != testSlice[i] {
+				t.Fatalf("want element %d at index %d, got %d", testSlice[i], i, dst[i])
+			}
+		}
+		for i := n; i < size; i++ {
+			if dst[i] != 0 {
+				t.Fatalf("got element %d written past the end at index %d", dst[i], i)
+			}
+		}
+	}
+}
+
+func TestVectorTruncate(t *testing.T) {
+	for _, size := range []int{0, 1, 33, 64, 65, 32*32 + 32, 32*32 + 33, 32*32*32 + 40} {
+		var slice = make([]int, size)
+		for i := range slice {
+			slice[i] = i
+		}
+		var vec = vectors.New(slice...)
+
+		for n := 0; n <= size; n += 1 + n/5 {
+			var truncated = vec.Truncate(n)
+			if got, want := truncated.Len(), n; got != want {
+				t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+			}
+			if got, want := truncated.Stats(), vectors.New(slice[:n]...).Stats(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got stats %+v truncating %d to %d, want %+v", got, size, n, want)
+			}
+
+			// Growing the truncated vector must not affect the original.
+			truncated = truncated.Conj(-1)
+			for i := 0; i < n; i++ {
+				if truncated.Nth(i) != i {
+					t.Fatalf("want element %d at index %d, got %d", i, i, truncated.Nth(i))
+				}
+			}
+			if got := truncated.Nth(n); got != -1 {
+				t.Fatalf("got Nth(%d)=%d after Conj, want -1", n, got)
+			}
+		}
+
+		for i := range slice {
+			if vec.Nth(i) != i {
+				t.Fatalf("want element %d at index %d of the original, got %d", i, i, vec.Nth(i))
+			}
+		}
+	}
+}
+
+func TestVectorTruncateOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).Truncate(4)
+}
+
+func TestVectorGrow(t *testing.T) {
+	for _, size := range []int{0, 1, 32, 33, 64, 32*32 + 33} {
+		for _, n := range []int{size, size + 1, size + 31, size + 32, size + 100, size + 32*32*2} {
+			var slice = make([]int, size)
+			for i := range slice {
+				slice[i] = i + 1
+			}
+			var vec = vectors.New(slice...)
+
+			var grown = vec.GrowFill(n, -1)
+			var want = append(slice, make([]int, n-size)...)
+			for i := size; i < n; i++ {
+				want[i] = -1
+			}
+			if got, want := grown.String(), fmt.Sprintf("%v", want); got != want {
+				t.Fatalf("got %s growing %d to %d, want %s", got, size, n, want)
+			}
+			if got, want := grown.Stats(), vectors.New(want...).Stats(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got stats %+v growing %d to %d, want %+v", got, size, n, want)
+			}
+
+			// The shared fill leaves must not be changed by later updates.
+			if n > size {
+				var tvec = grown.Assoc(n-1, -2).Transient()
+				tvec.Assoc(size, -3)
+				var updated = tvec.Persistent()
+				if got, want := vectors.CountIf(updated, func(x int) bool { return x == -1 }), n-size-2; n-size > 1 && got != want {
+					t.Fatalf("got %d fill values after updates, want %d", got, want)
+				}
+				if got, want := vectors.CountIf(grown, func(x int) bool { return x == -1 }), n-size; got != want {
+					t.Fatalf("got %d fill values in the grown vector after updates, want %d", got, want)
+				}
+			}
+		}
+	}
+
+	if got, want := vectors.New(1).Grow(3).String(), "[1 0 0]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestVectorGrowOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).Grow(2)
+}
+
+func TestVectorFillRange(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		start, end int
+	}{
+		{"Empty", 10, 10},
+		{"Single", 10, 11},
+		{"WithinLeaf", 1, 20},
+		{"AcrossLeaves", 20, 50},
+		{"IntoTail", 50, 65},
+		{"Everything", 0, 65},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var vec = vectors.New(testSlice...)
+			var filled = vec.FillRange(tc.start, tc.end, 0)
+
+			var want = append([]int{}, testSlice...)
+			for i := tc.start; i < tc.end; i++ {
+				want[i] = 0
+			}
+			if got, want := filled.String(), fmt.Sprintf("%v", want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := vec.String(), fmt.Sprintf("%v", testSlice); got != want {
+				t.Fatalf("got original %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestVectorFillRangeOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).FillRange(2, 1, 0)
+}
+
+func TestVectorReplaceRange(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		start, end  int
+		replacement []int
+	}{
+		{"Nothing", 10, 10, []int{}},
+		{"Insert", 10, 10, []int{-1, -2}},
+		{"Shrink", 10, 50, []int{-1}},
+		{"SameLength", 10, 13, []int{-1, -2, -3}},
+		{"Grow", 10, 11, testSlice},
+		{"Prepend", 0, 0, []int{-1}},
+		{"Append", 65, 65, []int{-1}},
+		{"Everything", 0, 65, []int{-1}},
+		{"Remove", 0, 65, []int{}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var vec = vectors.New(testSlice...)
+			var replaced = vec.ReplaceRange(tc.start, tc.end, vectors.New(tc.replacement...))
+
+			var want = append([]int{}, testSlice[:tc.start]...)
+			want = append(want, tc.replacement...)
+			want = append(want, testSlice[tc.end:]...)
+			if got, want := replaced.String(), fmt.Sprintf("%v", want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := vec.String(), fmt.Sprintf("%v", testSlice); got != want {
+				t.Fatalf("got original %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestVectorReplaceRangeOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).ReplaceRange(1, 4, vectors.New(0))
+}
+
+func TestVectorRemoveRange(t *testing.T) {
+	var slice = make([]int, 32*32+40)
+	for i := range slice {
+		slice[i] = i
+	}
+
+	var testCases = []struct {
+		name       string
+		start, end int
+	}{
+		{"Nothing", 10, 10},
+		{"Single", 10, 11},
+		{"Middle", 10, 500},
+		{"Prefix", 0, 100},
+		{"AlignedPrefix", 0, 64},
+		{"AlignedMiddle", 64, 512},
+		{"AlignedToTail", 32, 32 * 32},
+		{"Suffix", 100, len(slice)},
+		{"Everything", 0, len(slice)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var vec = vectors.New(slice...)
+			var removed = vec.RemoveRange(tc.start, tc.end)
+
+			var want = append([]int{}, slice[:tc.start]...)
+			want = append(want, slice[tc.end:]...)
+			if got, want := removed.String(), fmt.Sprintf("%v", want); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := removed.Stats(), vectors.New(want...).Stats(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got stats %+v, want %+v", got, want)
+			}
+			if got, want := vec.String(), fmt.Sprintf("%v", slice); got != want {
+				t.Fatalf("got original %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestVectorRemoveRangeSharesLeaves(t *testing.T) {
+	var slice = make([]int, 32*32+40)
+	for i := range slice {
+		slice[i] = i
+	}
+
+	var vec = vectors.New(slice...)
+	var removed = vec.RemoveRange(64, 512)
+	for i := 0; i < removed.Len(); i += 32 {
+		var from = i
+		if i >= 64 {
+			from = i + 512 - 64
+		}
+		var got, _ = removed.LeafAt(i)
+		var want, _ = vec.LeafAt(from)
+		if &got[0] != &want[0] {
+			t.Fatalf("got a new leaf at index %d, want the leaf of the original vector at %d", i, from)
+		}
+	}
+}
+
+func TestVectorRemoveRangeOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	vectors.New(1, 2, 3).RemoveRange(-1, 2)
+}
+
 func TestVectorString(t *testing.T) {
 	type testStruct struct {
 		name string
@@ -174,6 +638,43 @@ func TestVectorString(t *testing.T) {
 	if got, want := fmt.Sprintf("%v", structSlice), structVec.String(); got != want {
 		t.Errorf("got %s, want %s", got, want)
 	}
+
+	if got, want := fmt.Sprintf("%v", testSlice), vectors.New(testSlice...).Transient().String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestVectorFormat(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+
+	var testCases = []struct {
+		name   string
+		format string
+		value  any
+		want   string
+	}{
+		{"EmptyV", "%v", vectors.New[int](), "[]"},
+		{"EmptyGoSyntax", "%#v", vectors.New[int](), "vectors.New[int]()"},
+		{"Ints", "%v", vectors.New(1, 2, 3), "[1 2 3]"},
+		{"IntsHex", "%x", vectors.New(10, 11, 255), "[a b ff]"},
+		{"IntsWidth", "%03d", vectors.New(1, 2), "[001 002]"},
+		{"Strings", "%q", vectors.New("a", "b"), `["a" "b"]`},
+		{"StringsGoSyntax", "%#v", vectors.New("a", "b"), `vectors.New[string]("a", "b")`},
+		{"StructPlus", "%+v", vectors.New(point{1, 2}), "[{X:1 Y:2}]"},
+		{"DeepTrie", "%v", vectors.New(testSlice...), fmt.Sprintf("%v", testSlice)},
+		{"DeepTrieHex", "%x", vectors.New(testSlice...), fmt.Sprintf("%x", testSlice)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := fmt.Sprintf(tc.format, tc.value), tc.want; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		})
+	}
 }
 
 func TestTransientVectorAssoc(t *testing.T) {
@@ -198,6 +699,315 @@ func TestTransientVectorConj(t *testing.T) {
 	}
 }
 
+func TestTransientVectorConjSlice(t *testing.T) {
+	var slice = make([]int, 32*32*2+40)
+	for i := range slice {
+		slice[i] = i
+	}
+
+	for _, start := range []int{0, 1, 32, 33, 100} {
+		for _, n := range []int{0, 1, 31, 32, 33, 64, 32*32 + 1, len(slice) - start} {
+			var vec = vectors.New(slice[:start]...)
+			var tvec = vec.Transient()
+			tvec.ConjSlice(slice[start : start+n])
+			var got = tvec.Persistent()
+			if got, want := got.String(), fmt.Sprintf("%v", slice[:start+n]); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := got.Stats(), vectors.New(slice[:start+n]...).Stats(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got stats %+v, want %+v", got, want)
+			}
+			if got, want := vec.Len(), start; got != want {
+				t.Fatalf("got original Len()=%d, want %d", got, want)
+			}
+		}
+	}
+}
+
+func TestTransientVectorPop(t *testing.T) {
+	var slice = make([]int, 32*32+40)
+	for i := range slice {
+		slice[i] = i
+	}
+
+	var vec = vectors.New(slice...)
+	var tvec = vec.Transient()
+	for n := len(slice); n > 0; n-- {
+		if got, want := tvec.Len(), n; got != want {
+			t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+		}
+		if got, want := tvec.Peek(), slice[n-1]; got != want {
+			t.Fatalf("got Peek()=%d, want Peek()=%d", got, want)
+		}
+		tvec.Pop()
+	}
+
+	// Popping and then growing again must not affect the original vector.
+	tvec = vec.Transient()
+	for i := 0; i < 100; i++ {
+		tvec.Pop()
+	}
+	for i := 0; i < 100; i++ {
+		tvec.Conj(-i)
+	}
+	if got, want := vec.String(), fmt.Sprintf("%v", slice); got != want {
+		t.Fatalf("got original %s, want %s", got, want)
+	}
+	if got, want := tvec.Persistent().Nth(len(slice)-1), -99; got != want {
+		t.Fatalf("got last value %d, want %d", got, want)
+	}
+}
+
+func TestTransientVectorPopEmpty(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	var tvec = vectors.New[int]().Transient()
+	tvec.Pop()
+}
+
+func TestTransientFromSlice(t *testing.T) {
+	var slice = make([]int, 32*32*2+5)
+	for i := range slice {
+		slice[i] = i
+	}
+
+	for _, n := range []int{0, 1, 32, 33, 64, 32*32 + 32, len(slice)} {
+		n := n
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			var s = append([]int{}, slice[:n]...)
+			var tvec = vectors.TransientFromSlice(s)
+			if got, want := tvec.String(), fmt.Sprint(slice[:n]); got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+
+			tvec.Conj(-1)
+			var vec = tvec.Persistent()
+			var want = vectors.New(append(slice[:n:n], -1)...)
+			if got, want := vec.Stats(), want.Stats(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got stats %+v, want %+v", got, want)
+			}
+			if !vectors.Equal(vec, want) {
+				t.Fatalf("got %v, want %v", vec, want)
+			}
+		})
+	}
+}
+
+func TestTransientVectorToSlice(t *testing.T) {
+	for _, n := range []int{0, 1, 32, 33, len(testSlice)} {
+		var tvec = vectors.New(testSlice[:n]...).Transient()
+		var got = tvec.ToSlice()
+		if got, want := fmt.Sprint(got), fmt.Sprint(testSlice[:n]); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+
+		// The slice must not share memory with the transient.
+		if n > 0 {
+			got[n-1] = -1
+			if got, want := tvec.Peek(), testSlice[n-1]; got != want {
+				t.Fatalf("got Peek()=%d after changing the slice, want %d", got, want)
+			}
+		}
+	}
+}
+
+func TestTransientVectorReserve(t *testing.T) {
+	var slice = make([]int, 32*32*2+5)
+	for i := range slice {
+		slice[i] = i
+	}
+
+	for _, start := range []int{0, 1, 33, 32*32 + 32} {
+		for _, n := range []int{0, 1, 32, 33, 64, 32*32 + 32, len(slice)} {
+			if n < start {
+				continue
+			}
+
+			var tvec = vectors.New(slice[:start]...).Transient()
+			tvec.Reserve(n)
+			if got, want := tvec.Stats().Depth, vectors.New(slice[:n]...).Stats().Depth; got < want {
+				t.Fatalf("got depth %d after reserving %d values, want at least %d", got, n, want)
+			}
+			tvec.ConjSlice(slice[start:n])
+			for i := 0; i < n; i++ {
+				if got := tvec.Nth(i); got != slice[i] {
+					t.Fatalf("want element %d at index %d, got %d", slice[i], i, got)
+				}
+			}
+
+			var got = tvec.Persistent()
+			var want = vectors.New(slice[:n]...)
+			if got, want := got.Stats(), want.Stats(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("got stats %+v reserving %d from %d, want %+v", got, n, start, want)
+			}
+			if !vectors.Equal(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+		}
+	}
+}
+
+func TestTransientVectorReservePop(t *testing.T) {
+	var tvec = vectors.New(testSlice...).Transient()
+	tvec.Reserve(32 * 32 * 32)
+	for i := 0; i < 33; i++ {
+		tvec.Pop()
+	}
+
+	var got = tvec.Persistent()
+	var want = vectors.New(testSlice[:len(testSlice)-33]...)
+	if got, want := got.Stats(), want.Stats(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got stats %+v, want %+v", got, want)
+	}
+	if !vectors.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestTransientVectorReadsDoNotInvalidate(t *testing.T) {
+	var tvec = vectors.New(testSlice...).Transient()
+	_ = tvec.Len()
+	_ = tvec.Nth(0)
+	_ = tvec.Peek()
+	_ = tvec.String()
+	_ = tvec.Stats()
+	_ = tvec.ToSlice()
+
+	tvec.Conj(66)
+	if got, want := tvec.Persistent().Len(), len(testSlice)+1; got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+}
+
+func TestTransientVectorCopyInvalidated(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		mutate func(tvec *vectors.TransientVector[int])
+	}{
+		{name: "Assoc", mutate: func(tvec *vectors.TransientVector[int]) { tvec.Assoc(0, 42) }},
+		{name: "Conj", mutate: func(tvec *vectors.TransientVector[int]) { tvec.Conj(42) }},
+		{name: "ConjSlice", mutate: func(tvec *vectors.TransientVector[int]) { tvec.ConjSlice([]int{42}) }},
+		{name: "Pop", mutate: func(tvec *vectors.TransientVector[int]) { tvec.Pop() }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var tvec = vectors.New(testSlice...).Transient()
+			var copied = tvec
+			tc.mutate(&tvec)
+			_ = tvec.Len()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+			}()
+			copied.Len()
+		})
+	}
+}
+
+func TestTransientVectorPersistentInvalidated(t *testing.T) {
+	var tvec = vectors.New(testSlice...).Transient()
+	tvec.Persistent()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	tvec.Conj(42)
+}
+
+func TestTransientVectorPersistentReadable(t *testing.T) {
+	// Making a transient vector persistent freezes it, leaving the transient
+	// and its copies readable.
+	var tvec = vectors.New(testSlice...).Transient()
+	tvec.Conj(66)
+	var copied = tvec
+	var vec = tvec.Persistent()
+
+	for _, tv := range []vectors.TransientVector[int]{tvec, copied} {
+		if got, want := tv.Len(), vec.Len(); got != want {
+			t.Fatalf("got Len()=%d after Persistent, want Len()=%d", got, want)
+		}
+		if got, want := tv.String(), vec.String(); got != want {
+			t.Fatalf("got %s after Persistent, want %s", got, want)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("got nil panic when one was expected")
+		}
+	}()
+	copied.Assoc(0, 42)
+}
+
+func TestTransientVectorHandoff(t *testing.T) {
+	var tvec = vectors.New[int]().Transient()
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		tvec.ConjSlice(testSlice[:40])
+	}()
+	<-done
+	tvec.ConjSlice(testSlice[40:])
+
+	if got, want := tvec.Persistent().String(), fmt.Sprint(testSlice); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestTransientVectorConcurrentMutation(t *testing.T) {
+	var testCases = []struct {
+		name string
+		use  func(tvec *vectors.TransientVector[int])
+	}{
+		{"Conj", func(tvec *vectors.TransientVector[int]) { tvec.Conj(0) }},
+		{"Assoc", func(tvec *vectors.TransientVector[int]) { tvec.Assoc(0, 0) }},
+		{"Pop", func(tvec *vectors.TransientVector[int]) { tvec.Pop() }},
+		{"Nth", func(tvec *vectors.TransientVector[int]) { tvec.Nth(0) }},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var tvec = vectors.New(testSlice...).Transient()
+			var panicked = make(chan any)
+			vectors.Mutating(&tvec, func() {
+				go func() {
+					defer func() { panicked <- recover() }()
+					tc.use(&tvec)
+				}()
+
+				var r = <-panicked
+				if r == nil {
+					t.Fatalf("got nil panic when one was expected")
+				}
+				if msg := r.(string); !strings.Contains(msg, "concurrent") {
+					t.Fatalf("got panic %q, want a panic about concurrent use", msg)
+				}
+			})
+		})
+	}
+}
+
+func TestTransientVectorZeroValue(t *testing.T) {
+	var tvec vectors.TransientVector[int]
+	for _, value := range testSlice {
+		tvec.Conj(value)
+	}
+
+	if got, want := tvec.Persistent().String(), fmt.Sprint(testSlice); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
 func FuzzVectorNth(f *testing.F) {
 	f.Fuzz(func(t *testing.T, b []byte) {
 		var vec = vectors.New(b...)
@@ -293,16 +1103,16 @@ func FuzzTransientVectorAssoc(f *testing.F) {
 
 		var tvec = vec.Transient()
 
-		var result = tvec.Assoc(index, value)
+		tvec.Assoc(index, value)
 
-		if got, want := vec.Len(), result.Len(); got != want {
-			t.Fatalf("got vec.Len() == %d != result.Len(), want vec.Len() == %d == result.Len()", got, want)
+		if got, want := vec.Len(), tvec.Len(); got != want {
+			t.Fatalf("got vec.Len() == %d != tvec.Len(), want vec.Len() == %d == tvec.Len()", got, want)
 		}
 		if got := vec.Nth(index); got != originalValue {
 			t.Fatalf("got vec.Nth(index) == %v, want vec.Nth(index) == %v", got, originalValue)
 		}
-		if got, want := result.Nth(index), value; got != want {
-			t.Fatalf("got result.Nth(index) == %v, result.Nth(index) == %v", got, want)
+		if got, want := tvec.Nth(index), value; got != want {
+			t.Fatalf("got tvec.Nth(index) == %v, tvec.Nth(index) == %v", got, want)
 		}
 	})
 }
@@ -320,14 +1130,14 @@ func FuzzTransientVectorConj(f *testing.F) {
 	f.Fuzz(func(t *testing.T, init []byte, value byte) {
 		var vec = vectors.New(init...)
 		var tvec = vec.Transient()
-		var result = tvec.Conj(value)
+		tvec.Conj(value)
 
 		if got, want := vec.Len(), len(init); got != want {
 			t.Fatalf("expected source to not be modified, got vec.Len() == %d, want vec.Len() == %d", got, want)
 		}
 
-		if got, want := result.Len(), vec.Len()+1; got != want {
-			t.Fatalf("expected result one elem longer, got result.Len() == %d, want result.Len() == %d", got, want)
+		if got, want := tvec.Len(), vec.Len()+1; got != want {
+			t.Fatalf("expected tvec one elem longer, got tvec.Len() == %d, want tvec.Len() == %d", got, want)
 		}
 	})
 }
@@ -351,7 +1161,7 @@ func newBenchmarkVec(n int) vectors.Vector[int] {
 func newBenchmarkTransientVector(n int) vectors.TransientVector[int] {
 	vec := vectors.TransientVector[int]{}
 	for i := 0; i < n; i++ {
-		vec = vec.Conj(i + 1)
+		vec.Conj(i + 1)
 	}
 	return vec
 }
@@ -375,6 +1185,20 @@ func BenchmarkNthPersistent(b *testing.B) {
 	}
 }
 
+func BenchmarkNthDepth(b *testing.B) {
+	// Sizes whose trees are 0, 1, 2 and 3 levels deep under the root, to
+	// cover both the unrolled and the looping walks of the tree.
+	for depth, n := range []int{64, 32*32 + 32, 32*32*32 + 32, 32*32*32*32 + 32} {
+		vec := newBenchmarkVec(n)
+		b.Run(fmt.Sprintf("%d", depth), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// Skip the tail so every lookup walks the tree.
+				vec.Nth(i % (n - 32))
+			}
+		})
+	}
+}
+
 func BenchmarkNthTransient(b *testing.B) {
 	for _, n := range benchmarkCases {
 		tvec := newBenchmarkTransientVector(n)
@@ -415,7 +1239,7 @@ func BenchmarkAssocTransient(b *testing.B) {
 			tvec := newBenchmarkTransientVector(n)
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				tvec = tvec.Assoc(i%n, i)
+				tvec.Assoc(i%n, i)
 			}
 		})
 	}
@@ -452,7 +1276,7 @@ func BenchmarkConjTransient(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				tvec := vectors.New[int]().Transient()
 				for i := 0; i < n; i++ {
-					tvec = tvec.Conj(i)
+					tvec.Conj(i)
 				}
 			}
 		})
@@ -0,0 +1,43 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML implements the yaml.Marshaler interface. The vector is encoded
+// as a sequence of its values.
+func (v Vector[T]) MarshalYAML() (any, error) {
+	return v.appendTo(make([]T, 0, v.count)), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, replacing the
+// contents of v with the values of a sequence node. A null node decodes to an
+// empty vector. The decoded values are packed directly into full leaves
+// rather than appended one at a time.
+func (v *Vector[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		*v = Vector[T]{}
+		return nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("vectors: cannot unmarshal YAML node at line %d into a vector", node.Line)
+	}
+
+	var values = make([]T, len(node.Content))
+	for i, item := range node.Content {
+		if err := item.Decode(&values[i]); err != nil {
+			return fmt.Errorf("vectors: value %d: %w", i, err)
+		}
+	}
+	*v = fromSlice(values)
+	return nil
+}
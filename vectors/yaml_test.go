@@ -0,0 +1,99 @@
+package vectors_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+	"gopkg.in/yaml.v3"
+)
+
+func TestVectorYAML(t *testing.T) {
+	var deep = make([]int, 32*32+5)
+	for i := range deep {
+		deep[i] = i
+	}
+
+	var testCases = []struct {
+		name  string
+		slice []int
+	}{
+		{"Empty", []int{}},
+		{"Tail", []int{1, 2, 3}},
+		{"Trie", deep},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var data, err = yaml.Marshal(vectors.New(tc.slice...))
+			if err != nil {
+				t.Fatalf("got marshal error %v", err)
+			}
+
+			var got vectors.Vector[int]
+			if err := yaml.Unmarshal(data, &got); err != nil {
+				t.Fatalf("got unmarshal error %v", err)
+			}
+			if got.Len() != len(tc.slice) {
+				t.Fatalf("got Len()=%d, want Len()=%d", got.Len(), len(tc.slice))
+			}
+			for i := range tc.slice {
+				if got.Nth(i) != tc.slice[i] {
+					t.Fatalf("want element %d at index %d, got %d", tc.slice[i], i, got.Nth(i))
+				}
+			}
+		})
+	}
+}
+
+func TestVectorYAMLConfig(t *testing.T) {
+	type config struct {
+		Hosts vectors.Vector[string]              `yaml:"hosts"`
+		Ports vectors.Vector[int]                 `yaml:"ports"`
+		Grid  vectors.Vector[vectors.Vector[int]] `yaml:"grid"`
+	}
+
+	var data = []byte("hosts: [a, b]\nports: ~\ngrid:\n  - [1, 2]\n  - []\n")
+	var c config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		t.Fatalf("got unmarshal error %v", err)
+	}
+	if got, want := c.Hosts.String(), "[a b]"; got != want {
+		t.Fatalf("got hosts %s, want %s", got, want)
+	}
+	if got, want := c.Ports.Len(), 0; got != want {
+		t.Fatalf("got ports Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := c.Grid.String(), "[[1 2] []]"; got != want {
+		t.Fatalf("got grid %s, want %s", got, want)
+	}
+
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		t.Fatalf("got marshal error %v", err)
+	}
+	if got, want := string(out), "hosts:\n    - a\n    - b\nports: []\ngrid:\n    - - 1\n      - 2\n    - []\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestVectorYAMLErrors(t *testing.T) {
+	var testCases = []struct {
+		name string
+		data string
+	}{
+		{"Mapping", "a: 1"},
+		{"Scalar", "1"},
+		{"WrongType", "[1, x]"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var v vectors.Vector[int]
+			if err := yaml.Unmarshal([]byte(tc.data), &v); err == nil {
+				t.Fatalf("got %v and nil error, want an error", v)
+			}
+		})
+	}
+}
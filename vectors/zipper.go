@@ -0,0 +1,144 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package vectors
+
+// Zipper holds a position within a vector and supports editing the vector
+// around that position, similarly to the cursor of a text editor. The position
+// is always between two values (or at either end of the vector), so Insert
+// adds a value before the position and Delete removes the value after it.
+//
+// Values near the position are kept in a gap buffer, so moving the position
+// by one and editing around it take amortized constant time. The edits are
+// committed back into a persistent vector by calling Vector, which rebuilds
+// only the part of the vector after the earliest edit.
+//
+// A Zipper is mutable and must not be used by multiple goroutines at the same
+// time. The vector a zipper was made from is never modified by it.
+type Zipper[T any] struct {
+	base  Vector[T]
+	lo    int // Values [0, lo) of base come before left
+	hi    int // Values [hi, base.count) of base come after right
+	left  []T // Values before the position, in order
+	right []T // Values after the position, in reverse order
+}
+
+// Zipper creates a new zipper over v with its position at the start of v.
+func (v Vector[T]) Zipper() *Zipper[T] {
+	return &Zipper[T]{base: v}
+}
+
+// Len returns the number of values in the vector being edited.
+func (z *Zipper[T]) Len() int {
+	return z.lo + len(z.left) + len(z.right) + z.base.count - z.hi
+}
+
+// Pos returns the number of values before the position of the zipper.
+func (z *Zipper[T]) Pos() int {
+	return z.lo + len(z.left)
+}
+
+// fill ensures that right holds at least one value if there are any values
+// after the position, returning false if there aren't.
+func (z *Zipper[T]) fill() bool {
+	if len(z.right) > 0 {
+		return true
+	}
+	if z.hi == z.base.count {
+		return false
+	}
+	z.right = append(z.right, z.base.Nth(z.hi))
+	z.hi += 1
+	return true
+}
+
+// Value returns the value after the position of the zipper, and false if the
+// position is at the end of the vector.
+func (z *Zipper[T]) Value() (T, bool) {
+	if !z.fill() {
+		var zero T
+		return zero, false
+	}
+	return z.right[len(z.right)-1], true
+}
+
+// Right moves the position of the zipper forward past one value, returning
+// false without moving if the position is at the end of the vector.
+func (z *Zipper[T]) Right() bool {
+	if !z.fill() {
+		return false
+	}
+	z.left = append(z.left, z.right[len(z.right)-1])
+	z.right = z.right[:len(z.right)-1]
+	return true
+}
+
+// Left moves the position of the zipper back past one value, returning false
+// without moving if the position is at the start of the vector.
+func (z *Zipper[T]) Left() bool {
+	if len(z.left) == 0 {
+		if z.lo == 0 {
+			return false
+		}
+		z.lo -= 1
+		z.right = append(z.right, z.base.Nth(z.lo))
+		return true
+	}
+	z.right = append(z.right, z.left[len(z.left)-1])
+	z.left = z.left[:len(z.left)-1]
+	return true
+}
+
+// Set replaces the value after the position of the zipper, returning false
+// without changing anything if the position is at the end of the vector.
+func (z *Zipper[T]) Set(value T) bool {
+	if !z.fill() {
+		return false
+	}
+	z.right[len(z.right)-1] = value
+	return true
+}
+
+// Insert adds a value at the position of the zipper, moving the position
+// forward past the new value.
+func (z *Zipper[T]) Insert(value T) {
+	z.left = append(z.left, value)
+}
+
+// Delete removes the value after the position of the zipper, returning false
+// without changing anything if the position is at the end of the vector.
+func (z *Zipper[T]) Delete() bool {
+	if !z.fill() {
+		return false
+	}
+	z.right = z.right[:len(z.right)-1]
+	return true
+}
+
+// Vector returns a persistent vector containing every edit made through the
+// zipper so far. The values before the earliest edit are shared with the
+// original vector, while the values after it are copied. The zipper may
+// continue to be used afterwards.
+func (z *Zipper[T]) Vector() Vector[T] {
+	if len(z.left) == 0 && len(z.right) == 0 && z.lo == z.hi {
+		return z.base
+	}
+
+	var tv = z.base.take(z.lo).Transient()
+	for _, value := range z.left {
+		tv.Conj(value)
+	}
+	for i := len(z.right) - 1; i >= 0; i-- {
+		tv.Conj(z.right[i])
+	}
+	for seq := newSeq(z.base, z.hi); !seq.IsEmpty(); seq = seq.ChunkRest() {
+		for _, value := range seq.Chunk() {
+			tv.Conj(value)
+		}
+	}
+
+	var pos = z.Pos()
+	*z = Zipper[T]{base: tv.Persistent(), lo: pos, hi: pos}
+	return z.base
+}
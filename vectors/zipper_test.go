@@ -0,0 +1,92 @@
+package vectors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/toddgaunt/persistent/vectors"
+)
+
+func TestZipperNavigation(t *testing.T) {
+	var z = vectors.New(1, 2, 3).Zipper()
+
+	if z.Left() {
+		t.Fatalf("got Left()=true at the start")
+	}
+	for i := 1; i <= 3; i++ {
+		if got, ok := z.Value(); !ok || got != i {
+			t.Fatalf("got Value()=(%d, %v), want (%d, true)", got, ok, i)
+		}
+		if !z.Right() {
+			t.Fatalf("got Right()=false at position %d", z.Pos())
+		}
+	}
+	if _, ok := z.Value(); ok {
+		t.Fatalf("got a value at the end")
+	}
+	if z.Right() {
+		t.Fatalf("got Right()=true at the end")
+	}
+	for i := 3; i >= 1; i-- {
+		if !z.Left() {
+			t.Fatalf("got Left()=false at position %d", z.Pos())
+		}
+		if got, ok := z.Value(); !ok || got != i {
+			t.Fatalf("got Value()=(%d, %v), want (%d, true)", got, ok, i)
+		}
+	}
+}
+
+func TestZipperEdits(t *testing.T) {
+	var original = vectors.New(testSlice...)
+	var z = original.Zipper()
+
+	for i := 0; i < 40; i++ {
+		z.Right()
+	}
+	z.Set(-1)    // [... 40 -1 42 ...]
+	z.Insert(-2) // [... 40 -2 -1 42 ...]
+	z.Right()    // past -1
+	z.Delete()   // removes 42
+	z.Left()     // before -1
+	z.Left()     // before -2
+	z.Left()     // before 40
+	z.Delete()   // removes 40
+
+	var want = append([]int{}, testSlice[:39]...)
+	want = append(want, -2, -1)
+	want = append(want, testSlice[42:]...)
+
+	if got, want := z.Len(), len(want); got != want {
+		t.Fatalf("got Len()=%d, want Len()=%d", got, want)
+	}
+	if got, want := z.Vector().String(), fmt.Sprintf("%v", want); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := original.String(), fmt.Sprintf("%v", testSlice); got != want {
+		t.Fatalf("got original %s, want %s", got, want)
+	}
+
+	// The zipper keeps its position after committing and can keep editing.
+	if got, want := z.Pos(), 39; got != want {
+		t.Fatalf("got Pos()=%d after Vector(), want %d", got, want)
+	}
+	z.Insert(0)
+	want = append(want[:39], append([]int{0}, want[39:]...)...)
+	if got, want := z.Vector().String(), fmt.Sprintf("%v", want); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestZipperInsertEmpty(t *testing.T) {
+	var z = vectors.New[int]().Zipper()
+	for i := 0; i < 100; i++ {
+		z.Insert(i)
+	}
+	var got = z.Vector()
+	for i := 0; i < 100; i++ {
+		if got.Nth(i) != i {
+			t.Fatalf("want element %d at index %d, got %d", i, i, got.Nth(i))
+		}
+	}
+}
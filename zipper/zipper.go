@@ -0,0 +1,346 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package zipper provides Huet-style zippers over trees made of nested
+// persistent maps, vectors and lists. A zipper is a location within a tree
+// which can be moved up to the parent of the value at that location, down to
+// its first child, or left and right to its siblings, and which can replace
+// the value at that location. Edits are carried along as the location moves,
+// and are committed to new versions of every map, vector and list between the
+// location and the root of the tree by calling Root. The tree a zipper was
+// made from is never modified by it.
+//
+// As with the paths package, maps are any values with GetOK(key),
+// Assoc(key, value) and All() methods, such as those of the maps and
+// sortedmap packages, with the children of a map being its values in the order
+// All yields them. Vectors are any values with Len(), Nth(index) and
+// Assoc(index, value) methods, such as those of the vectors package. Lists are
+// any values with Len(), All(), Insert(index, value) and Remove(index)
+// methods, such as those of the lists package. Any other value is a leaf of
+// the tree.
+package zipper
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// container is a map, vector or list holding the children of a location.
+type container interface {
+	// len returns the number of children.
+	len() int
+	// key returns the key or index of child i.
+	key(i int) any
+	// get returns child i.
+	get(i int) reflect.Value
+	// set returns a new container with child i replaced by v, which must be
+	// assignable to elem.
+	set(i int, v reflect.Value) container
+	// elem returns the type of the children.
+	elem() reflect.Type
+	// value returns the map, vector or list itself.
+	value() reflect.Value
+}
+
+type vectorContainer struct {
+	v reflect.Value
+}
+
+func (c vectorContainer) len() int {
+	return int(c.v.MethodByName("Len").Call(nil)[0].Int())
+}
+
+func (c vectorContainer) key(i int) any {
+	return i
+}
+
+func (c vectorContainer) get(i int) reflect.Value {
+	return c.v.MethodByName("Nth").Call([]reflect.Value{reflect.ValueOf(i)})[0]
+}
+
+func (c vectorContainer) set(i int, v reflect.Value) container {
+	return vectorContainer{c.v.MethodByName("Assoc").Call([]reflect.Value{reflect.ValueOf(i), v})[0]}
+}
+
+func (c vectorContainer) elem() reflect.Type {
+	return c.v.MethodByName("Nth").Type().Out(0)
+}
+
+func (c vectorContainer) value() reflect.Value {
+	return c.v
+}
+
+// mapContainer holds the keys of a map in the order they were iterated over
+// when the zipper moved down into it. Only the values of a map are ever
+// replaced, so its keys never change.
+type mapContainer struct {
+	v    reflect.Value
+	keys []reflect.Value
+}
+
+func (c mapContainer) len() int {
+	return len(c.keys)
+}
+
+func (c mapContainer) key(i int) any {
+	return c.keys[i].Interface()
+}
+
+func (c mapContainer) get(i int) reflect.Value {
+	return c.v.MethodByName("GetOK").Call([]reflect.Value{c.keys[i]})[0]
+}
+
+func (c mapContainer) set(i int, v reflect.Value) container {
+	return mapContainer{c.v.MethodByName("Assoc").Call([]reflect.Value{c.keys[i], v})[0], c.keys}
+}
+
+func (c mapContainer) elem() reflect.Type {
+	return c.v.MethodByName("GetOK").Type().Out(0)
+}
+
+func (c mapContainer) value() reflect.Value {
+	return c.v
+}
+
+// listContainer holds the items of a list, since finding an item by index
+// within a list takes time proportional to the index.
+type listContainer struct {
+	v     reflect.Value
+	items []reflect.Value
+}
+
+func (c listContainer) len() int {
+	return len(c.items)
+}
+
+func (c listContainer) key(i int) any {
+	return i
+}
+
+func (c listContainer) get(i int) reflect.Value {
+	return c.items[i]
+}
+
+func (c listContainer) set(i int, v reflect.Value) container {
+	var index = reflect.ValueOf(i)
+	var removed = c.v.MethodByName("Remove").Call([]reflect.Value{index})[0]
+	var items = slices.Clone(c.items)
+	items[i] = v
+	return listContainer{removed.MethodByName("Insert").Call([]reflect.Value{index, v})[0], items}
+}
+
+func (c listContainer) elem() reflect.Type {
+	return c.v.MethodByName("Insert").Type().In(1)
+}
+
+func (c listContainer) value() reflect.Value {
+	return c.v
+}
+
+// collect returns the first value yielded by each step of the iterator
+// returned by calling the All method of v.
+func collect(v reflect.Value) []reflect.Value {
+	var values []reflect.Value
+	var all = v.MethodByName("All").Call(nil)[0]
+	var yield = reflect.MakeFunc(all.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		values = append(values, args[0])
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+	all.Call([]reflect.Value{yield})
+	return values
+}
+
+// containerOf returns v as a container, and true if it is a map, vector or
+// list.
+func containerOf(v reflect.Value) (container, bool) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, false
+	}
+
+	var has = func(names ...string) bool {
+		for _, name := range names {
+			if !v.MethodByName(name).IsValid() {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case has("GetOK", "Assoc", "All"):
+		return mapContainer{v, collect(v)}, true
+	case has("Len", "Nth", "Assoc"):
+		return vectorContainer{v}, true
+	case has("Len", "All", "Insert", "Remove"):
+		return listContainer{v, collect(v)}, true
+	default:
+		return nil, false
+	}
+}
+
+// crumb records the parent of a location, so the zipper can move back up to
+// it. The parent includes every edit made to the siblings of the location.
+type crumb struct {
+	up      *crumb
+	parent  container
+	index   int  // Index of the location within parent
+	changed bool // Whether parent has been edited since the zipper moved into it
+}
+
+// Loc is a location within a tree. Like the persistent data structures it
+// moves through, a Loc is a value which is never modified by the operations
+// provided by this package, so each version of a Loc remains usable after
+// moving or editing it. The zero value of Loc is a location at the root of an
+// empty tree.
+type Loc struct {
+	node    reflect.Value
+	crumb   *crumb
+	changed bool // Whether node has been edited since the zipper moved to it
+}
+
+// New creates a new zipper with its location at root.
+func New(root any) Loc {
+	return Loc{node: reflect.ValueOf(root)}
+}
+
+// Node returns the value at the location of l.
+func (l Loc) Node() any {
+	if !l.node.IsValid() {
+		return nil
+	}
+	return l.node.Interface()
+}
+
+// Key returns the key or index of the value at the location of l within its
+// parent, and true if l isn't at the root of the tree.
+func (l Loc) Key() (any, bool) {
+	if l.crumb == nil {
+		return nil, false
+	}
+	return l.crumb.parent.key(l.crumb.index), true
+}
+
+// Down returns the location of the first child of the value at the location
+// of l, and true if the value is a map, vector or list with at least one
+// child.
+func (l Loc) Down() (Loc, bool) {
+	var parent, ok = containerOf(l.node)
+	if !ok || parent.len() == 0 {
+		return l, false
+	}
+
+	return Loc{
+		node:  parent.get(0),
+		crumb: &crumb{up: l.crumb, parent: parent, changed: l.changed},
+	}, true
+}
+
+// Up returns the location of the parent of the value at the location of l,
+// and true if l isn't at the root of the tree. Any edits made to the value at
+// l are carried up to its parent.
+func (l Loc) Up() (Loc, bool) {
+	if l.crumb == nil {
+		return l, false
+	}
+
+	var parent = l.commit()
+	return Loc{
+		node:    parent.value(),
+		crumb:   l.crumb.up,
+		changed: l.changed || l.crumb.changed,
+	}, true
+}
+
+// Left returns the location of the sibling before the value at the location of
+// l, and true if there is one.
+func (l Loc) Left() (Loc, bool) {
+	return l.move(-1)
+}
+
+// Right returns the location of the sibling after the value at the location of
+// l, and true if there is one.
+func (l Loc) Right() (Loc, bool) {
+	return l.move(1)
+}
+
+func (l Loc) move(by int) (Loc, bool) {
+	if l.crumb == nil {
+		return l, false
+	}
+	var index = l.crumb.index + by
+	if index < 0 || index >= l.crumb.parent.len() {
+		return l, false
+	}
+
+	var parent = l.commit()
+	return Loc{
+		node: parent.get(index),
+		crumb: &crumb{
+			up:      l.crumb.up,
+			parent:  parent,
+			index:   index,
+			changed: l.changed || l.crumb.changed,
+		},
+	}, true
+}
+
+// commit returns the parent of l with any edits to the value at l applied.
+func (l Loc) commit() container {
+	if !l.changed {
+		return l.crumb.parent
+	}
+	return l.crumb.parent.set(l.crumb.index, l.node)
+}
+
+// Replace returns a location with value in place of the value at the location
+// of l. An error is returned if value is of the wrong type to be held by the
+// parent of l.
+func (l Loc) Replace(value any) (Loc, error) {
+	var v = reflect.ValueOf(value)
+	if l.crumb != nil {
+		var err error
+		if v, err = convert(v, l.crumb.parent.elem()); err != nil {
+			return l, err
+		}
+	}
+
+	return Loc{node: v, crumb: l.crumb, changed: true}, nil
+}
+
+// Edit is like Replace, except the value at the location of l is replaced by
+// the result of calling f with it.
+func (l Loc) Edit(f func(value any) any) (Loc, error) {
+	return l.Replace(f(l.Node()))
+}
+
+// Root returns the root of the tree with every edit made through the zipper
+// applied to it.
+func (l Loc) Root() any {
+	for {
+		var up, ok = l.Up()
+		if !ok {
+			return l.Node()
+		}
+		l = up
+	}
+}
+
+// convert returns v as a value of type t, where a missing value is converted
+// to the zero value of t if t can be nil.
+func convert(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if !v.IsValid() {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("zipper: cannot use nil as a value of type %s", t)
+	}
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("zipper: cannot use a value of type %s as a value of type %s", v.Type(), t)
+	}
+	return v, nil
+}
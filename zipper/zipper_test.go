@@ -0,0 +1,147 @@
+package zipper_test
+
+import (
+	"testing"
+
+	"github.com/toddgaunt/persistent/lists"
+	"github.com/toddgaunt/persistent/maps"
+	"github.com/toddgaunt/persistent/sortedmap"
+	"github.com/toddgaunt/persistent/vectors"
+	"github.com/toddgaunt/persistent/zipper"
+)
+
+func must(l zipper.Loc, ok bool) func(t *testing.T) zipper.Loc {
+	return func(t *testing.T) zipper.Loc {
+		t.Helper()
+		if !ok {
+			t.Fatalf("got false when moving from %v, want true", l.Node())
+		}
+		return l
+	}
+}
+
+func TestZipperVector(t *testing.T) {
+	var root = vectors.New(vectors.New(1, 2), vectors.New(3, 4, 5))
+
+	var loc = zipper.New(root)
+	loc = must(loc.Down())(t)
+	loc = must(loc.Right())(t)
+	loc = must(loc.Down())(t)
+	loc = must(loc.Right())(t)
+	if got, want := loc.Node(), 4; got != want {
+		t.Fatalf("got Node()=%v, want Node()=%v", got, want)
+	}
+	if got, _ := loc.Key(); got != 1 {
+		t.Fatalf("got Key()=%v, want Key()=1", got)
+	}
+
+	var edited, err = loc.Edit(func(v any) any { return v.(int) * 10 })
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	edited = must(edited.Right())(t)
+	if edited, err = edited.Replace(50); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	var got = edited.Root().(vectors.Vector[vectors.Vector[int]])
+	if got, want := got.String(), "[[1 2] [3 40 50]]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := root.String(), "[[1 2] [3 4 5]]"; got != want {
+		t.Fatalf("got original %s, want %s", got, want)
+	}
+	if got := loc.Root().(vectors.Vector[vectors.Vector[int]]); got.String() != root.String() {
+		t.Fatalf("got unedited %s, want %s", got, root)
+	}
+}
+
+func TestZipperMapAndList(t *testing.T) {
+	var root = sortedmap.New[string, lists.List[int]]().
+		Assoc("a", lists.New(1, 2, 3)).
+		Assoc("b", lists.New(4))
+
+	var loc = zipper.New(root)
+	loc = must(loc.Down())(t)
+	loc = must(loc.Right())(t)
+	if got, _ := loc.Key(); got != "b" {
+		t.Fatalf("got Key()=%v, want Key()=b", got)
+	}
+	loc = must(loc.Left())(t)
+	loc = must(loc.Down())(t)
+	loc = must(loc.Right())(t)
+	loc, _ = loc.Replace(20)
+	loc = must(loc.Up())(t)
+	loc = must(loc.Right())(t)
+	loc = must(loc.Down())(t)
+	loc, _ = loc.Replace(40)
+
+	var got = loc.Root().(sortedmap.Map[string, lists.List[int]])
+	if got, want := got.String(), "map[a:(1 20 3) b:(40)]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := root.String(), "map[a:(1 2 3) b:(4)]"; got != want {
+		t.Fatalf("got original %s, want %s", got, want)
+	}
+}
+
+func TestZipperInterfaces(t *testing.T) {
+	var root = maps.New[string, any]().Assoc("users", vectors.New[any](
+		maps.New[string, any]().Assoc("name", "ann"),
+		"unknown",
+	))
+
+	var loc = zipper.New(root)
+	loc = must(loc.Down())(t)
+	loc = must(loc.Down())(t)
+	loc = must(loc.Right())(t)
+	if _, ok := loc.Down(); ok {
+		t.Fatalf("got true moving down from a leaf, want false")
+	}
+	loc = must(loc.Left())(t)
+	loc = must(loc.Down())(t)
+	loc, _ = loc.Replace("bob")
+
+	var got = loc.Root().(maps.Map[string, any])
+	var users = got.Get("users").(vectors.Vector[any])
+	if got, want := users.Nth(0).(maps.Map[string, any]).Get("name"), "bob"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := root.Get("users").(vectors.Vector[any]).Nth(0).(maps.Map[string, any]).Get("name"), "ann"; got != want {
+		t.Fatalf("got original %v, want %v", got, want)
+	}
+}
+
+func TestZipperBounds(t *testing.T) {
+	var loc = zipper.New(vectors.New(1, 2))
+	if _, ok := loc.Up(); ok {
+		t.Fatalf("got true moving up from the root, want false")
+	}
+	if _, ok := loc.Left(); ok {
+		t.Fatalf("got true moving left from the root, want false")
+	}
+	if _, ok := loc.Key(); ok {
+		t.Fatalf("got a key at the root, want none")
+	}
+	loc = must(loc.Down())(t)
+	if _, ok := loc.Left(); ok {
+		t.Fatalf("got true moving left from the first child, want false")
+	}
+	loc = must(loc.Right())(t)
+	if _, ok := loc.Right(); ok {
+		t.Fatalf("got true moving right from the last child, want false")
+	}
+	if _, ok := zipper.New(vectors.New[int]()).Down(); ok {
+		t.Fatalf("got true moving down into an empty vector, want false")
+	}
+}
+
+func TestZipperReplaceWrongType(t *testing.T) {
+	var loc = must(zipper.New(vectors.New(1, 2)).Down())(t)
+	if _, err := loc.Replace("one"); err == nil {
+		t.Fatalf("got nil error, want an error")
+	}
+	if _, err := loc.Replace(nil); err == nil {
+		t.Fatalf("got nil error, want an error")
+	}
+}